@@ -27,10 +27,12 @@ import (
 // ComedianAPI struct used to handle slack requests (slash commands)
 type ComedianAPI struct {
 	echo   *echo.Echo
-	db     *storage.DB
+	db     storage.Store
 	config *config.Config
 	bundle *i18n.Bundle
 	bots   []*botuser.Bot
+
+	sessionSecret []byte
 }
 
 type swagger struct {
@@ -66,10 +68,9 @@ type teamMember struct {
 }
 
 var echoRouteRegex = regexp.MustCompile(`(?P<start>.*):(?P<param>[^\/]*)(?P<end>.*)`)
-var dbService *storage.DB
 
 //New creates API instance
-func New(config *config.Config, db *storage.DB, bundle *i18n.Bundle) *ComedianAPI {
+func New(config *config.Config, db storage.Store, bundle *i18n.Bundle) *ComedianAPI {
 
 	echo := echo.New()
 	echo.Use(middleware.CORS())
@@ -78,30 +79,33 @@ func New(config *config.Config, db *storage.DB, bundle *i18n.Bundle) *ComedianAP
 		Format: "method:${method}, uri:${uri}, status:${status}\n",
 	}))
 
-	dbService = db
-
 	api := ComedianAPI{
 		echo:   echo,
 		db:     db,
 		config: config,
 		bots:   []*botuser.Bot{},
 		bundle: bundle,
+
+		sessionSecret: sessionSecret(config.JWTSecret),
 	}
 
 	echo.GET("/healthcheck", api.healthcheck)
 	echo.POST("/login", api.login)
-	echo.POST("/event", api.handleEvent)
+	echo.POST("/event", api.handleEvent, api.verifySlackRequest)
 	echo.POST("/service-message", api.handleServiceMessage)
-	echo.POST("/commands", api.handleCommands)
-	echo.POST("/team-worklogs", api.showTeamWorklogs)
-	echo.POST("/user-commands", api.handleUsersCommands)
+	echo.POST("/commands", api.handleCommands, api.verifySlackRequest)
+	echo.POST("/team-worklogs", api.showTeamWorklogs, api.verifySlackRequest)
+	echo.POST("/user-commands", api.handleUsersCommands, api.verifySlackRequest)
+	echo.POST("/interactive", api.handleInteractive, api.verifySlackRequest)
 	echo.GET("/auth", api.auth)
+	echo.POST("/mattermost/commands", api.handleMattermostCommands)
+	echo.POST("/mattermost/messages", api.handleMattermostMessages)
 
 	g := echo.Group("/v1")
-	g.Use(AuthPreRequest)
+	g.Use(api.AuthPreRequest)
 
 	g.GET("/bots/:id", api.getBot)
-	g.PATCH("/bots/:id", api.updateBot)
+	g.PATCH("/bots/:id", api.updateBot, requireAdmin)
 
 	g.GET("/standups", api.listStandups)
 	g.GET("/standups/:id", api.getStandup)
@@ -116,27 +120,34 @@ func New(config *config.Config, db *storage.DB, bundle *i18n.Bundle) *ComedianAP
 	g.PATCH("/standupers/:id", api.updateStanduper)
 	g.DELETE("/standupers/:id", api.deleteStanduper)
 
-	return &api
-}
+	g.GET("/blockers", api.listBlockers)
+	g.GET("/blockers/:id", api.getBlocker)
+	g.PATCH("/blockers/:id", api.updateBlocker)
 
-// AuthPreRequest is the middleware function.
-func AuthPreRequest(next echo.HandlerFunc) echo.HandlerFunc {
-	return func(c echo.Context) error {
+	g.GET("/absences", api.listAbsences)
+	g.POST("/absences", api.createAbsence)
+	g.PATCH("/absences/:id", api.updateAbsence)
+	g.DELETE("/absences/:id", api.deleteAbsence)
 
-		accessToken := c.Request().Header.Get(echo.HeaderAuthorization)
-		if accessToken == "" {
-			return echo.NewHTTPError(http.StatusUnauthorized, "Missing or incorrect Bot Access Token")
-		}
+	g.GET("/holidays", api.listHolidays)
+	g.POST("/holidays", api.createHoliday, requireAdmin)
+	g.POST("/holidays/import", api.importHolidays, requireAdmin)
+	g.DELETE("/holidays/:id", api.deleteHoliday, requireAdmin)
 
-		bot, err := dbService.GetWorkspaceByBotAccessToken(accessToken)
-		if err != nil {
-			return echo.NewHTTPError(http.StatusUnauthorized, "Missing or incorrect Bot Access Token")
-		}
+	g.GET("/admins", api.listAdmins)
+	g.POST("/admins", api.createAdmin, requireAdmin)
+	g.DELETE("/admins/:id", api.deleteAdmin, requireAdmin)
 
-		c.Set("teamID", bot.WorkspaceID)
+	g.GET("/webhooks", api.listWebhooks, requireAdmin)
+	g.POST("/webhooks", api.createWebhook, requireAdmin)
+	g.PATCH("/webhooks/:id", api.updateWebhook, requireAdmin)
+	g.DELETE("/webhooks/:id", api.deleteWebhook, requireAdmin)
+	g.GET("/webhooks/:id/deliveries", api.listWebhookDeliveries, requireAdmin)
 
-		return next(c)
-	}
+	g.GET("/reports/daily", api.dailyReport)
+	g.GET("/reports/weekly", api.weeklyReport)
+
+	return &api
 }
 
 //SelectBot returns bot by its team id or teamname if found
@@ -166,17 +177,39 @@ func (api *ComedianAPI) removeBot(team string) {
 // Start starts http server
 func (api *ComedianAPI) Start() error {
 
+	// Mattermost being down should not keep Slack and Telegram workspaces down
+	mattermostReady := true
+	err := api.setupMattermost()
+	if err != nil {
+		log.Error("could not set up Mattermost, retrying later: ", err)
+		mattermostReady = false
+	}
+
+	err = api.setupTelegram()
+	if err != nil {
+		return err
+	}
+
 	settings, err := api.db.GetAllWorkspaces()
 	if err != nil {
 		return err
 	}
 
 	for _, bs := range settings {
+		err := api.seedAdmins(bs.WorkspaceID, initialAdmins(api.config.Admins, bs.WorkspaceID)...)
+		if err != nil {
+			log.Errorf("failed to add admins of workspace %v: %v", bs.WorkspaceID, err)
+		}
+
 		bot := botuser.New(api.config, api.bundle, bs, api.db)
 		api.bots = append(api.bots, bot)
 		bot.Start()
 	}
 
+	if !mattermostReady {
+		go api.retryMattermostSetup()
+	}
+
 	return api.echo.Start(api.config.HTTPBindAddr)
 }
 
@@ -224,10 +257,25 @@ func (api *ComedianAPI) login(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
+	now := time.Now()
+	expiresAt := now.Add(api.config.SessionTTL).Unix()
+	token, err := issueSession(api.sessionSecret, sessionClaims{
+		UserID:      user.ID,
+		WorkspaceID: bot.WorkspaceID,
+		IssuedAt:    now.Unix(),
+		ExpiresAt:   expiresAt,
+	})
+	if err != nil {
+		log.Errorf("issueSession failed: %v for user %v", err, user.ID)
+		return echo.NewHTTPError(http.StatusInternalServerError, somethingWentWrong)
+	}
+
 	return c.JSON(http.StatusOK, map[string]interface{}{
-		"user":      user,
-		"channels:": channels,
-		"bot":       bot,
+		"token":      token,
+		"expires_at": expiresAt,
+		"user":       user,
+		"channels:":  channels,
+		"bot":        bot,
 	})
 }
 
@@ -244,10 +292,6 @@ func (api *ComedianAPI) handleEvent(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	if incomingEvent.Token != api.config.SlackVerificationToken {
-		return echo.NewHTTPError(http.StatusUnauthorized, "verification token does not match")
-	}
-
 	if incomingEvent.Type == slackevents.URLVerification {
 		return c.JSON(http.StatusOK, incomingEvent.Challenge)
 	}
@@ -296,10 +340,6 @@ func (api *ComedianAPI) handleCommands(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	if !slashCommand.ValidateToken(api.config.SlackVerificationToken) {
-		return echo.NewHTTPError(http.StatusBadRequest, "wrong verification token")
-	}
-
 	bot, err := api.SelectBot(slashCommand.TeamID)
 	if err != nil {
 		log.WithFields(log.Fields{
@@ -321,21 +361,15 @@ func (api *ComedianAPI) handleUsersCommands(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	if !slashCommand.ValidateToken(api.config.SlackVerificationToken) {
-		return c.JSON(http.StatusBadRequest, "Invalid verification token")
-	}
-
 	bot, err := api.SelectBot(slashCommand.TeamID)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
 
 	today := time.Now()
-	dateFrom := fmt.Sprintf("%d-%02d-%02d", today.Year(), today.Month(), 1)
-	dateTo := fmt.Sprintf("%d-%02d-%02d", today.Year(), today.Month(), today.Day())
-	dataOnUser, err := bot.GetCollectorData("users", slashCommand.UserID, dateFrom, dateTo)
+	dataOnUser, err := bot.UserWork(slashCommand.UserID, time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.Local), today)
 	if err != nil {
-		return c.JSON(http.StatusOK, "Failed to get data from Collector. Make sure you were added to Collector database and try again")
+		return c.JSON(http.StatusOK, "Failed to get your worklogs. Make sure work source of the workspace knows your account and try again")
 	}
 
 	message := fmt.Sprintf("You have logged %v from the begining of the month", botuser.SecondsToHuman(dataOnUser.Worklogs))
@@ -407,10 +441,6 @@ func (api *ComedianAPI) showTeamWorklogs(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	if !slashCommand.ValidateToken(api.config.SlackVerificationToken) {
-		return c.JSON(http.StatusBadRequest, "Invalid verification token")
-	}
-
 	bot, err := api.SelectBot(slashCommand.TeamID)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
@@ -457,8 +487,7 @@ func (api *ComedianAPI) showTeamWorklogs(c echo.Context) error {
 	members := []teamMember{}
 
 	for _, standuper := range standupers {
-		userInProject := fmt.Sprintf("%v/%v", standuper.UserID, standuper.ChannelName)
-		dataOnUserInProject, err := bot.GetCollectorData("user-in-project", userInProject, dateFrom, dateTo)
+		dataOnUserInProject, err := bot.ProjectWork(standuper, from, to)
 		if err != nil {
 
 			continue
@@ -519,6 +548,12 @@ func (api *ComedianAPI) auth(c echo.Context) error {
 			return err
 		}
 
+		// user who installed Comedian becomes the first admin of the workspace
+		err = api.seedAdmins(cp.WorkspaceID, resp.UserID)
+		if err != nil {
+			log.WithFields(log.Fields(map[string]interface{}{"resp": resp, "error": err})).Error("auth failed on seedAdmins")
+		}
+
 		bot := botuser.New(api.config, api.bundle, cp, api.db)
 
 		api.bots = append(api.bots, bot)
@@ -537,6 +572,11 @@ func (api *ComedianAPI) auth(c echo.Context) error {
 		return err
 	}
 
+	err = api.seedAdmins(settings.WorkspaceID, resp.UserID)
+	if err != nil {
+		log.WithFields(log.Fields(map[string]interface{}{"resp": resp, "error": err})).Error("auth failed on seedAdmins")
+	}
+
 	bot, err := api.SelectBot(resp.TeamID)
 	if err != nil {
 		log.Error(err)
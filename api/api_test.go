@@ -17,9 +17,7 @@ func TestSwaggerRoutesExistInEcho(t *testing.T) {
 	assert.NoError(t, err)
 	sw, err := getSwagger()
 	assert.NoError(t, err)
-	db, err := storage.New(c.DatabaseURL, "../migrations")
-	assert.NoError(t, err)
-	api := New(c, db, nil)
+	api := New(c, storage.NewMemory(), nil)
 	routes := api.echo.Routes()
 
 	for k, v := range sw.Paths {
@@ -3,8 +3,12 @@ package api
 import (
 	"net/http"
 	"strconv"
+	"time"
 
+	"github.com/araddon/dateparse"
 	"github.com/labstack/echo"
+	"github.com/maddevsio/comedian/calendar"
+	"github.com/maddevsio/comedian/model"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -14,6 +18,8 @@ var (
 	doesNotExist        = "Entity does not yet exist"
 	incorrectDataFormat = "Incorrect data format, double check request body"
 	somethingWentWrong  = "Something went wrong"
+	incorrectDate       = "Incorrect date format, use YYYY-MM-DD"
+	incorrectCalendar   = "Incorrect calendar, iCalendar (.ics) file expected"
 )
 
 func (api *ComedianAPI) getBot(c echo.Context) error {
@@ -60,7 +66,16 @@ func (api *ComedianAPI) updateBot(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusForbidden, accessDenied)
 	}
 
-	if err := c.Bind(&settings); err != nil {
+	// work source token is never shown, so it is accepted next to settings.
+	// Scoring rules sent replace stored ones instead of being merged into them
+	stored := settings
+	payload := struct {
+		*model.Workspace
+		WorkSourceToken *string             `json:"work_source_token"`
+		ScoringRules    *model.ScoringRules `json:"scoring_rules"`
+	}{Workspace: &settings}
+
+	if err := c.Bind(&payload); err != nil {
 		log.WithFields(log.Fields{
 			"error":    err,
 			"fucntion": "c.Bind(&settings)",
@@ -68,6 +83,22 @@ func (api *ComedianAPI) updateBot(c echo.Context) error {
 		).Error("updateBot failed")
 		return echo.NewHTTPError(http.StatusBadRequest, incorrectDataFormat)
 	}
+	// installation of the bot is not a setting, it never comes from the request
+	settings.ID = stored.ID
+	settings.CreatedAt = stored.CreatedAt
+	settings.WorkspaceID = stored.WorkspaceID
+	settings.WorkspaceName = stored.WorkspaceName
+	settings.BotUserID = stored.BotUserID
+	settings.BotAccessToken = stored.BotAccessToken
+	settings.BotTokenHash = stored.BotTokenHash
+	settings.Platform = stored.Platform
+	settings.WorkSourceToken = stored.WorkSourceToken
+	if payload.WorkSourceToken != nil {
+		settings.WorkSourceToken = *payload.WorkSourceToken
+	}
+	if payload.ScoringRules != nil {
+		settings.ScoringRules = *payload.ScoringRules
+	}
 
 	res, err := api.db.UpdateWorkspace(settings)
 	if err != nil {
@@ -117,10 +148,65 @@ func (api *ComedianAPI) getStandup(c echo.Context) error {
 }
 
 func (api *ComedianAPI) listStandups(c echo.Context) error {
+	visible, err := api.visibleProjects(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, somethingWentWrong)
+	}
+
+	if c.QueryParam("from") == "" && c.QueryParam("to") == "" && c.QueryParam("channel_id") == "" && c.QueryParam("has_blockers") == "" {
+		items, err := api.db.ListTeamStandups(c.Get("teamID").(string))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, somethingWentWrong)
+		}
+
+		standups := []model.Standup{}
+		for _, standup := range items {
+			if visible(standup.ChannelID) {
+				standups = append(standups, standup)
+			}
+		}
 
-	standups, err := api.db.ListTeamStandups(c.Get("teamID").(string))
+		return c.JSON(http.StatusOK, map[string]interface{}{"standups": standups})
+	}
+
+	from := time.Unix(0, 0)
+	to := time.Now()
+
+	if c.QueryParam("from") != "" {
+		from, err = dateparse.ParseIn(c.QueryParam("from"), time.Local)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, incorrectDate)
+		}
+	}
+
+	if c.QueryParam("to") != "" {
+		to, err = dateparse.ParseIn(c.QueryParam("to"), time.Local)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, incorrectDate)
+		}
+		//date without time includes the whole day
+		if to.Hour() == 0 && to.Minute() == 0 && to.Second() == 0 {
+			to = to.AddDate(0, 0, 1).Add(-time.Second)
+		}
+	}
+
+	items, err := api.db.ListTeamStandupsForPeriod(c.Get("teamID").(string), from.Unix(), to.Unix())
 	if err != nil {
-		echo.NewHTTPError(http.StatusUnauthorized, somethingWentWrong)
+		return echo.NewHTTPError(http.StatusInternalServerError, somethingWentWrong)
+	}
+
+	standups := []model.Standup{}
+	for _, standup := range items {
+		if !visible(standup.ChannelID) {
+			continue
+		}
+		if c.QueryParam("channel_id") != "" && standup.ChannelID != c.QueryParam("channel_id") {
+			continue
+		}
+		if c.QueryParam("has_blockers") == "true" && standup.Blockers == "" {
+			continue
+		}
+		standups = append(standups, standup)
 	}
 
 	return c.JSON(http.StatusOK, map[string]interface{}{"standups": standups})
@@ -137,14 +223,24 @@ func (api *ComedianAPI) updateStandup(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusNotFound, doesNotExist)
 	}
 
-	if err := c.Bind(&standup); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, incorrectDataFormat)
-	}
-
 	if standup.WorkspaceID != c.Get("teamID") {
 		return echo.NewHTTPError(http.StatusUnauthorized, accessDenied)
 	}
 
+	if !api.canModify(c, standup.UserID, standup.ChannelID) {
+		return echo.NewHTTPError(http.StatusForbidden, ownerOnly)
+	}
+
+	stored := standup
+	if err := c.Bind(&standup); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, incorrectDataFormat)
+	}
+	standup.ID = stored.ID
+	standup.CreatedAt = stored.CreatedAt
+	standup.WorkspaceID = stored.WorkspaceID
+	standup.ChannelID = stored.ChannelID
+	standup.UserID = stored.UserID
+
 	standup, err = api.db.UpdateStandup(standup)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
@@ -168,9 +264,13 @@ func (api *ComedianAPI) deleteStandup(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusUnauthorized, accessDenied)
 	}
 
+	if !api.canModify(c, standup.UserID, standup.ChannelID) {
+		return echo.NewHTTPError(http.StatusForbidden, ownerOnly)
+	}
+
 	err = api.db.DeleteStandup(id)
 	if err != nil {
-		echo.NewHTTPError(http.StatusInternalServerError, somethingWentWrong)
+		return echo.NewHTTPError(http.StatusInternalServerError, somethingWentWrong)
 	}
 
 	return c.JSON(http.StatusNoContent, "")
@@ -180,7 +280,7 @@ func (api *ComedianAPI) listChannels(c echo.Context) error {
 
 	channels, err := api.db.ListWorkspaceProjects(c.Get("teamID").(string))
 	if err != nil {
-		echo.NewHTTPError(http.StatusInternalServerError, somethingWentWrong)
+		return echo.NewHTTPError(http.StatusInternalServerError, somethingWentWrong)
 	}
 
 	return c.JSON(http.StatusOK, map[string]interface{}{"channels": channels})
@@ -197,14 +297,23 @@ func (api *ComedianAPI) updateChannel(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusNotFound, doesNotExist)
 	}
 
-	if err := c.Bind(&channel); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, incorrectDataFormat)
-	}
-
 	if channel.WorkspaceID != c.Get("teamID") {
 		return echo.NewHTTPError(http.StatusUnauthorized, accessDenied)
 	}
 
+	if !api.canManageProject(c, channel.ChannelID) {
+		return echo.NewHTTPError(http.StatusForbidden, pmOrAdminOnly)
+	}
+
+	stored := channel
+	if err := c.Bind(&channel); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, incorrectDataFormat)
+	}
+	channel.ID = stored.ID
+	channel.CreatedAt = stored.CreatedAt
+	channel.WorkspaceID = stored.WorkspaceID
+	channel.ChannelID = stored.ChannelID
+
 	channel, err = api.db.UpdateProject(channel)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
@@ -228,6 +337,10 @@ func (api *ComedianAPI) deleteChannel(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusUnauthorized, accessDenied)
 	}
 
+	if !api.canManageProject(c, channel.ChannelID) {
+		return echo.NewHTTPError(http.StatusForbidden, pmOrAdminOnly)
+	}
+
 	err = api.db.DeleteProject(id)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, somethingWentWrong)
@@ -237,12 +350,23 @@ func (api *ComedianAPI) deleteChannel(c echo.Context) error {
 }
 
 func (api *ComedianAPI) listStandupers(c echo.Context) error {
+	visible, err := api.visibleProjects(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, somethingWentWrong)
+	}
 
-	standupers, err := api.db.ListWorkspaceStandupers(c.Get("teamID").(string))
+	items, err := api.db.ListWorkspaceStandupers(c.Get("teamID").(string))
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, somethingWentWrong)
 	}
 
+	standupers := []model.Standuper{}
+	for _, standuper := range items {
+		if visible(standuper.ChannelID) {
+			standupers = append(standupers, standuper)
+		}
+	}
+
 	return c.JSON(http.StatusOK, map[string]interface{}{"standupers": standupers})
 }
 
@@ -257,14 +381,24 @@ func (api *ComedianAPI) updateStanduper(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusNotFound, doesNotExist)
 	}
 
-	if err := c.Bind(&standuper); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, incorrectDataFormat)
-	}
-
 	if standuper.WorkspaceID != c.Get("teamID") {
 		return echo.NewHTTPError(http.StatusUnauthorized, accessDenied)
 	}
 
+	if !api.canManageProject(c, standuper.ChannelID) {
+		return echo.NewHTTPError(http.StatusForbidden, pmOrAdminOnly)
+	}
+
+	stored := standuper
+	if err := c.Bind(&standuper); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, incorrectDataFormat)
+	}
+	standuper.ID = stored.ID
+	standuper.CreatedAt = stored.CreatedAt
+	standuper.WorkspaceID = stored.WorkspaceID
+	standuper.ChannelID = stored.ChannelID
+	standuper.UserID = stored.UserID
+
 	standuper, err = api.db.UpdateStanduper(standuper)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
@@ -288,6 +422,10 @@ func (api *ComedianAPI) deleteStanduper(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusUnauthorized, accessDenied)
 	}
 
+	if !api.canManageProject(c, standuper.ChannelID) {
+		return echo.NewHTTPError(http.StatusForbidden, pmOrAdminOnly)
+	}
+
 	err = api.db.DeleteStanduper(id)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, somethingWentWrong)
@@ -295,3 +433,348 @@ func (api *ComedianAPI) deleteStanduper(c echo.Context) error {
 
 	return c.JSON(http.StatusNoContent, "")
 }
+
+func (api *ComedianAPI) listBlockers(c echo.Context) error {
+	visible, err := api.visibleProjects(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, somethingWentWrong)
+	}
+
+	items, err := api.db.ListTeamBlockers(c.Get("teamID").(string))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, somethingWentWrong)
+	}
+
+	blockers := []model.Blocker{}
+	for _, blocker := range items {
+		if !visible(blocker.ChannelID) {
+			continue
+		}
+		if c.QueryParam("channel_id") != "" && blocker.ChannelID != c.QueryParam("channel_id") {
+			continue
+		}
+		if c.QueryParam("resolved") != "" && strconv.FormatBool(blocker.Resolved) != c.QueryParam("resolved") {
+			continue
+		}
+		blockers = append(blockers, blocker)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"blockers": blockers})
+}
+
+func (api *ComedianAPI) getBlocker(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 0, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, incorrectID)
+	}
+
+	blocker, err := api.db.GetBlocker(id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, doesNotExist)
+	}
+
+	if blocker.WorkspaceID != c.Get("teamID") {
+		return echo.NewHTTPError(http.StatusUnauthorized, accessDenied)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"blocker": blocker})
+}
+
+func (api *ComedianAPI) updateBlocker(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 0, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, incorrectID)
+	}
+
+	blocker, err := api.db.GetBlocker(id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, doesNotExist)
+	}
+
+	if blocker.WorkspaceID != c.Get("teamID") {
+		return echo.NewHTTPError(http.StatusUnauthorized, accessDenied)
+	}
+
+	if !api.canModify(c, blocker.UserID, blocker.ChannelID) {
+		return echo.NewHTTPError(http.StatusForbidden, ownerOnly)
+	}
+
+	stored := blocker
+	if err := c.Bind(&blocker); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, incorrectDataFormat)
+	}
+	blocker.ID = stored.ID
+	blocker.CreatedAt = stored.CreatedAt
+	blocker.WorkspaceID = stored.WorkspaceID
+	blocker.ChannelID = stored.ChannelID
+	blocker.UserID = stored.UserID
+	blocker.StandupID = stored.StandupID
+
+	if blocker.Resolved && !stored.Resolved && blocker.ResolvedAt == 0 {
+		blocker.ResolvedAt = time.Now().Unix()
+	}
+	if !blocker.Resolved {
+		blocker.ResolvedAt = 0
+		blocker.ResolvedBy = ""
+	}
+
+	blocker, err = api.db.UpdateBlocker(blocker)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"blocker": blocker})
+}
+
+func (api *ComedianAPI) listAbsences(c echo.Context) error {
+	visible, err := api.visibleProjects(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, somethingWentWrong)
+	}
+
+	items, err := api.db.ListTeamAbsences(c.Get("teamID").(string))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, somethingWentWrong)
+	}
+
+	absences := []model.Absence{}
+	for _, absence := range items {
+		// absences in all projects are visible to admins and the absent user only
+		if absence.UserID != c.Get("userID") && !visible(absence.ChannelID) {
+			continue
+		}
+		if c.QueryParam("user_id") != "" && absence.UserID != c.QueryParam("user_id") {
+			continue
+		}
+		if c.QueryParam("channel_id") != "" && absence.ChannelID != "" && absence.ChannelID != c.QueryParam("channel_id") {
+			continue
+		}
+		absences = append(absences, absence)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"absences": absences})
+}
+
+func (api *ComedianAPI) createAbsence(c echo.Context) error {
+	var absence model.Absence
+
+	if err := c.Bind(&absence); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, incorrectDataFormat)
+	}
+
+	absence.ID = 0
+	absence.CreatedAt = time.Now().Unix()
+	absence.WorkspaceID = c.Get("teamID").(string)
+
+	if !api.canModify(c, absence.UserID, absence.ChannelID) {
+		return echo.NewHTTPError(http.StatusForbidden, ownerOnly)
+	}
+
+	absence, err := api.db.CreateAbsence(absence)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(http.StatusCreated, map[string]interface{}{"absence": absence})
+}
+
+func (api *ComedianAPI) updateAbsence(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 0, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, incorrectID)
+	}
+
+	absence, err := api.db.GetAbsence(id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, doesNotExist)
+	}
+
+	if absence.WorkspaceID != c.Get("teamID") {
+		return echo.NewHTTPError(http.StatusUnauthorized, accessDenied)
+	}
+
+	if !api.canModify(c, absence.UserID, absence.ChannelID) {
+		return echo.NewHTTPError(http.StatusForbidden, ownerOnly)
+	}
+
+	stored := absence
+	if err := c.Bind(&absence); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, incorrectDataFormat)
+	}
+	absence.ID = stored.ID
+	absence.CreatedAt = stored.CreatedAt
+	absence.WorkspaceID = stored.WorkspaceID
+	absence.UserID = stored.UserID
+
+	// absence may be moved to another project only by those allowed to manage it there
+	if !api.canModify(c, absence.UserID, absence.ChannelID) {
+		return echo.NewHTTPError(http.StatusForbidden, ownerOnly)
+	}
+
+	absence, err = api.db.UpdateAbsence(absence)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"absence": absence})
+}
+
+func (api *ComedianAPI) deleteAbsence(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 0, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, incorrectID)
+	}
+
+	absence, err := api.db.GetAbsence(id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, doesNotExist)
+	}
+
+	if absence.WorkspaceID != c.Get("teamID") {
+		return echo.NewHTTPError(http.StatusUnauthorized, accessDenied)
+	}
+
+	if !api.canModify(c, absence.UserID, absence.ChannelID) {
+		return echo.NewHTTPError(http.StatusForbidden, ownerOnly)
+	}
+
+	err = api.db.DeleteAbsence(id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, somethingWentWrong)
+	}
+
+	return c.JSON(http.StatusNoContent, "")
+}
+
+func (api *ComedianAPI) listHolidays(c echo.Context) error {
+	items, err := api.db.ListTeamHolidays(c.Get("teamID").(string))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, somethingWentWrong)
+	}
+
+	holidays := []model.Holiday{}
+	for _, holiday := range items {
+		if c.QueryParam("channel_id") != "" && holiday.ChannelID != "" && holiday.ChannelID != c.QueryParam("channel_id") {
+			continue
+		}
+		holidays = append(holidays, holiday)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"holidays": holidays})
+}
+
+func (api *ComedianAPI) createHoliday(c echo.Context) error {
+	var holiday model.Holiday
+
+	if err := c.Bind(&holiday); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, incorrectDataFormat)
+	}
+
+	holiday.ID = 0
+	holiday.CreatedAt = time.Now().Unix()
+	holiday.WorkspaceID = c.Get("teamID").(string)
+
+	if err := api.checkHolidayChannel(c, holiday.ChannelID); err != nil {
+		return err
+	}
+
+	holiday, err := api.db.CreateHoliday(holiday)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(http.StatusCreated, map[string]interface{}{"holiday": holiday})
+}
+
+// importHolidays creates holidays from iCalendar file sent as request body.
+// Holidays are attached to the channel from channel_id query param or to the whole workspace
+func (api *ComedianAPI) importHolidays(c echo.Context) error {
+	teamID := c.Get("teamID").(string)
+	channelID := c.QueryParam("channel_id")
+
+	err := api.checkHolidayChannel(c, channelID)
+	if err != nil {
+		return err
+	}
+
+	events, err := calendar.ParseICS(c.Request().Body)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, incorrectCalendar)
+	}
+
+	existing, err := api.db.ListTeamHolidays(teamID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, somethingWentWrong)
+	}
+
+	known := map[string]bool{}
+	for _, holiday := range existing {
+		if holiday.ChannelID == channelID {
+			known[holiday.Date] = true
+		}
+	}
+
+	holidays := []model.Holiday{}
+	for _, event := range events {
+		for _, date := range event.Dates() {
+			if known[date] {
+				continue
+			}
+			holiday, err := api.db.CreateHoliday(model.Holiday{
+				CreatedAt:   time.Now().Unix(),
+				WorkspaceID: teamID,
+				ChannelID:   channelID,
+				Date:        date,
+				Name:        event.Summary,
+			})
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, somethingWentWrong)
+			}
+			known[date] = true
+			holidays = append(holidays, holiday)
+		}
+	}
+
+	return c.JSON(http.StatusCreated, map[string]interface{}{"holidays": holidays})
+}
+
+// checkHolidayChannel lets holidays be attached only to projects of the caller's
+// workspace, holidays without channel are holidays of the whole workspace
+func (api *ComedianAPI) checkHolidayChannel(c echo.Context, channelID string) error {
+	if channelID == "" {
+		return nil
+	}
+
+	project, err := api.db.SelectProject(channelID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, doesNotExist)
+	}
+
+	if project.WorkspaceID != c.Get("teamID") {
+		return echo.NewHTTPError(http.StatusUnauthorized, accessDenied)
+	}
+	return nil
+}
+
+func (api *ComedianAPI) deleteHoliday(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 0, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, incorrectID)
+	}
+
+	holiday, err := api.db.GetHoliday(id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, doesNotExist)
+	}
+
+	if holiday.WorkspaceID != c.Get("teamID") {
+		return echo.NewHTTPError(http.StatusUnauthorized, accessDenied)
+	}
+
+	err = api.db.DeleteHoliday(id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, somethingWentWrong)
+	}
+
+	return c.JSON(http.StatusNoContent, "")
+}
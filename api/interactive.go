@@ -0,0 +1,70 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/labstack/echo"
+	"github.com/maddevsio/comedian/botuser"
+	log "github.com/sirupsen/logrus"
+)
+
+// interactionPayload is a part of Slack interaction payload Comedian uses.
+// Slack client of the project does not know about views, so they are decoded here
+type interactionPayload struct {
+	Type string `json:"type"`
+	Team struct {
+		ID string `json:"id"`
+	} `json:"team"`
+	User struct {
+		ID string `json:"id"`
+	} `json:"user"`
+	View struct {
+		ID              string `json:"id"`
+		CallbackID      string `json:"callback_id"`
+		PrivateMetadata string `json:"private_metadata"`
+		State           struct {
+			Values map[string]map[string]struct {
+				Value string `json:"value"`
+			} `json:"values"`
+		} `json:"state"`
+	} `json:"view"`
+}
+
+func (api *ComedianAPI) handleInteractive(c echo.Context) error {
+	var payload interactionPayload
+	err := json.Unmarshal([]byte(c.FormValue("payload")), &payload)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, incorrectDataFormat)
+	}
+
+	if payload.Type != "view_submission" || payload.View.CallbackID != botuser.StandupModalCallbackID {
+		return c.NoContent(http.StatusOK)
+	}
+
+	bot, err := api.SelectBot(payload.Team.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	values := map[string]string{}
+	for blockID, actions := range payload.View.State.Values {
+		values[blockID] = actions[botuser.StandupModalActionID].Value
+	}
+
+	fieldErrors, err := bot.HandleStandupSubmission(payload.View.ID, payload.View.PrivateMetadata, payload.User.ID, values)
+	if err != nil {
+		log.Error("HandleStandupSubmission failed: ", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	if len(fieldErrors) > 0 {
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"response_action": "errors",
+			"errors":          fieldErrors,
+		})
+	}
+
+	// empty response closes the modal
+	return c.NoContent(http.StatusOK)
+}
@@ -0,0 +1,199 @@
+package api
+
+import (
+	"database/sql"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/maddevsio/comedian/botuser"
+	"github.com/maddevsio/comedian/messenger"
+	"github.com/maddevsio/comedian/model"
+	"github.com/nlopes/slack"
+	log "github.com/sirupsen/logrus"
+)
+
+// mattermostRetryInterval is a pause between attempts to set up unreachable Mattermost
+const mattermostRetryInterval = time.Minute
+
+// mattermostWebhook is a payload of Mattermost outgoing webhook
+type mattermostWebhook struct {
+	Token     string `json:"token" form:"token"`
+	TeamID    string `json:"team_id" form:"team_id"`
+	ChannelID string `json:"channel_id" form:"channel_id"`
+	UserID    string `json:"user_id" form:"user_id"`
+	PostID    string `json:"post_id" form:"post_id"`
+	Text      string `json:"text" form:"text"`
+}
+
+// setupMattermost creates or updates workspace of Mattermost team configured with env variables
+func (api *ComedianAPI) setupMattermost() error {
+	if api.config.MattermostURL == "" {
+		return nil
+	}
+
+	chat := messenger.NewMattermost(api.config.MattermostURL, api.config.MattermostBotToken, api.config.MattermostTeamID)
+
+	me, err := chat.Me()
+	if err != nil {
+		return err
+	}
+
+	teamName, err := chat.TeamName()
+	if err != nil {
+		return err
+	}
+
+	workspace, err := api.db.GetWorkspaceByWorkspaceID(api.config.MattermostTeamID)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			return err
+		}
+		// bot user ID is the username, since Mattermost mentions bot by it
+		_, err = api.db.CreateWorkspace(model.Workspace{
+			CreatedAt:              time.Now().Unix(),
+			BotUserID:              me.Name,
+			NotifierInterval:       30,
+			Language:               "en",
+			MaxReminders:           3,
+			ReminderOffset:         10,
+			BotAccessToken:         api.config.MattermostBotToken,
+			WorkspaceID:            api.config.MattermostTeamID,
+			WorkspaceName:          teamName,
+			ReportingChannel:       "",
+			ReportingTime:          "10am",
+			ProjectsReportsEnabled: false,
+			Platform:               model.PlatformMattermost,
+		})
+		return err
+	}
+
+	workspace.BotUserID = me.Name
+	workspace.BotAccessToken = api.config.MattermostBotToken
+	workspace.Platform = model.PlatformMattermost
+
+	_, err = api.db.UpdateWorkspace(workspace)
+	return err
+}
+
+// retryMattermostSetup sets up Mattermost workspace once Mattermost is reachable
+// and starts its bot or updates the one started from stored settings
+func (api *ComedianAPI) retryMattermostSetup() {
+	for {
+		time.Sleep(mattermostRetryInterval)
+
+		err := api.setupMattermost()
+		if err != nil {
+			log.Error("could not set up Mattermost, retrying later: ", err)
+			continue
+		}
+
+		workspace, err := api.db.GetWorkspaceByWorkspaceID(api.config.MattermostTeamID)
+		if err != nil {
+			log.Error("GetWorkspaceByWorkspaceID failed: ", err)
+			return
+		}
+
+		bot, err := api.SelectBot(workspace.WorkspaceID)
+		if err == nil {
+			bot.SetProperties(&workspace)
+			return
+		}
+
+		bot = botuser.New(api.config, api.bundle, workspace, api.db)
+		api.bots = append(api.bots, bot)
+		bot.Start()
+		return
+	}
+}
+
+func (api *ComedianAPI) validMattermostToken(token string) bool {
+	if token == "" {
+		return false
+	}
+	for _, t := range strings.Split(api.config.MattermostTokens, ",") {
+		if strings.TrimSpace(t) == token {
+			return true
+		}
+	}
+	return false
+}
+
+func (api *ComedianAPI) handleMattermostCommands(c echo.Context) error {
+	slashCommand, err := slack.SlashCommandParse(c.Request())
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if !api.validMattermostToken(slashCommand.Token) {
+		return echo.NewHTTPError(http.StatusBadRequest, "wrong verification token")
+	}
+
+	bot, err := api.SelectBot(slashCommand.TeamID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	api.ensureProject(bot, slashCommand.TeamID, slashCommand.ChannelID)
+
+	message := bot.ImplementCommands(slashCommand)
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"response_type": "ephemeral",
+		"text":          message,
+	})
+}
+
+func (api *ComedianAPI) handleMattermostMessages(c echo.Context) error {
+	webhook := mattermostWebhook{}
+	if err := c.Bind(&webhook); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, incorrectDataFormat)
+	}
+
+	if !api.validMattermostToken(webhook.Token) {
+		return echo.NewHTTPError(http.StatusBadRequest, "wrong verification token")
+	}
+
+	bot, err := api.SelectBot(webhook.TeamID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	api.ensureProject(bot, webhook.TeamID, webhook.ChannelID)
+
+	// outgoing webhooks deliver new posts only, edits and deletions are not tracked
+	message := &slack.MessageEvent{
+		Msg: slack.Msg{
+			Type:      "message",
+			Channel:   webhook.ChannelID,
+			User:      webhook.UserID,
+			Text:      webhook.Text,
+			Timestamp: webhook.PostID,
+		},
+	}
+
+	err = bot.HandleMessage(message)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+// ensureProject creates project for the channel, since Mattermost does not
+// notify bot when it is added to channel
+func (api *ComedianAPI) ensureProject(bot *botuser.Bot, teamID, channelID string) {
+	_, err := api.db.SelectProject(channelID)
+	if err == nil {
+		return
+	}
+
+	_, err = bot.HandleJoin(&slack.MemberJoinedChannelEvent{
+		Channel: channelID,
+		Team:    teamID,
+	})
+	if err != nil {
+		log.Error("could not create project for mattermost channel: ", err)
+	}
+}
@@ -0,0 +1,134 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/maddevsio/comedian/botuser"
+	log "github.com/sirupsen/logrus"
+)
+
+// maxReportDays limits period of weekly report, work source is asked about every standuper
+const maxReportDays = 31
+
+const reportDateFormat = "2006-01-02"
+
+var (
+	incorrectReportFormat = "Incorrect report format, use json, csv or markdown"
+	incorrectReportPeriod = fmt.Sprintf("Incorrect report period, 'from' must not be after 'to' and period cannot be longer than %d days", maxReportDays)
+)
+
+// dailyReport returns report on the day from date query param, yesterday by default
+func (api *ComedianAPI) dailyReport(c echo.Context) error {
+	bot, err := api.SelectBot(c.Get("teamID").(string))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, doesNotExist)
+	}
+
+	date, err := reportDate(c.QueryParam("date"), time.Now().AddDate(0, 0, -1))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, incorrectDate)
+	}
+
+	report, err := bot.DailyReport(date)
+	if err != nil {
+		log.Error("DailyReport failed: ", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, somethingWentWrong)
+	}
+
+	return renderReport(c, api.visibleReport(c, report))
+}
+
+// weeklyReport returns report on the period from from and to query params,
+// last seven days by default
+func (api *ComedianAPI) weeklyReport(c echo.Context) error {
+	bot, err := api.SelectBot(c.Get("teamID").(string))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, doesNotExist)
+	}
+
+	to, err := reportDate(c.QueryParam("to"), time.Now().AddDate(0, 0, -1))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, incorrectDate)
+	}
+	from, err := reportDate(c.QueryParam("from"), to.AddDate(0, 0, -6))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, incorrectDate)
+	}
+	if from.After(to) || to.Sub(from) >= maxReportDays*24*time.Hour {
+		return echo.NewHTTPError(http.StatusBadRequest, incorrectReportPeriod)
+	}
+
+	report, err := bot.WeeklyReport(from, to)
+	if err != nil {
+		log.Error("WeeklyReport failed: ", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, somethingWentWrong)
+	}
+
+	return renderReport(c, api.visibleReport(c, report))
+}
+
+// visibleReport leaves entries caller may see: admins see the whole workspace,
+// PMs see their projects and members see only their own work
+func (api *ComedianAPI) visibleReport(c echo.Context, report botuser.Report) botuser.Report {
+	entries := []botuser.ReportEntry{}
+	managed := map[string]bool{}
+	for _, entry := range report.Entries {
+		allowed, ok := managed[entry.ChannelID]
+		if !ok {
+			allowed = api.canManageProject(c, entry.ChannelID)
+			managed[entry.ChannelID] = allowed
+		}
+		if allowed || entry.UserID == c.Get("userID") {
+			entries = append(entries, entry)
+		}
+	}
+	report.Entries = entries
+	return report
+}
+
+func reportDate(value string, byDefault time.Time) (time.Time, error) {
+	if value == "" {
+		return byDefault, nil
+	}
+	return time.ParseInLocation(reportDateFormat, value, time.Local)
+}
+
+// renderReport writes report in format from format query param or Accept header, JSON by default
+func renderReport(c echo.Context, report botuser.Report) error {
+	format := c.QueryParam("format")
+	if format == "" {
+		accept := c.Request().Header.Get(echo.HeaderAccept)
+		switch {
+		case strings.Contains(accept, "text/csv"):
+			format = "csv"
+		case strings.Contains(accept, "text/markdown"):
+			format = "markdown"
+		default:
+			format = "json"
+		}
+	}
+
+	filename := fmt.Sprintf("comedian-%s-report-%s", report.Kind, report.To)
+
+	switch format {
+	case "json":
+		return c.JSON(http.StatusOK, map[string]interface{}{"report": report})
+	case "csv":
+		var b bytes.Buffer
+		if err := report.WriteCSV(&b); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, somethingWentWrong)
+		}
+		c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s.csv"`, filename))
+		return c.Blob(http.StatusOK, "text/csv; charset=utf-8", b.Bytes())
+	case "markdown", "md":
+		c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`inline; filename="%s.md"`, filename))
+		return c.Blob(http.StatusOK, "text/markdown; charset=utf-8", []byte(report.Markdown()))
+	}
+
+	return echo.NewHTTPError(http.StatusBadRequest, incorrectReportFormat)
+}
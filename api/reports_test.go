@@ -0,0 +1,110 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo"
+	"github.com/maddevsio/comedian/botuser"
+	"github.com/maddevsio/comedian/model"
+	"github.com/maddevsio/comedian/storage"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestReportHandlers(t *testing.T) {
+	db := storage.NewMemory()
+	_, err := db.CreateProject(model.Project{WorkspaceID: "T1", ChannelID: "C1", ChannelName: "web"})
+	require.NoError(t, err)
+	_, err = db.CreateStanduper(model.Standuper{WorkspaceID: "T1", ChannelID: "C1", UserID: "U1", RealName: "Alice"})
+	require.NoError(t, err)
+
+	bot := botuser.New(nil, i18n.NewBundle(language.English), model.Workspace{WorkspaceID: "T1", BotAccessToken: "token"}, db)
+	api := &ComedianAPI{db: db, bots: []*botuser.Bot{bot}}
+
+	request := func(target, accept string, handler echo.HandlerFunc) (*httptest.ResponseRecorder, error) {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		if accept != "" {
+			req.Header.Set(echo.HeaderAccept, accept)
+		}
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(req, rec)
+		c.Set("teamID", "T1")
+		c.Set("role", model.RoleAdmin)
+		return rec, handler(c)
+	}
+
+	rec, err := request("/v1/reports/daily?date=2019-05-07", "", api.dailyReport)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var body struct {
+		Report botuser.Report `json:"report"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, botuser.ReportDaily, body.Report.Kind)
+	assert.Equal(t, "2019-05-07", body.Report.From)
+	require.Len(t, body.Report.Entries, 1)
+	assert.Equal(t, "Alice", body.Report.Entries[0].RealName)
+
+	rec, err = request("/v1/reports/daily?date=2019-05-07", "text/csv", api.dailyReport)
+	require.NoError(t, err)
+	assert.Equal(t, "text/csv; charset=utf-8", rec.Header().Get(echo.HeaderContentType))
+	assert.Equal(t, `attachment; filename="comedian-daily-report-2019-05-07.csv"`, rec.Header().Get(echo.HeaderContentDisposition))
+	assert.True(t, strings.HasPrefix(rec.Body.String(), "channel_id,channel_name,user_id"))
+	assert.Contains(t, rec.Body.String(), "C1,web,U1,Alice")
+
+	rec, err = request("/v1/reports/weekly?from=2019-05-01&to=2019-05-07&format=markdown", "application/json", api.weeklyReport)
+	require.NoError(t, err)
+	assert.Equal(t, "text/markdown; charset=utf-8", rec.Header().Get(echo.HeaderContentType))
+	assert.True(t, strings.HasPrefix(rec.Body.String(), "# Weekly report 2019-05-01 – 2019-05-07\n"))
+	assert.Contains(t, rec.Body.String(), "| Alice |")
+
+	for _, tt := range []struct {
+		target  string
+		handler echo.HandlerFunc
+		message string
+	}{
+		{"/v1/reports/daily?date=07.05.2019", api.dailyReport, incorrectDate},
+		{"/v1/reports/daily?format=xml", api.dailyReport, incorrectReportFormat},
+		{"/v1/reports/weekly?from=2019-05-08&to=2019-05-07", api.weeklyReport, incorrectReportPeriod},
+		{"/v1/reports/weekly?from=2019-01-01&to=2019-05-07", api.weeklyReport, incorrectReportPeriod},
+	} {
+		_, err := request(tt.target, "", tt.handler)
+		he, ok := err.(*echo.HTTPError)
+		require.True(t, ok, tt.target)
+		assert.Equal(t, http.StatusBadRequest, he.Code, tt.target)
+		assert.Equal(t, tt.message, he.Message, tt.target)
+	}
+}
+
+func TestVisibleReport(t *testing.T) {
+	db := storage.NewMemory()
+	_, err := db.CreateStanduper(model.Standuper{WorkspaceID: "T1", ChannelID: "C1", UserID: "PM1", Role: model.RolePM})
+	require.NoError(t, err)
+	api := &ComedianAPI{db: db}
+
+	report := botuser.Report{Entries: []botuser.ReportEntry{
+		{ChannelID: "C1", UserID: "U1"},
+		{ChannelID: "C1", UserID: "PM1"},
+		{ChannelID: "C2", UserID: "U1"},
+		{ChannelID: "C2", UserID: "U2"},
+	}}
+
+	visibleTo := func(userID, role string) []botuser.ReportEntry {
+		c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/v1/reports/daily", nil), httptest.NewRecorder())
+		c.Set("teamID", "T1")
+		c.Set("userID", userID)
+		c.Set("role", role)
+		return api.visibleReport(c, report).Entries
+	}
+
+	assert.Equal(t, report.Entries, visibleTo("U3", model.RoleAdmin))
+	assert.Equal(t, report.Entries[:2], visibleTo("PM1", model.RoleMember))
+	assert.Equal(t, []botuser.ReportEntry{report.Entries[0], report.Entries[2]}, visibleTo("U1", model.RoleMember))
+	assert.Empty(t, visibleTo("U3", model.RoleMember))
+}
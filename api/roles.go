@@ -0,0 +1,180 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/maddevsio/comedian/model"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	adminOnly     = "Only workspace admins are allowed to do this"
+	pmOrAdminOnly = "Only PMs of the project and workspace admins are allowed to do this"
+	lastAdmin     = "Workspace must have at least one admin"
+	ownerOnly     = "Only the author, PMs of the project and workspace admins are allowed to do this"
+)
+
+// requireAdmin lets through requests of workspace admins only
+func requireAdmin(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if c.Get("role") != model.RoleAdmin {
+			return echo.NewHTTPError(http.StatusForbidden, adminOnly)
+		}
+		return next(c)
+	}
+}
+
+// canManageProject tells if caller is workspace admin or PM of the project
+func (api *ComedianAPI) canManageProject(c echo.Context, channelID string) bool {
+	if c.Get("role") == model.RoleAdmin {
+		return true
+	}
+
+	userID, _ := c.Get("userID").(string)
+	if userID == "" {
+		return false
+	}
+
+	standuper, err := api.db.FindStansuperByUserID(userID, channelID)
+	return err == nil && standuper.Role == model.RolePM
+}
+
+// canModify tells if caller may change entity of user in the channel: its author,
+// PM of the project or workspace admin. Entities without channel are managed by admins
+func (api *ComedianAPI) canModify(c echo.Context, userID, channelID string) bool {
+	if userID != "" && c.Get("userID") == userID {
+		return true
+	}
+	if channelID == "" {
+		return c.Get("role") == model.RoleAdmin
+	}
+	return api.canManageProject(c, channelID)
+}
+
+// visibleProjects returns function telling if caller may list entities of the project:
+// admins see all projects of the workspace, others only projects they are standupers of
+func (api *ComedianAPI) visibleProjects(c echo.Context) (func(channelID string) bool, error) {
+	if c.Get("role") == model.RoleAdmin {
+		return func(string) bool { return true }, nil
+	}
+
+	userID, _ := c.Get("userID").(string)
+	if userID == "" {
+		return func(string) bool { return false }, nil
+	}
+
+	standupers, err := api.db.FindStansupersByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	joined := map[string]bool{}
+	for _, standuper := range standupers {
+		if standuper.WorkspaceID == c.Get("teamID") {
+			joined[standuper.ChannelID] = true
+		}
+	}
+	return func(channelID string) bool { return joined[channelID] }, nil
+}
+
+// initialAdmins returns users of the workspace from comma separated
+// "workspace_id:user_id" list of ADMINS setting
+func initialAdmins(admins, workspaceID string) []string {
+	users := []string{}
+	for _, admin := range strings.Split(admins, ",") {
+		fields := strings.SplitN(strings.TrimSpace(admin), ":", 2)
+		if len(fields) == 2 && fields[0] == workspaceID && fields[1] != "" {
+			users = append(users, fields[1])
+		}
+	}
+	return users
+}
+
+// seedAdmins makes users admins of the workspace if it has no admins yet,
+// so the first admin is never chosen by whoever comes first
+func (api *ComedianAPI) seedAdmins(workspaceID string, users ...string) error {
+	admins, err := api.db.ListWorkspaceAdmins(workspaceID)
+	if err != nil || len(admins) > 0 {
+		return err
+	}
+
+	for _, userID := range users {
+		if userID == "" {
+			continue
+		}
+		_, err := api.db.CreateAdmin(model.Admin{
+			CreatedAt:   time.Now().Unix(),
+			WorkspaceID: workspaceID,
+			UserID:      userID,
+		})
+		if err != nil {
+			return err
+		}
+		log.Infof("%v is the first admin of workspace %v", userID, workspaceID)
+	}
+	return nil
+}
+
+func (api *ComedianAPI) listAdmins(c echo.Context) error {
+	admins, err := api.db.ListWorkspaceAdmins(c.Get("teamID").(string))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, somethingWentWrong)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"admins": admins})
+}
+
+func (api *ComedianAPI) createAdmin(c echo.Context) error {
+	var admin model.Admin
+
+	if err := c.Bind(&admin); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, incorrectDataFormat)
+	}
+
+	admin.ID = 0
+	admin.CreatedAt = time.Now().Unix()
+	admin.WorkspaceID = c.Get("teamID").(string)
+
+	admin, err := api.db.CreateAdmin(admin)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(http.StatusCreated, map[string]interface{}{"admin": admin})
+}
+
+func (api *ComedianAPI) deleteAdmin(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 0, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, incorrectID)
+	}
+
+	admins, err := api.db.ListWorkspaceAdmins(c.Get("teamID").(string))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, somethingWentWrong)
+	}
+
+	found := false
+	for _, admin := range admins {
+		if admin.ID == id {
+			found = true
+		}
+	}
+	if !found {
+		return echo.NewHTTPError(http.StatusNotFound, doesNotExist)
+	}
+	if len(admins) == 1 {
+		return echo.NewHTTPError(http.StatusBadRequest, lastAdmin)
+	}
+
+	err = api.db.DeleteAdmin(id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, somethingWentWrong)
+	}
+
+	return c.JSON(http.StatusNoContent, "")
+}
@@ -0,0 +1,364 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo"
+	"github.com/maddevsio/comedian/botuser"
+	"github.com/maddevsio/comedian/model"
+	"github.com/maddevsio/comedian/storage"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestRequireAdmin(t *testing.T) {
+	testCases := []struct {
+		role   string
+		status int
+	}{
+		{model.RoleAdmin, http.StatusOK},
+		{model.RolePM, http.StatusForbidden},
+		{model.RoleMember, http.StatusForbidden},
+		{"", http.StatusForbidden},
+	}
+
+	for _, tt := range testCases {
+		c := echo.New().NewContext(httptest.NewRequest(http.MethodPost, "/v1/admins", nil), httptest.NewRecorder())
+		c.Set("role", tt.role)
+
+		err := requireAdmin(func(c echo.Context) error {
+			return c.NoContent(http.StatusOK)
+		})(c)
+
+		if tt.status == http.StatusOK {
+			assert.NoError(t, err, tt.role)
+			continue
+		}
+		he, ok := err.(*echo.HTTPError)
+		if assert.True(t, ok, tt.role) {
+			assert.Equal(t, tt.status, he.Code, tt.role)
+		}
+	}
+}
+
+func TestCanManageProject(t *testing.T) {
+	db := storage.NewMemory()
+	_, err := db.CreateStanduper(model.Standuper{
+		WorkspaceID: "T1",
+		UserID:      "PM1",
+		ChannelID:   "CHAN1",
+		Role:        model.RolePM,
+	})
+	require.NoError(t, err)
+	api := &ComedianAPI{db: db}
+
+	testCases := []struct {
+		role      string
+		userID    string
+		channelID string
+		allowed   bool
+	}{
+		{model.RoleAdmin, "", "CHAN1", true},
+		{model.RoleMember, "PM1", "CHAN1", true},
+		{model.RoleMember, "PM1", "CHAN2", false},
+		{model.RoleMember, "USER1", "CHAN1", false},
+		{model.RoleMember, "", "CHAN1", false},
+	}
+
+	for _, tt := range testCases {
+		c := echo.New().NewContext(httptest.NewRequest(http.MethodPatch, "/v1/channels/1", nil), httptest.NewRecorder())
+		c.Set("role", tt.role)
+		c.Set("userID", tt.userID)
+		assert.Equal(t, tt.allowed, api.canManageProject(c, tt.channelID), tt)
+	}
+}
+
+func TestCanModify(t *testing.T) {
+	api := &ComedianAPI{db: storage.NewMemory()}
+
+	testCases := []struct {
+		role      string
+		userID    string
+		owner     string
+		channelID string
+		allowed   bool
+	}{
+		{model.RoleMember, "U1", "U1", "CHAN1", true},
+		{model.RoleMember, "U1", "U1", "", true},
+		{model.RoleMember, "U2", "U1", "CHAN1", false},
+		{model.RoleMember, "U2", "U1", "", false},
+		{model.RoleMember, "", "", "", false},
+		{model.RoleAdmin, "U2", "U1", "", true},
+		{model.RoleAdmin, "U2", "U1", "CHAN1", true},
+	}
+
+	for _, tt := range testCases {
+		c := echo.New().NewContext(httptest.NewRequest(http.MethodPatch, "/v1/absences/1", nil), httptest.NewRecorder())
+		c.Set("role", tt.role)
+		c.Set("userID", tt.userID)
+		assert.Equal(t, tt.allowed, api.canModify(c, tt.owner, tt.channelID), tt)
+	}
+}
+
+// patchAs calls update handler as PM1 of CHAN1 in workspace T1
+func patchAs(handler echo.HandlerFunc, id int64, body string) error {
+	req := httptest.NewRequest(http.MethodPatch, "/v1", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	c := echo.New().NewContext(req, httptest.NewRecorder())
+	c.Set("teamID", "T1")
+	c.Set("userID", "PM1")
+	c.Set("role", model.RoleMember)
+	c.SetParamNames("id")
+	c.SetParamValues(fmt.Sprint(id))
+	return handler(c)
+}
+
+func TestUpdateHandlersKeepOwnership(t *testing.T) {
+	db := storage.NewMemory()
+	api := &ComedianAPI{db: db}
+
+	_, err := db.CreateStanduper(model.Standuper{WorkspaceID: "T1", UserID: "PM1", ChannelID: "CHAN1", Role: model.RolePM})
+	require.NoError(t, err)
+	own, err := db.CreateProject(model.Project{WorkspaceID: "T1", ChannelID: "CHAN1", ChannelName: "own"})
+	require.NoError(t, err)
+	foreign, err := db.CreateProject(model.Project{WorkspaceID: "T2", ChannelID: "CHAN2", ChannelName: "foreign"})
+	require.NoError(t, err)
+
+	err = patchAs(api.updateChannel, own.ID, fmt.Sprintf(`{"id":%d,"workspace_id":"T2","channel_id":"CHAN2","deadline":"10:00"}`, foreign.ID))
+	require.NoError(t, err)
+	updated, err := db.GetProject(own.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "10:00", updated.Deadline)
+	assert.Equal(t, "T1", updated.WorkspaceID)
+	assert.Equal(t, "CHAN1", updated.ChannelID)
+	untouched, err := db.GetProject(foreign.ID)
+	require.NoError(t, err)
+	assert.Equal(t, foreign, untouched)
+
+	err = patchAs(api.updateChannel, foreign.ID, `{"deadline":"10:00"}`)
+	assert.Error(t, err)
+
+	member, err := db.CreateStanduper(model.Standuper{WorkspaceID: "T1", UserID: "U1", ChannelID: "CHAN1"})
+	require.NoError(t, err)
+	err = patchAs(api.updateStanduper, member.ID, `{"workspace_id":"T2","user_id":"U2","channel_id":"CHAN2","work_account":"alice"}`)
+	require.NoError(t, err)
+	standuper, err := db.GetStanduper(member.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", standuper.WorkAccount)
+	assert.Equal(t, "T1", standuper.WorkspaceID)
+	assert.Equal(t, "U1", standuper.UserID)
+	assert.Equal(t, "CHAN1", standuper.ChannelID)
+
+	standup, err := db.CreateStandup(model.Standup{WorkspaceID: "T1", UserID: "U1", ChannelID: "CHAN1", MessageTS: "1"})
+	require.NoError(t, err)
+	err = patchAs(api.updateStandup, standup.ID, `{"id":999,"workspace_id":"T2","user_id":"U2","channel_id":"CHAN2","comment":"edited"}`)
+	require.NoError(t, err)
+	edited, err := db.GetStandup(standup.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "edited", edited.Comment)
+	assert.Equal(t, "T1", edited.WorkspaceID)
+	assert.Equal(t, "U1", edited.UserID)
+	assert.Equal(t, "CHAN1", edited.ChannelID)
+}
+
+func TestUpdateBlockerKeepsOwnership(t *testing.T) {
+	db := storage.NewMemory()
+	api := &ComedianAPI{db: db}
+
+	_, err := db.CreateStanduper(model.Standuper{WorkspaceID: "T1", UserID: "PM1", ChannelID: "CHAN1", Role: model.RolePM})
+	require.NoError(t, err)
+	own, err := db.CreateBlocker(model.Blocker{WorkspaceID: "T1", UserID: "U1", ChannelID: "CHAN1", StandupID: 1, Text: "waiting for review"})
+	require.NoError(t, err)
+	foreign, err := db.CreateBlocker(model.Blocker{WorkspaceID: "T2", UserID: "U2", ChannelID: "CHAN2", StandupID: 2, Text: "waiting for design"})
+	require.NoError(t, err)
+
+	err = patchAs(api.updateBlocker, own.ID, fmt.Sprintf(`{"id":%d,"workspace_id":"T2","user_id":"U2","channel_id":"CHAN2","standup_id":2,"text":"resolved","resolved":true}`, foreign.ID))
+	require.NoError(t, err)
+	updated, err := db.GetBlocker(own.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "resolved", updated.Text)
+	assert.True(t, updated.Resolved)
+	assert.NotZero(t, updated.ResolvedAt)
+	assert.Equal(t, int64(1), updated.StandupID)
+	untouched, err := db.GetBlocker(foreign.ID)
+	require.NoError(t, err)
+	assert.Equal(t, foreign, untouched)
+}
+
+func TestUpdateAbsenceKeepsOwnership(t *testing.T) {
+	db := storage.NewMemory()
+	api := &ComedianAPI{db: db}
+
+	_, err := db.CreateStanduper(model.Standuper{WorkspaceID: "T1", UserID: "PM1", ChannelID: "CHAN1", Role: model.RolePM})
+	require.NoError(t, err)
+	own, err := db.CreateAbsence(model.Absence{WorkspaceID: "T1", UserID: "U1", ChannelID: "CHAN1", DateFrom: "2019-05-01", DateTo: "2019-05-03"})
+	require.NoError(t, err)
+	other, err := db.CreateAbsence(model.Absence{WorkspaceID: "T1", UserID: "U2", DateFrom: "2019-05-01", DateTo: "2019-05-03"})
+	require.NoError(t, err)
+
+	err = patchAs(api.updateAbsence, own.ID, fmt.Sprintf(`{"id":%d,"workspace_id":"T2","user_id":"U2","channel_id":"CHAN1","date_from":"2019-05-02","date_to":"2019-05-03","reason":"sick"}`, other.ID))
+	require.NoError(t, err)
+	updated, err := db.GetAbsence(own.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "sick", updated.Reason)
+	assert.Equal(t, "2019-05-02", updated.DateFrom)
+	assert.Equal(t, "T1", updated.WorkspaceID)
+	assert.Equal(t, "U1", updated.UserID)
+	untouched, err := db.GetAbsence(other.ID)
+	require.NoError(t, err)
+	assert.Equal(t, other, untouched)
+
+	// PM of one project cannot move absence to a project of somebody else
+	err = patchAs(api.updateAbsence, own.ID, `{"channel_id":"CHAN2","date_from":"2019-05-02","date_to":"2019-05-03"}`)
+	assert.Error(t, err)
+}
+
+func TestUpdateBotKeepsInstallation(t *testing.T) {
+	db := storage.NewMemory()
+	own, err := db.CreateWorkspace(model.Workspace{WorkspaceID: "T1", WorkspaceName: "one", BotAccessToken: "token1", BotUserID: "B1", Language: "en", NotifierInterval: 30, MaxReminders: 3, ReminderOffset: 10, ReportingTime: "9:00"})
+	require.NoError(t, err)
+	foreign, err := db.CreateWorkspace(model.Workspace{WorkspaceID: "T2", WorkspaceName: "two", BotAccessToken: "token2", BotUserID: "B2", Language: "en", NotifierInterval: 30, MaxReminders: 3, ReminderOffset: 10, ReportingTime: "9:00"})
+	require.NoError(t, err)
+
+	bot := botuser.New(nil, i18n.NewBundle(language.English), own, db)
+	api := &ComedianAPI{db: db, bots: []*botuser.Bot{bot}}
+
+	req := httptest.NewRequest(http.MethodPatch, "/v1/bots", strings.NewReader(fmt.Sprintf(
+		`{"id":%d,"workspace_id":"T2","workspace_name":"two","bot_user_id":"B2","language":"ru"}`, foreign.ID)))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	c := echo.New().NewContext(req, httptest.NewRecorder())
+	c.Set("teamID", "T1")
+	c.SetParamNames("id")
+	c.SetParamValues(fmt.Sprint(own.ID))
+	require.NoError(t, api.updateBot(c))
+
+	updated, err := db.GetWorkspace(own.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "ru", updated.Language)
+	assert.Equal(t, "T1", updated.WorkspaceID)
+	assert.Equal(t, "B1", updated.BotUserID)
+	assert.Equal(t, "token1", updated.BotAccessToken)
+	untouched, err := db.GetWorkspace(foreign.ID)
+	require.NoError(t, err)
+	assert.Equal(t, foreign, untouched)
+}
+
+func TestSeedAdmins(t *testing.T) {
+	api := &ComedianAPI{db: storage.NewMemory()}
+
+	assert.Equal(t, []string{"U1", "U3"}, initialAdmins("T1:U1, T2:U2,T1:U3,T1:,U4", "T1"))
+	assert.Empty(t, initialAdmins("", "T1"))
+
+	require.NoError(t, api.seedAdmins("T1", "U1", ""))
+	require.NoError(t, api.seedAdmins("T1", "U2"))
+	admins, err := api.db.ListWorkspaceAdmins("T1")
+	require.NoError(t, err)
+	require.Len(t, admins, 1)
+	assert.Equal(t, "U1", admins[0].UserID)
+}
+
+// listAs calls list handler in workspace T1 and returns IDs of listed entities
+func listAs(t *testing.T, handler echo.HandlerFunc, role, userID, key string) []int64 {
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/v1", nil), rec)
+	c.Set("teamID", "T1")
+	c.Set("userID", userID)
+	c.Set("role", role)
+	require.NoError(t, handler(c))
+
+	var body map[string][]struct {
+		ID int64 `json:"id"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	ids := []int64{}
+	for _, item := range body[key] {
+		ids = append(ids, item.ID)
+	}
+	return ids
+}
+
+func TestListsShowJoinedProjects(t *testing.T) {
+	db := storage.NewMemory()
+	api := &ComedianAPI{db: db}
+
+	own, err := db.CreateStanduper(model.Standuper{WorkspaceID: "T1", UserID: "U1", ChannelID: "CHAN1"})
+	require.NoError(t, err)
+	other, err := db.CreateStanduper(model.Standuper{WorkspaceID: "T1", UserID: "U2", ChannelID: "CHAN2"})
+	require.NoError(t, err)
+	_, err = db.CreateStanduper(model.Standuper{WorkspaceID: "T2", UserID: "U1", ChannelID: "CHAN2"})
+	require.NoError(t, err)
+
+	ownStandup, err := db.CreateStandup(model.Standup{WorkspaceID: "T1", UserID: "U1", ChannelID: "CHAN1", MessageTS: "1"})
+	require.NoError(t, err)
+	otherStandup, err := db.CreateStandup(model.Standup{WorkspaceID: "T1", UserID: "U2", ChannelID: "CHAN2", MessageTS: "2"})
+	require.NoError(t, err)
+
+	ownBlocker, err := db.CreateBlocker(model.Blocker{WorkspaceID: "T1", UserID: "U1", ChannelID: "CHAN1", Text: "waiting"})
+	require.NoError(t, err)
+	otherBlocker, err := db.CreateBlocker(model.Blocker{WorkspaceID: "T1", UserID: "U2", ChannelID: "CHAN2", Text: "waiting"})
+	require.NoError(t, err)
+
+	ownAbsence, err := db.CreateAbsence(model.Absence{WorkspaceID: "T1", UserID: "U1", DateFrom: "2019-05-07", DateTo: "2019-05-07"})
+	require.NoError(t, err)
+	projectAbsence, err := db.CreateAbsence(model.Absence{WorkspaceID: "T1", UserID: "U3", ChannelID: "CHAN1", DateFrom: "2019-05-07", DateTo: "2019-05-07"})
+	require.NoError(t, err)
+	otherAbsence, err := db.CreateAbsence(model.Absence{WorkspaceID: "T1", UserID: "U2", DateFrom: "2019-05-07", DateTo: "2019-05-07"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []int64{own.ID}, listAs(t, api.listStandupers, model.RoleMember, "U1", "standupers"))
+	assert.ElementsMatch(t, []int64{own.ID, other.ID}, listAs(t, api.listStandupers, model.RoleAdmin, "U1", "standupers"))
+	assert.Equal(t, []int64{ownStandup.ID}, listAs(t, api.listStandups, model.RoleMember, "U1", "standups"))
+	assert.ElementsMatch(t, []int64{ownStandup.ID, otherStandup.ID}, listAs(t, api.listStandups, model.RoleAdmin, "U1", "standups"))
+	assert.Equal(t, []int64{ownBlocker.ID}, listAs(t, api.listBlockers, model.RoleMember, "U1", "blockers"))
+	assert.ElementsMatch(t, []int64{ownBlocker.ID, otherBlocker.ID}, listAs(t, api.listBlockers, model.RoleAdmin, "U1", "blockers"))
+	assert.ElementsMatch(t, []int64{ownAbsence.ID, projectAbsence.ID}, listAs(t, api.listAbsences, model.RoleMember, "U1", "absences"))
+	assert.ElementsMatch(t, []int64{ownAbsence.ID, projectAbsence.ID, otherAbsence.ID}, listAs(t, api.listAbsences, model.RoleAdmin, "U1", "absences"))
+	assert.Empty(t, listAs(t, api.listStandups, model.RoleMember, "", "standups"))
+}
+
+func TestHolidaysOfForeignChannel(t *testing.T) {
+	db := storage.NewMemory()
+	api := &ComedianAPI{db: db}
+
+	_, err := db.CreateProject(model.Project{WorkspaceID: "T1", ChannelID: "CHAN1", ChannelName: "own"})
+	require.NoError(t, err)
+	_, err = db.CreateProject(model.Project{WorkspaceID: "T2", ChannelID: "CHAN2", ChannelName: "foreign"})
+	require.NoError(t, err)
+
+	create := func(channelID string) error {
+		body := fmt.Sprintf(`{"channel_id":%q,"date":"2019-05-09","name":"Victory Day"}`, channelID)
+		req := httptest.NewRequest(http.MethodPost, "/v1/holidays", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		c := echo.New().NewContext(req, httptest.NewRecorder())
+		c.Set("teamID", "T1")
+		c.Set("role", model.RoleAdmin)
+		return api.createHoliday(c)
+	}
+	importTo := func(channelID string) error {
+		ics := "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nDTSTART;VALUE=DATE:20190509\r\nSUMMARY:Victory Day\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+		req := httptest.NewRequest(http.MethodPost, "/v1/holidays/import?channel_id="+channelID, strings.NewReader(ics))
+		c := echo.New().NewContext(req, httptest.NewRecorder())
+		c.Set("teamID", "T1")
+		c.Set("role", model.RoleAdmin)
+		return api.importHolidays(c)
+	}
+
+	assert.NoError(t, create(""))
+	assert.NoError(t, create("CHAN1"))
+	assert.NoError(t, importTo("CHAN1"))
+	for _, channelID := range []string{"CHAN2", "UNKNOWN"} {
+		assert.Error(t, create(channelID), channelID)
+		assert.Error(t, importTo(channelID), channelID)
+	}
+
+	holidays, err := db.ListTeamHolidays("T1")
+	require.NoError(t, err)
+	assert.Len(t, holidays, 2)
+}
@@ -0,0 +1,118 @@
+package api
+
+import (
+	"crypto/rand"
+	"errors"
+	"net/http"
+	"strings"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/labstack/echo"
+	"github.com/maddevsio/comedian/model"
+	log "github.com/sirupsen/logrus"
+)
+
+var invalidSession = "Missing, expired or incorrect session token, login again"
+
+// sessionClaims tie session to the user logged in with Slack and the workspace
+type sessionClaims struct {
+	UserID      string `json:"sub"`
+	WorkspaceID string `json:"team"`
+	IssuedAt    int64  `json:"iat"`
+	ExpiresAt   int64  `json:"exp"`
+}
+
+// Valid rejects expired sessions and sessions that do not identify the user
+func (claims sessionClaims) Valid() error {
+	if claims.UserID == "" || claims.WorkspaceID == "" {
+		return errors.New("token does not identify user")
+	}
+	if jwt.TimeFunc().Unix() >= claims.ExpiresAt {
+		return errors.New("token expired")
+	}
+	return nil
+}
+
+// sessionSecret returns configured secret signing sessions. Without one a random
+// secret is used, so sessions do not survive restart and are not shared between replicas
+func sessionSecret(secret string) []byte {
+	if secret != "" {
+		return []byte(secret)
+	}
+
+	log.Warning("JWT_SECRET is not set, sessions are signed with a random secret of this replica")
+	random := make([]byte, 32)
+	_, err := rand.Read(random)
+	if err != nil {
+		log.Fatal("could not generate session secret: ", err)
+	}
+	return random
+}
+
+// issueSession returns JWT signed with HMAC-SHA256
+func issueSession(secret []byte, claims sessionClaims) (string, error) {
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+}
+
+// parseSession checks signature and expiration of JWT issued by issueSession.
+// Tokens signed with any other algorithm, including "none", are rejected
+func parseSession(secret []byte, token string) (sessionClaims, error) {
+	var claims sessionClaims
+
+	_, err := jwt.ParseWithClaims(token, &claims, func(t *jwt.Token) (interface{}, error) {
+		if t.Method != jwt.SigningMethodHS256 {
+			return nil, errors.New("unsupported token header")
+		}
+		return secret, nil
+	})
+	if err == nil {
+		return claims, nil
+	}
+
+	ve, ok := err.(*jwt.ValidationError)
+	switch {
+	case !ok:
+		return claims, err
+	case ve.Errors&jwt.ValidationErrorMalformed != 0:
+		return claims, errors.New("malformed token")
+	case ve.Errors&jwt.ValidationErrorUnverifiable != 0:
+		return claims, errors.New("unsupported token header")
+	case ve.Errors&jwt.ValidationErrorSignatureInvalid != 0:
+		return claims, errors.New("signature does not match")
+	case ve.Inner != nil:
+		return claims, ve.Inner
+	}
+	return claims, err
+}
+
+// AuthPreRequest authenticates /v1 requests with session token issued by /login,
+// passed as "Authorization: Bearer <token>". Sets workspace, user and role of the caller
+func (api *ComedianAPI) AuthPreRequest(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		token := strings.TrimPrefix(c.Request().Header.Get(echo.HeaderAuthorization), "Bearer ")
+
+		claims, err := parseSession(api.sessionSecret, token)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusUnauthorized, invalidSession)
+		}
+
+		// sessions of workspaces Comedian was removed from are rejected
+		_, err = api.db.GetWorkspaceByWorkspaceID(claims.WorkspaceID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusUnauthorized, invalidSession)
+		}
+
+		// role is looked up on every request, so revoked admins lose access at once
+		role := model.RoleMember
+		_, err = api.db.FindAdmin(claims.WorkspaceID, claims.UserID)
+		if err == nil {
+			role = model.RoleAdmin
+		}
+
+		c.Set("teamID", claims.WorkspaceID)
+		c.Set("userID", claims.UserID)
+		c.Set("role", role)
+
+		return next(c)
+	}
+}
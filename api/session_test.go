@@ -0,0 +1,120 @@
+package api
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/labstack/echo"
+	"github.com/maddevsio/comedian/model"
+	"github.com/maddevsio/comedian/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSession(t *testing.T) {
+	secret := []byte("secret")
+	now := time.Now()
+	claims := sessionClaims{UserID: "U1", WorkspaceID: "T1", IssuedAt: now.Unix(), ExpiresAt: now.Add(time.Hour).Unix()}
+
+	valid, err := issueSession(secret, claims)
+	require.NoError(t, err)
+	expired, err := issueSession(secret, sessionClaims{UserID: "U1", WorkspaceID: "T1", ExpiresAt: now.Add(-time.Minute).Unix()})
+	require.NoError(t, err)
+	anonymous, err := issueSession(secret, sessionClaims{WorkspaceID: "T1", ExpiresAt: now.Add(time.Hour).Unix()})
+	require.NoError(t, err)
+	forged, err := issueSession([]byte("other"), claims)
+	require.NoError(t, err)
+	otherAlg, err := jwt.NewWithClaims(jwt.SigningMethodHS512, claims).SignedString(secret)
+	require.NoError(t, err)
+
+	parts := strings.Split(valid, ".")
+	unsigned := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`)) + "." + parts[1] + "."
+
+	testCases := []struct {
+		name         string
+		token        string
+		errorMessage string
+	}{
+		{"valid", valid, ""},
+		{"expired", expired, "token expired"},
+		{"no user", anonymous, "token does not identify user"},
+		{"other secret", forged, "signature does not match"},
+		{"alg none", unsigned, "unsupported token header"},
+		{"other alg", otherAlg, "unsupported token header"},
+		{"bot token", "xoxb-123", "malformed token"},
+		{"empty", "", "malformed token"},
+	}
+
+	for _, tt := range testCases {
+		parsed, err := parseSession(secret, tt.token)
+		if tt.errorMessage == "" {
+			assert.NoError(t, err, tt.name)
+			assert.Equal(t, claims, parsed, tt.name)
+		} else if assert.Error(t, err, tt.name) {
+			assert.Equal(t, tt.errorMessage, err.Error(), tt.name)
+		}
+	}
+}
+
+func TestAuthPreRequest(t *testing.T) {
+	db := storage.NewMemory()
+	_, err := db.CreateWorkspace(model.Workspace{
+		WorkspaceID:      "T1",
+		WorkspaceName:    "team",
+		BotAccessToken:   "xoxb-123",
+		NotifierInterval: 30,
+		Language:         "en",
+		MaxReminders:     3,
+		ReminderOffset:   10,
+		ReportingTime:    "10am",
+	})
+	require.NoError(t, err)
+	_, err = db.CreateAdmin(model.Admin{WorkspaceID: "T1", UserID: "ADMIN1"})
+	require.NoError(t, err)
+
+	api := &ComedianAPI{db: db, sessionSecret: []byte("secret")}
+	session := func(userID, workspaceID string) string {
+		token, err := issueSession(api.sessionSecret, sessionClaims{UserID: userID, WorkspaceID: workspaceID, ExpiresAt: time.Now().Add(time.Hour).Unix()})
+		require.NoError(t, err)
+		return "Bearer " + token
+	}
+
+	testCases := []struct {
+		name          string
+		authorization string
+		status        int
+		role          string
+	}{
+		{"member", session("U1", "T1"), http.StatusOK, model.RoleMember},
+		{"admin", session("ADMIN1", "T1"), http.StatusOK, model.RoleAdmin},
+		{"unknown workspace", session("U1", "T2"), http.StatusUnauthorized, ""},
+		{"bot access token", "xoxb-123", http.StatusUnauthorized, ""},
+		{"missing", "", http.StatusUnauthorized, ""},
+	}
+
+	for _, tt := range testCases {
+		req := httptest.NewRequest(http.MethodGet, "/v1/standups", nil)
+		req.Header.Set(echo.HeaderAuthorization, tt.authorization)
+		c := echo.New().NewContext(req, httptest.NewRecorder())
+
+		err := api.AuthPreRequest(func(c echo.Context) error {
+			return c.NoContent(http.StatusOK)
+		})(c)
+
+		if tt.status == http.StatusOK {
+			assert.NoError(t, err, tt.name)
+			assert.Equal(t, tt.role, c.Get("role"), tt.name)
+			assert.Equal(t, "T1", c.Get("teamID"), tt.name)
+			continue
+		}
+		he, ok := err.(*echo.HTTPError)
+		if assert.True(t, ok, tt.name) {
+			assert.Equal(t, tt.status, he.Code, tt.name)
+		}
+	}
+}
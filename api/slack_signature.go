@@ -0,0 +1,102 @@
+package api
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	slackSignatureHeader = "X-Slack-Signature"
+	slackTimestampHeader = "X-Slack-Request-Timestamp"
+	slackSignatureScheme = "v0"
+)
+
+// verifySlackRequest lets through requests signed with Slack signing secret.
+// Deprecated verification token is checked only when no signing secret is
+// configured, otherwise unsigned requests could bypass the signature
+func (api *ComedianAPI) verifySlackRequest(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		body, err := ioutil.ReadAll(c.Request().Body)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		// handlers read the body again
+		c.Request().Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		if api.config.SlackSigningSecret != "" {
+			signature := c.Request().Header.Get(slackSignatureHeader)
+			timestamp := c.Request().Header.Get(slackTimestampHeader)
+			err := checkSlackSignature(api.config.SlackSigningSecret, signature, timestamp, body, time.Now(), api.config.SlackSignatureSkew)
+			if err != nil {
+				log.Warning("Slack request rejected: ", err)
+				return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+			}
+			return next(c)
+		}
+
+		if api.config.SlackVerificationToken != "" && slackRequestToken(body) == api.config.SlackVerificationToken {
+			return next(c)
+		}
+
+		return echo.NewHTTPError(http.StatusUnauthorized, "request is not signed by Slack")
+	}
+}
+
+// checkSlackSignature checks signature of request body made at timestamp, see
+// https://api.slack.com/docs/verifying-requests-from-slack
+func checkSlackSignature(secret, signature, timestamp string, body []byte, now time.Time, skew time.Duration) error {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return errors.New("invalid request timestamp")
+	}
+
+	// old requests are rejected, so that captured ones can not be replayed
+	diff := now.Sub(time.Unix(ts, 0))
+	if diff > skew || diff < -skew {
+		return errors.New("request timestamp is out of allowed window")
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(slackSignatureScheme + ":" + timestamp + ":"))
+	mac.Write(body)
+	expected := slackSignatureScheme + "=" + hex.EncodeToString(mac.Sum(nil))
+
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return errors.New("signature does not match")
+	}
+	return nil
+}
+
+// slackRequestToken returns verification token of event (JSON), slash command (form)
+// or interaction (JSON in payload form field) payload
+func slackRequestToken(body []byte) string {
+	if strings.HasPrefix(strings.TrimSpace(string(body)), "{") {
+		var payload struct {
+			Token string `json:"token"`
+		}
+		json.Unmarshal(body, &payload)
+		return payload.Token
+	}
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return ""
+	}
+	if payload := form.Get("payload"); payload != "" {
+		return slackRequestToken([]byte(payload))
+	}
+	return form.Get("token")
+}
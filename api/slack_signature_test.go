@@ -0,0 +1,81 @@
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/maddevsio/comedian/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func sign(secret, timestamp, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + timestamp + ":" + body))
+	return "v0=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifySlackRequest(t *testing.T) {
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	old := strconv.FormatInt(time.Now().Add(-10*time.Minute).Unix(), 10)
+	command := "token=legacy&team_id=T1&command=%2Fshow"
+
+	testCases := []struct {
+		name      string
+		secret    string
+		token     string
+		body      string
+		timestamp string
+		signature string
+		status    int
+	}{
+		{"signed", "secret", "", command, now, sign("secret", now, command), http.StatusOK},
+		{"wrong secret", "secret", "", command, now, sign("other", now, command), http.StatusUnauthorized},
+		{"replayed", "secret", "", command, old, sign("secret", old, command), http.StatusUnauthorized},
+		{"bad signature does not fall back to token", "secret", "legacy", command, now, "v0=abc", http.StatusUnauthorized},
+		{"no token fallback when secret is set", "secret", "legacy", command, "", "", http.StatusUnauthorized},
+		{"token fallback for command", "", "legacy", command, "", "", http.StatusOK},
+		{"token fallback for event", "", "legacy", `{"token":"legacy","type":"url_verification"}`, "", "", http.StatusOK},
+		{"wrong token", "", "legacy", `{"token":"other"}`, "", "", http.StatusUnauthorized},
+		{"no fallback", "secret", "", command, "", "", http.StatusUnauthorized},
+		{"nothing configured", "", "", command, "", "", http.StatusUnauthorized},
+	}
+
+	for _, tt := range testCases {
+		api := &ComedianAPI{config: &config.Config{
+			SlackSigningSecret:     tt.secret,
+			SlackVerificationToken: tt.token,
+			SlackSignatureSkew:     5 * time.Minute,
+		}}
+
+		req := httptest.NewRequest(http.MethodPost, "/commands", strings.NewReader(tt.body))
+		if tt.signature != "" {
+			req.Header.Set(slackSignatureHeader, tt.signature)
+			req.Header.Set(slackTimestampHeader, tt.timestamp)
+		}
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(req, rec)
+
+		err := api.verifySlackRequest(func(c echo.Context) error {
+			// body is still readable by handler
+			body, err := ioutil.ReadAll(c.Request().Body)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.body, string(body))
+			return c.NoContent(http.StatusOK)
+		})(c)
+
+		status := rec.Code
+		if he, ok := err.(*echo.HTTPError); ok {
+			status = he.Code
+		}
+		assert.Equal(t, tt.status, status, tt.name)
+	}
+}
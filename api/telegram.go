@@ -0,0 +1,60 @@
+package api
+
+import (
+	"database/sql"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/maddevsio/comedian/messenger"
+	"github.com/maddevsio/comedian/model"
+)
+
+// setupTelegram creates or updates workspace of Telegram bot configured with env variables.
+// Updates are polled by the bot itself, so no endpoints are exposed for Telegram
+func (api *ComedianAPI) setupTelegram() error {
+	if api.config.TelegramBotToken == "" {
+		return nil
+	}
+
+	chat := messenger.NewTelegram(api.config.TelegramAPIURL, api.config.TelegramBotToken)
+
+	me, err := chat.Me()
+	if err != nil {
+		return err
+	}
+
+	// bot is mentioned by @username both in standups and in commands
+	botUserID := "@" + me.Username
+	workspaceID := strconv.FormatInt(me.ID, 10)
+
+	workspace, err := api.db.GetWorkspaceByWorkspaceID(workspaceID)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			return err
+		}
+		_, err = api.db.CreateWorkspace(model.Workspace{
+			CreatedAt:              time.Now().Unix(),
+			BotUserID:              botUserID,
+			NotifierInterval:       30,
+			Language:               "en",
+			MaxReminders:           3,
+			ReminderOffset:         10,
+			BotAccessToken:         api.config.TelegramBotToken,
+			WorkspaceID:            workspaceID,
+			WorkspaceName:          strings.TrimSpace(me.FirstName + " " + me.LastName),
+			ReportingChannel:       "",
+			ReportingTime:          "10am",
+			ProjectsReportsEnabled: false,
+			Platform:               model.PlatformTelegram,
+		})
+		return err
+	}
+
+	workspace.BotUserID = botUserID
+	workspace.BotAccessToken = api.config.TelegramBotToken
+	workspace.Platform = model.PlatformTelegram
+
+	_, err = api.db.UpdateWorkspace(workspace)
+	return err
+}
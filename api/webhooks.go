@@ -0,0 +1,135 @@
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/maddevsio/comedian/model"
+)
+
+// deliveriesLimit is how many latest deliveries of webhook are shown
+const deliveriesLimit = 100
+
+func (api *ComedianAPI) listWebhooks(c echo.Context) error {
+	webhooks, err := api.db.ListWorkspaceWebhooks(c.Get("teamID").(string))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, somethingWentWrong)
+	}
+
+	// secret is shown only once, when webhook is created
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"webhooks": webhooks})
+}
+
+func (api *ComedianAPI) createWebhook(c echo.Context) error {
+	webhook := model.Webhook{Enabled: true}
+
+	if err := c.Bind(&webhook); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, incorrectDataFormat)
+	}
+
+	webhook.ID = 0
+	webhook.CreatedAt = time.Now().Unix()
+	webhook.WorkspaceID = c.Get("teamID").(string)
+	if webhook.Secret == "" {
+		secret, err := webhookSecret()
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, somethingWentWrong)
+		}
+		webhook.Secret = secret
+	}
+
+	webhook, err := api.db.CreateWebhook(webhook)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(http.StatusCreated, map[string]interface{}{"webhook": webhook})
+}
+
+func (api *ComedianAPI) updateWebhook(c echo.Context) error {
+	webhook, err := api.findWebhook(c)
+	if err != nil {
+		return err
+	}
+	stored := webhook
+	webhook.Secret = ""
+
+	if err := c.Bind(&webhook); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, incorrectDataFormat)
+	}
+	webhook.ID = stored.ID
+	webhook.CreatedAt = stored.CreatedAt
+	webhook.WorkspaceID = stored.WorkspaceID
+	if webhook.Secret == "" {
+		webhook.Secret = stored.Secret
+	}
+
+	webhook, err = api.db.UpdateWebhook(webhook)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	webhook.Secret = ""
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"webhook": webhook})
+}
+
+func (api *ComedianAPI) deleteWebhook(c echo.Context) error {
+	webhook, err := api.findWebhook(c)
+	if err != nil {
+		return err
+	}
+
+	err = api.db.DeleteWebhook(webhook.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, somethingWentWrong)
+	}
+
+	return c.JSON(http.StatusNoContent, "")
+}
+
+func (api *ComedianAPI) listWebhookDeliveries(c echo.Context) error {
+	webhook, err := api.findWebhook(c)
+	if err != nil {
+		return err
+	}
+
+	deliveries, err := api.db.ListWebhookDeliveries(webhook.ID, deliveriesLimit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, somethingWentWrong)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"deliveries": deliveries})
+}
+
+// findWebhook returns webhook from path if it belongs to workspace of caller
+func (api *ComedianAPI) findWebhook(c echo.Context) (model.Webhook, error) {
+	id, err := strconv.ParseInt(c.Param("id"), 0, 64)
+	if err != nil {
+		return model.Webhook{}, echo.NewHTTPError(http.StatusBadRequest, incorrectID)
+	}
+
+	webhook, err := api.db.GetWebhook(id)
+	if err != nil || webhook.WorkspaceID != c.Get("teamID") {
+		return model.Webhook{}, echo.NewHTTPError(http.StatusNotFound, doesNotExist)
+	}
+
+	return webhook, nil
+}
+
+// webhookSecret generates secret used to sign deliveries
+func webhookSecret() (string, error) {
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
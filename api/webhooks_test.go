@@ -0,0 +1,76 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo"
+	"github.com/maddevsio/comedian/model"
+	"github.com/maddevsio/comedian/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookHandlers(t *testing.T) {
+	api := &ComedianAPI{db: storage.NewMemory()}
+
+	request := func(method, body, id string, handler echo.HandlerFunc) (*httptest.ResponseRecorder, error) {
+		req := httptest.NewRequest(method, "/v1/webhooks", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(req, rec)
+		c.Set("teamID", "T1")
+		c.SetParamNames("id")
+		c.SetParamValues(id)
+		return rec, handler(c)
+	}
+
+	_, err := request(http.MethodPost, `{"url":"ftp://example.com"}`, "", api.createWebhook)
+	assert.Error(t, err)
+
+	rec, err := request(http.MethodPost, `{"url":"https://example.com/hook","events":"standup.created"}`, "", api.createWebhook)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	var created struct {
+		Webhook model.Webhook `json:"webhook"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.Equal(t, 64, len(created.Webhook.Secret))
+	assert.True(t, created.Webhook.Enabled)
+	assert.Equal(t, "T1", created.Webhook.WorkspaceID)
+	id := created.Webhook.ID
+
+	rec, err = request(http.MethodGet, "", "", api.listWebhooks)
+	require.NoError(t, err)
+	assert.Contains(t, rec.Body.String(), "https://example.com/hook")
+	assert.NotContains(t, rec.Body.String(), created.Webhook.Secret)
+
+	rec, err = request(http.MethodPatch, `{"id":999,"workspace_id":"T2","url":"https://example.com/other","enabled":false}`, fmt.Sprint(id), api.updateWebhook)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	stored, err := api.db.GetWebhook(id)
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/other", stored.URL)
+	assert.Equal(t, "T1", stored.WorkspaceID)
+	assert.Equal(t, created.Webhook.Secret, stored.Secret)
+	assert.False(t, stored.Enabled)
+
+	rec, err = request(http.MethodGet, "", fmt.Sprint(id), api.listWebhookDeliveries)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	other, err := api.db.CreateWebhook(model.Webhook{WorkspaceID: "T2", URL: "https://example.com", Secret: "s"})
+	require.NoError(t, err)
+	_, err = request(http.MethodDelete, "", fmt.Sprint(other.ID), api.deleteWebhook)
+	he, ok := err.(*echo.HTTPError)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusNotFound, he.Code)
+
+	request(http.MethodDelete, "", fmt.Sprint(id), api.deleteWebhook)
+	_, err = api.db.GetWebhook(id)
+	assert.Error(t, err)
+}
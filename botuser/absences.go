@@ -0,0 +1,186 @@
+package botuser
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/maddevsio/comedian/model"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/nlopes/slack"
+	log "github.com/sirupsen/logrus"
+)
+
+// isAbsent returns true if user is on leave in the channel on the given day
+func (bot *Bot) isAbsent(userID, channelID string, t time.Time) bool {
+	_, err := bot.db.FindAbsence(userID, channelID, t.Format(model.AbsenceDateFormat))
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Error("FindAbsence failed: ", err)
+		}
+		return false
+	}
+	return true
+}
+
+func (bot *Bot) awayCommand(command slack.SlashCommand) string {
+	fields := strings.Fields(command.Text)
+
+	if len(fields) == 0 {
+		return bot.listAbsences(command.UserID)
+	}
+
+	if len(fields) == 2 && fields[0] == "cancel" {
+		return bot.cancelAbsence(command.UserID, fields[1])
+	}
+
+	absence := model.Absence{
+		CreatedAt:   time.Now().Unix(),
+		WorkspaceID: bot.workspace.WorkspaceID,
+		UserID:      command.UserID,
+		DateFrom:    fields[0],
+		DateTo:      fields[0],
+	}
+	fields = fields[1:]
+
+	if len(fields) > 0 {
+		if _, err := time.Parse(model.AbsenceDateFormat, fields[0]); err == nil {
+			absence.DateTo = fields[0]
+			fields = fields[1:]
+		}
+	}
+
+	if len(fields) > 0 && fields[0] == "here" {
+		absence.ChannelID = command.ChannelID
+		fields = fields[1:]
+	}
+
+	absence.Reason = strings.Join(fields, " ")
+
+	if absence.Validate() != nil {
+		msg, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+			DefaultMessage: &i18n.Message{
+				ID:    "wrongAwayFormat",
+				Other: "Use '/away YYYY-MM-DD [YYYY-MM-DD] [here] [reason]' to set your absence, '/away cancel <id>' to cancel it",
+			},
+		})
+		if err != nil {
+			log.Error(err)
+		}
+		return msg
+	}
+
+	absence, err := bot.db.CreateAbsence(absence)
+	if err != nil {
+		log.Error("CreateAbsence failed: ", err)
+		msg, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+			DefaultMessage: &i18n.Message{
+				ID:    "failedCreateAbsence",
+				Other: "Could not save your absence",
+			},
+		})
+		if err != nil {
+			log.Error(err)
+		}
+		return msg
+	}
+
+	msg, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+		DefaultMessage: &i18n.Message{
+			ID:    "absenceCreated",
+			Other: "You are away from {{.from}} to {{.to}}, no standups are expected from you",
+		},
+		TemplateData: map[string]interface{}{
+			"from": absence.DateFrom,
+			"to":   absence.DateTo,
+		},
+	})
+	if err != nil {
+		log.Error(err)
+	}
+	return msg
+}
+
+func (bot *Bot) listAbsences(userID string) string {
+	absences, err := bot.db.ListUserAbsences(userID, time.Now().Format(model.AbsenceDateFormat))
+	if err != nil {
+		log.Error("ListUserAbsences failed: ", err)
+	}
+
+	if len(absences) == 0 {
+		msg, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+			DefaultMessage: &i18n.Message{
+				ID:    "noAbsences",
+				Other: "You have no upcoming absences",
+			},
+		})
+		if err != nil {
+			log.Error(err)
+		}
+		return msg
+	}
+
+	list := []string{}
+	for _, absence := range absences {
+		item := fmt.Sprintf("%d. %s - %s", absence.ID, absence.DateFrom, absence.DateTo)
+		if absence.ChannelID != "" {
+			item += fmt.Sprintf(" <#%s>", absence.ChannelID)
+		}
+		if absence.Reason != "" {
+			item += " " + absence.Reason
+		}
+		list = append(list, item)
+	}
+	return strings.Join(list, "\n")
+}
+
+func (bot *Bot) cancelAbsence(userID, param string) string {
+	id, err := strconv.ParseInt(param, 10, 64)
+	if err != nil {
+		return bot.absenceNotFound(param)
+	}
+
+	absence, err := bot.db.GetAbsence(id)
+	if err != nil || absence.UserID != userID {
+		return bot.absenceNotFound(param)
+	}
+
+	err = bot.db.DeleteAbsence(id)
+	if err != nil {
+		log.Error("DeleteAbsence failed: ", err)
+		return bot.absenceNotFound(param)
+	}
+
+	msg, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+		DefaultMessage: &i18n.Message{
+			ID:    "absenceCanceled",
+			Other: "Absence from {{.from}} to {{.to}} is canceled",
+		},
+		TemplateData: map[string]interface{}{
+			"from": absence.DateFrom,
+			"to":   absence.DateTo,
+		},
+	})
+	if err != nil {
+		log.Error(err)
+	}
+	return msg
+}
+
+func (bot *Bot) absenceNotFound(id string) string {
+	msg, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+		DefaultMessage: &i18n.Message{
+			ID:    "absenceNotFound",
+			Other: "You have no absence {{.id}}",
+		},
+		TemplateData: map[string]interface{}{
+			"id": id,
+		},
+	})
+	if err != nil {
+		log.Error(err)
+	}
+	return msg
+}
@@ -0,0 +1,58 @@
+package botuser
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/maddevsio/comedian/model"
+	"github.com/nlopes/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAwayCommand(t *testing.T) {
+	command := slack.SlashCommand{
+		Command:   "/away",
+		TeamID:    "testTeam",
+		UserID:    "away123",
+		ChannelID: "CHAN123",
+	}
+
+	resp := bot.ImplementCommands(command)
+	assert.Equal(t, "You have no upcoming absences", resp)
+
+	command.Text = "tomorrow"
+	resp = bot.ImplementCommands(command)
+	assert.Equal(t, "Use '/away YYYY-MM-DD [YYYY-MM-DD] [here] [reason]' to set your absence, '/away cancel <id>' to cancel it", resp)
+
+	from := time.Now().Format(model.AbsenceDateFormat)
+	to := time.Now().AddDate(0, 0, 13).Format(model.AbsenceDateFormat)
+
+	command.Text = from + " " + to + " here vacation"
+	resp = bot.ImplementCommands(command)
+	assert.Equal(t, "You are away from "+from+" to "+to+", no standups are expected from you", resp)
+
+	assert.True(t, bot.isAbsent("away123", "CHAN123", time.Now()))
+	assert.False(t, bot.isAbsent("away123", "CHAN321", time.Now()))
+	assert.False(t, bot.isAbsent("away123", "CHAN123", time.Now().AddDate(0, 0, 14)))
+
+	absences, err := bot.db.ListUserAbsences("away123", from)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(absences))
+	assert.Equal(t, "vacation", absences[0].Reason)
+
+	command.Text = ""
+	resp = bot.ImplementCommands(command)
+	assert.Contains(t, resp, from+" - "+to+" <#CHAN123> vacation")
+
+	command.Text = "cancel 100500"
+	resp = bot.ImplementCommands(command)
+	assert.Equal(t, "You have no absence 100500", resp)
+
+	command.Text = "cancel " + strconv.FormatInt(absences[0].ID, 10)
+	resp = bot.ImplementCommands(command)
+	assert.Equal(t, "Absence from "+from+" to "+to+" is canceled", resp)
+
+	assert.False(t, bot.isAbsent("away123", "CHAN123", time.Now()))
+}
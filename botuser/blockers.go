@@ -0,0 +1,238 @@
+package botuser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/maddevsio/comedian/model"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/nlopes/slack"
+	log "github.com/sirupsen/logrus"
+)
+
+// carryBlockers adds user's blockers that are still open to the blockers of a new standup
+func (bot *Bot) carryBlockers(standup model.Standup) string {
+	blockers, err := bot.db.ListOpenBlockers(standup.ChannelID)
+	if err != nil {
+		log.Error("ListOpenBlockers failed: ", err)
+		return standup.Blockers
+	}
+
+	texts := []string{}
+	if standup.Blockers != "" {
+		texts = append(texts, standup.Blockers)
+	}
+	for _, blocker := range blockers {
+		if blocker.UserID != standup.UserID || blocker.StandupID == standup.ID {
+			continue
+		}
+		if contains(texts, blocker.Text) {
+			continue
+		}
+		texts = append(texts, blocker.Text)
+	}
+	return strings.Join(texts, "\n")
+}
+
+// trackBlocker keeps blocker of the standup in sync with its problem section.
+// New blockers are sent to project managers of the channel
+func (bot *Bot) trackBlocker(standup model.Standup, text string) {
+	blockers, err := bot.db.ListOpenBlockers(standup.ChannelID)
+	if err != nil {
+		log.Error("ListOpenBlockers failed: ", err)
+		return
+	}
+
+	for _, blocker := range blockers {
+		if blocker.StandupID != standup.ID {
+			continue
+		}
+		if text == "" {
+			err = bot.db.DeleteBlocker(blocker.ID)
+			if err != nil {
+				log.Error("DeleteBlocker failed: ", err)
+			}
+			return
+		}
+		if blocker.Text != text {
+			blocker.Text = text
+			_, err = bot.db.UpdateBlocker(blocker)
+			if err != nil {
+				log.Error("UpdateBlocker failed: ", err)
+			}
+		}
+		return
+	}
+
+	if text == "" {
+		return
+	}
+
+	for _, blocker := range blockers {
+		if blocker.UserID == standup.UserID && blocker.Text == text {
+			return
+		}
+	}
+
+	blocker, err := bot.db.CreateBlocker(model.Blocker{
+		CreatedAt:   time.Now().Unix(),
+		WorkspaceID: standup.WorkspaceID,
+		ChannelID:   standup.ChannelID,
+		UserID:      standup.UserID,
+		StandupID:   standup.ID,
+		Text:        text,
+	})
+	if err != nil {
+		log.Error("CreateBlocker failed: ", err)
+		return
+	}
+
+	bot.escalateBlocker(blocker)
+}
+
+func (bot *Bot) escalateBlocker(blocker model.Blocker) {
+	standupers, err := bot.db.ListProjectStandupers(blocker.ChannelID)
+	if err != nil {
+		log.Error("ListProjectStandupers failed: ", err)
+		return
+	}
+
+	for _, standuper := range standupers {
+		if standuper.Role != model.RolePM || standuper.UserID == blocker.UserID {
+			continue
+		}
+
+		text, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+			DefaultMessage: &i18n.Message{
+				ID:    "escalateBlocker",
+				Other: "<@{{.user}}> has a blocker in <#{{.channel}}>: {{.text}}\nUse `/blockers resolve {{.id}}` when it is resolved",
+			},
+			TemplateData: map[string]interface{}{
+				"user":    blocker.UserID,
+				"channel": blocker.ChannelID,
+				"text":    blocker.Text,
+				"id":      blocker.ID,
+			},
+		})
+		if err != nil {
+			log.Error(err)
+		}
+
+		err = bot.send(&Message{
+			Type: "direct",
+			User: standuper.UserID,
+			Text: text,
+		})
+		if err != nil {
+			log.Error("escalateBlocker failed: ", err)
+		}
+	}
+}
+
+func (bot *Bot) blockersCommand(command slack.SlashCommand) string {
+	fields := strings.Fields(command.Text)
+	if len(fields) == 2 && fields[0] == "resolve" {
+		return bot.resolveBlocker(command, fields[1])
+	}
+
+	blockers, err := bot.db.ListOpenBlockers(command.ChannelID)
+	if err != nil {
+		log.Error("ListOpenBlockers failed: ", err)
+	}
+
+	if len(blockers) == 0 {
+		msg, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+			DefaultMessage: &i18n.Message{
+				ID:    "noOpenBlockers",
+				Other: "No open blockers in this channel",
+			},
+		})
+		if err != nil {
+			log.Error(err)
+		}
+		return msg
+	}
+
+	list := []string{}
+	for _, blocker := range blockers {
+		list = append(list, fmt.Sprintf("%d. <@%s>: %s", blocker.ID, blocker.UserID, blocker.Text))
+	}
+	return strings.Join(list, "\n")
+}
+
+func (bot *Bot) resolveBlocker(command slack.SlashCommand, param string) string {
+	id, err := strconv.ParseInt(param, 10, 64)
+	if err != nil {
+		return bot.blockerNotFound(param)
+	}
+
+	blocker, err := bot.db.GetBlocker(id)
+	if err != nil || blocker.ChannelID != command.ChannelID || blocker.Resolved {
+		return bot.blockerNotFound(param)
+	}
+
+	if blocker.UserID != command.UserID && !bot.CanManageProject(command.UserID, command.ChannelID) {
+		msg, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+			DefaultMessage: &i18n.Message{
+				ID:    "onlyOwnerCanResolveBlocker",
+				Other: "Only the author of the blocker, PMs of the project and workspace admins can resolve it",
+			},
+		})
+		if err != nil {
+			log.Error(err)
+		}
+		return msg
+	}
+
+	blocker.Resolved = true
+	blocker.ResolvedAt = time.Now().Unix()
+	blocker.ResolvedBy = command.UserID
+
+	_, err = bot.db.UpdateBlocker(blocker)
+	if err != nil {
+		log.Error("UpdateBlocker failed: ", err)
+		msg, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+			DefaultMessage: &i18n.Message{
+				ID:    "failedResolveBlocker",
+				Other: "Failed to resolve blocker",
+			},
+		})
+		if err != nil {
+			log.Error(err)
+		}
+		return msg
+	}
+
+	msg, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+		DefaultMessage: &i18n.Message{
+			ID:    "blockerResolved",
+			Other: "Blocker of <@{{.user}}> is resolved: {{.text}}",
+		},
+		TemplateData: map[string]interface{}{
+			"user": blocker.UserID,
+			"text": blocker.Text,
+		},
+	})
+	if err != nil {
+		log.Error(err)
+	}
+	return msg
+}
+
+func (bot *Bot) blockerNotFound(id string) string {
+	msg, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+		DefaultMessage: &i18n.Message{
+			ID:    "blockerNotFound",
+			Other: "There is no open blocker {{.id}} in this channel",
+		},
+		TemplateData: map[string]interface{}{
+			"id": id,
+		},
+	})
+	if err != nil {
+		log.Error(err)
+	}
+	return msg
+}
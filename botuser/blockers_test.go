@@ -0,0 +1,98 @@
+package botuser
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/maddevsio/comedian/model"
+	"github.com/nlopes/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrackBlockers(t *testing.T) {
+	standup, err := bot.db.CreateStandup(model.Standup{
+		WorkspaceID: "testTeam",
+		ChannelID:   "BLOCK1",
+		UserID:      "foo",
+		MessageTS:   "1",
+		Blockers:    "CI is down",
+	})
+	require.NoError(t, err)
+
+	bot.trackBlocker(standup, "CI is down")
+	blockers, err := bot.db.ListOpenBlockers("BLOCK1")
+	require.NoError(t, err)
+	require.Equal(t, 1, len(blockers))
+	assert.Equal(t, standup.ID, blockers[0].StandupID)
+
+	bot.trackBlocker(standup, "CI is down again")
+	blockers, err = bot.db.ListOpenBlockers("BLOCK1")
+	require.NoError(t, err)
+	require.Equal(t, 1, len(blockers))
+	assert.Equal(t, "CI is down again", blockers[0].Text)
+
+	next := model.Standup{ID: standup.ID + 100, ChannelID: "BLOCK1", UserID: "foo", Blockers: "no access to staging"}
+	assert.Equal(t, "no access to staging\nCI is down again", bot.carryBlockers(next))
+
+	next.Blockers = ""
+	assert.Equal(t, "CI is down again", bot.carryBlockers(next))
+
+	next.UserID = "bar"
+	assert.Equal(t, "", bot.carryBlockers(next))
+
+	resp := bot.ImplementCommands(slack.SlashCommand{
+		Command:   "/blockers",
+		TeamID:    "testTeam",
+		UserID:    "pm1",
+		ChannelID: "BLOCK1",
+	})
+	assert.Equal(t, fmt.Sprintf("%d. <@foo>: CI is down again", blockers[0].ID), resp)
+
+	resp = bot.ImplementCommands(slack.SlashCommand{
+		Command:   "/blockers",
+		TeamID:    "testTeam",
+		UserID:    "pm1",
+		ChannelID: "BLOCK1",
+		Text:      "resolve 100500",
+	})
+	assert.Equal(t, "There is no open blocker 100500 in this channel", resp)
+
+	// blockers of others are resolved only by PMs and admins
+	resp = bot.ImplementCommands(slack.SlashCommand{
+		Command:   "/blockers",
+		TeamID:    "testTeam",
+		UserID:    "bar",
+		ChannelID: "BLOCK1",
+		Text:      fmt.Sprintf("resolve %d", blockers[0].ID),
+	})
+	assert.Equal(t, "Only the author of the blocker, PMs of the project and workspace admins can resolve it", resp)
+
+	pm, err := bot.db.CreateStanduper(model.Standuper{WorkspaceID: "testTeam", ChannelID: "BLOCK1", UserID: "pm1", Role: model.RolePM})
+	require.NoError(t, err)
+	defer bot.db.DeleteStanduper(pm.ID)
+
+	resp = bot.ImplementCommands(slack.SlashCommand{
+		Command:   "/blockers",
+		TeamID:    "testTeam",
+		UserID:    "pm1",
+		ChannelID: "BLOCK1",
+		Text:      fmt.Sprintf("resolve %d", blockers[0].ID),
+	})
+	assert.Equal(t, "Blocker of <@foo> is resolved: CI is down again", resp)
+
+	resp = bot.ImplementCommands(slack.SlashCommand{
+		Command:   "/blockers",
+		TeamID:    "testTeam",
+		UserID:    "pm1",
+		ChannelID: "BLOCK1",
+	})
+	assert.Equal(t, "No open blockers in this channel", resp)
+
+	blocker, err := bot.db.GetBlocker(blockers[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, "pm1", blocker.ResolvedBy)
+
+	next.UserID = "foo"
+	assert.Equal(t, "", bot.carryBlockers(next))
+}
@@ -1,14 +1,20 @@
 package botuser
 
 import (
+	"database/sql"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/maddevsio/comedian/config"
+	"github.com/maddevsio/comedian/messenger"
 	"github.com/maddevsio/comedian/model"
 	"github.com/maddevsio/comedian/storage"
+	"github.com/maddevsio/comedian/webhook"
 	"github.com/nicksnyder/go-i18n/v2/i18n"
 	"github.com/nlopes/slack"
 	log "github.com/sirupsen/logrus"
@@ -23,6 +29,7 @@ var (
 var problemKeys = []string{"issue", "мешает"}
 var todayPlansKeys = []string{"today", "сегодня"}
 var yesterdayWorkKeys = []string{"yesterday", "friday", "вчера", "пятниц"}
+var noProblemsValues = []string{"no", "none", "nothing", "нет", "ничего"}
 
 //Message represent any message that can be send to Slack or any other destination
 type Message struct {
@@ -36,28 +43,48 @@ type Message struct {
 // Bot struct used for storing and communicating with slack api
 type Bot struct {
 	conf      *config.Config
-	db        *storage.DB
+	db        storage.Store
 	localizer *i18n.Localizer
 	workspace *model.Workspace
-	slack     *slack.Client
+	chat      messenger.Messenger
 	bundle    *i18n.Bundle
 	quitChan  chan struct{}
+	replicaID string
+	webhooks  *webhook.Sender
+	work      WorkSource
 }
 
 //New creates new Bot instance
-func New(config *config.Config, bundle *i18n.Bundle, settings model.Workspace, db *storage.DB) *Bot {
+func New(config *config.Config, bundle *i18n.Bundle, settings model.Workspace, db storage.Store) *Bot {
 	bot := &Bot{
 		conf:      config,
 		db:        db,
-		slack:     slack.New(settings.BotAccessToken),
+		chat:      newMessenger(config, settings),
 		workspace: &settings,
 		bundle:    bundle,
 		localizer: i18n.NewLocalizer(bundle, settings.Language),
+		replicaID: replicaID(config),
+		webhooks:  webhook.NewSender(webhookTimeout(config)),
+		work:      newWorkSource(config, settings),
 	}
 	bot.quitChan = make(chan struct{})
 	return bot
 }
 
+// newMessenger picks chat platform of the workspace
+func newMessenger(conf *config.Config, settings model.Workspace) messenger.Messenger {
+	if conf == nil {
+		return messenger.NewSlack(settings.BotAccessToken)
+	}
+	switch settings.Platform {
+	case model.PlatformMattermost:
+		return messenger.NewMattermost(conf.MattermostURL, settings.BotAccessToken, settings.WorkspaceID)
+	case model.PlatformTelegram:
+		return messenger.NewTelegram(conf.TelegramAPIURL, settings.BotAccessToken)
+	}
+	return messenger.NewSlack(settings.BotAccessToken)
+}
+
 //Start updates Users list and launches notifications
 func (bot *Bot) Start() {
 	var wg sync.WaitGroup
@@ -66,32 +93,24 @@ func (bot *Bot) Start() {
 
 	wg.Add(1)
 	go func() {
-		ticker := time.NewTicker(time.Second * 60).C
+		ticker := time.NewTicker(schedulerInterval).C
 		for {
 			select {
-			case <-ticker:
-				err := bot.notifyChannels()
-				if err != nil {
-					log.Error("notifyChannels failed: ", err)
-				}
-				err = bot.CallDisplayYesterdayTeamReport()
-				if err != nil {
-					log.Error("CallDisplayYesterdayTeamReport failed: ", err)
-				}
-				err = bot.CallDisplayWeeklyTeamReport()
-				if err != nil {
-					log.Error("CallDisplayWeeklyTeamReport failed: ", err)
-				}
-				err = bot.remindAboutWorklogs()
-				if err != nil {
-					log.Error("remindAboutWorklogs failed: ", err)
+			case now := <-ticker:
+				if bot.holdLease(now) {
+					bot.runJobs(now)
 				}
 			case <-bot.quitChan:
+				bot.releaseLease()
 				wg.Done()
 				return
 			}
 		}
 	}()
+
+	if chat, ok := bot.chat.(*messenger.Telegram); ok {
+		go bot.pollTelegram(chat)
+	}
 }
 
 func (bot *Bot) send(msg *Message) error {
@@ -124,6 +143,17 @@ func (bot *Bot) Stop() {
 
 //HandleMessage handles slack message event
 func (bot *Bot) HandleMessage(msg *slack.MessageEvent) error {
+	if msg.SubType == typeMessage && isDirectChannel(msg.Channel, msg.User) {
+		handled, err := bot.handleStandupAnswer(msg)
+		if err != nil {
+			log.Error("STANDUP ANSWER FAILED: ", err)
+			return err
+		}
+		if handled {
+			return nil
+		}
+	}
+
 	if !strings.Contains(msg.Msg.Text, bot.workspace.BotUserID) {
 		return nil
 	}
@@ -152,8 +182,9 @@ func (bot *Bot) HandleMessage(msg *slack.MessageEvent) error {
 }
 
 func (bot *Bot) handleNewMessage(msg *slack.MessageEvent) (string, error) {
+	rules := bot.standupRules(msg.Channel)
 
-	problem := bot.analizeStandup(msg.Msg.Text)
+	problem := bot.analizeStandup(msg.Msg.Text, rules)
 	if problem != "" {
 		err := bot.send(&Message{
 			Type:    "ephemeral",
@@ -164,24 +195,30 @@ func (bot *Bot) handleNewMessage(msg *slack.MessageEvent) (string, error) {
 		return problem, err
 	}
 
-	_, err := bot.db.CreateStandup(model.Standup{
+	done, planned, blockers := parseStandup(msg.Msg.Text, rules)
+
+	standup := model.Standup{
 		CreatedAt:   time.Now().Unix(),
 		WorkspaceID: msg.Team,
 		ChannelID:   msg.Channel,
 		UserID:      msg.User,
 		Comment:     msg.Msg.Text,
+		Done:        done,
+		Planned:     planned,
+		Blockers:    blockers,
 		MessageTS:   msg.Msg.Timestamp,
-	})
+	}
+	standup.Blockers = bot.carryBlockers(standup)
+
+	standup, err := bot.db.CreateStandup(standup)
 	if err != nil {
 		return "", err
 	}
-	item := slack.ItemRef{
-		Channel:   msg.Channel,
-		Timestamp: msg.Msg.Timestamp,
-		File:      "",
-		Comment:   "",
-	}
-	err = bot.slack.AddReaction("heavy_check_mark", item)
+	bot.trackBlocker(standup, blockers)
+	bot.dropStandupDraft(standup.UserID, standup.ChannelID)
+	bot.emit(model.EventStandupCreated, standup)
+
+	err = bot.chat.AddReaction(msg.Channel, msg.Msg.Timestamp, "heavy_check_mark")
 	if err != nil {
 		return "", err
 	}
@@ -189,7 +226,9 @@ func (bot *Bot) handleNewMessage(msg *slack.MessageEvent) (string, error) {
 }
 
 func (bot *Bot) handleEditMessage(msg *slack.MessageEvent) (string, error) {
-	problem := bot.analizeStandup(msg.SubMessage.Text)
+	rules := bot.standupRules(msg.Channel)
+
+	problem := bot.analizeStandup(msg.SubMessage.Text, rules)
 	if problem != "" {
 		err := bot.send(&Message{
 			Type:    "ephemeral",
@@ -200,35 +239,46 @@ func (bot *Bot) handleEditMessage(msg *slack.MessageEvent) (string, error) {
 		return problem, err
 	}
 
+	done, planned, blockers := parseStandup(msg.SubMessage.Text, rules)
+
 	standup, err := bot.db.SelectStandupByMessageTS(msg.SubMessage.Timestamp)
 	if err == nil {
 		standup.Comment = msg.SubMessage.Text
+		standup.Done = done
+		standup.Planned = planned
+		standup.Blockers = blockers
+		standup.Blockers = bot.carryBlockers(standup)
 		_, err := bot.db.UpdateStandup(standup)
 		if err != nil {
 			return "", err
 		}
+		bot.trackBlocker(standup, blockers)
+		bot.emit(model.EventStandupUpdated, standup)
 		return "standup updated", nil
 	}
 
-	standup, err = bot.db.CreateStandup(model.Standup{
+	standup = model.Standup{
 		CreatedAt:   time.Now().Unix(),
 		WorkspaceID: msg.Team,
 		ChannelID:   msg.Channel,
 		UserID:      msg.SubMessage.User,
 		Comment:     msg.SubMessage.Text,
+		Done:        done,
+		Planned:     planned,
+		Blockers:    blockers,
 		MessageTS:   msg.SubMessage.Timestamp,
-	})
+	}
+	standup.Blockers = bot.carryBlockers(standup)
+
+	standup, err = bot.db.CreateStandup(standup)
 	if err != nil {
 		return "", err
 	}
+	bot.trackBlocker(standup, blockers)
+	bot.dropStandupDraft(standup.UserID, standup.ChannelID)
+	bot.emit(model.EventStandupCreated, standup)
 
-	item := slack.ItemRef{
-		Channel:   msg.Channel,
-		Timestamp: msg.SubMessage.Timestamp,
-		File:      "",
-		Comment:   "",
-	}
-	err = bot.slack.AddReaction("heavy_check_mark", item)
+	err = bot.chat.AddReaction(msg.Channel, msg.SubMessage.Timestamp, "heavy_check_mark")
 	if err != nil {
 		return "", err
 	}
@@ -246,122 +296,209 @@ func (bot *Bot) handleDeleteMessage(msg *slack.MessageEvent) (string, error) {
 	if err != nil {
 		return "", err
 	}
+	bot.trackBlocker(standup, "")
+	bot.emit(model.EventStandupDeleted, standup)
 
 	return "standup deleted", nil
 }
 
-func (bot *Bot) submittedStandupToday(userID, channelID string) bool {
-	standup, err := bot.db.SelectLatestStandupByUser(userID, channelID)
-	if err != nil {
-		return false
-	}
+// submittedStandupOn returns true if user submitted standup in the channel on the
+// day of at, days are counted in the time zone of at
+func (bot *Bot) submittedStandupOn(userID, channelID string, at time.Time) bool {
+	dayStart := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, at.Location())
+	dayEnd := dayStart.AddDate(0, 0, 1).Add(-time.Second)
 
-	userProfile, err := bot.slack.GetUserInfo(userID)
+	_, err := bot.db.GetStandupForPeriod(userID, channelID, dayStart.Unix(), dayEnd.Unix())
 	if err != nil {
-		log.Error(err)
+		if err != sql.ErrNoRows {
+			log.Error("GetStandupForPeriod failed: ", err)
+		}
 		return false
 	}
 
-	loc := time.FixedZone(userProfile.TZ, userProfile.TZOffset)
-
-	if time.Unix(standup.CreatedAt, 0).Day() == time.Now().UTC().In(loc).Day() {
-		log.Info("not non reporter: ", userID)
-		return true
-	}
-	return false
+	log.Info("not non reporter: ", userID)
+	return true
 }
 
-func (bot *Bot) analizeStandup(message string) string {
+func (bot *Bot) analizeStandup(message string, rules []standupRule) string {
 	errors := []string{}
 	message = strings.ToLower(message)
 
-	var mentionsYesterdayWork, mentionsTodayPlans, mentionsProblem bool
+	for _, rule := range rules {
+		if !rule.required || mentionsAny(message, rule.keys) {
+			continue
+		}
+		warnings, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+			DefaultMessage: sectionWarnings[rule.section],
+			TemplateData: map[string]interface{}{
+				"Keywords": strings.Join(rule.keys, ", "),
+			},
+		})
+		if err != nil {
+			log.Error(err)
+		}
+		errors = append(errors, warnings)
+	}
+	return strings.Join(errors, ", ")
+}
 
-	for _, work := range yesterdayWorkKeys {
-		if strings.Contains(message, work) {
-			mentionsYesterdayWork = true
+func mentionsAny(message string, keys []string) bool {
+	for _, key := range keys {
+		if start, _ := findKeyword(message, key); start >= 0 {
+			return true
 		}
 	}
+	return false
+}
+
+// findKeyword returns position of the first word of message starting with keyword
+// and the end of the word. Keywords may be word stems, so the word may go on after
+// keyword, but keyword inside of a word or of compound like "issues-tracker" is not found
+func findKeyword(message, key string) (int, int) {
+	if key == "" {
+		return -1, -1
+	}
 
-	for _, plan := range todayPlansKeys {
-		if strings.Contains(message, plan) {
-			mentionsTodayPlans = true
+	for offset := 0; offset < len(message); {
+		i := strings.Index(message[offset:], key)
+		if i < 0 {
+			break
 		}
+		start := offset + i
+
+		end := start + len(key)
+		for end < len(message) {
+			r, size := utf8.DecodeRuneInString(message[end:])
+			if !unicode.IsLetter(r) {
+				break
+			}
+			end += size
+		}
+
+		if !joinsWordBefore(message, start) && !joinsWordAfter(message, end) {
+			return start, end
+		}
+
+		_, size := utf8.DecodeRuneInString(message[start:])
+		offset = start + size
 	}
 
-	for _, problem := range problemKeys {
-		if strings.Contains(message, problem) {
-			mentionsProblem = true
+	return -1, -1
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// joinsWordBefore tells if text before i is a part of the same word, hyphen joins words
+func joinsWordBefore(message string, i int) bool {
+	r, size := utf8.DecodeLastRuneInString(message[:i])
+	if size == 0 {
+		return false
+	}
+	if r == '-' {
+		r, size = utf8.DecodeLastRuneInString(message[:i-size])
+		return size > 0 && isWordRune(r)
+	}
+	return isWordRune(r)
+}
+
+// joinsWordAfter tells if text after i is a part of the same word, hyphen joins words
+func joinsWordAfter(message string, i int) bool {
+	r, size := utf8.DecodeRuneInString(message[i:])
+	if size == 0 {
+		return false
+	}
+	if r == '-' {
+		r, size = utf8.DecodeRuneInString(message[i+size:])
+		return size > 0 && isWordRune(r)
+	}
+	return isWordRune(r)
+}
+
+type standupSection struct {
+	start int
+	end   int
+	text  *string
+}
+
+// parseStandup splits standup message into what was done, what is planned and blockers.
+// Every section starts at the first keyword of its kind and lasts until the next section
+func parseStandup(message string, rules []standupRule) (string, string, string) {
+	var done, planned, blockers string
+	texts := map[string]*string{
+		model.SectionYesterday: &done,
+		model.SectionToday:     &planned,
+		model.SectionProblems:  &blockers,
+	}
+
+	// keywords are searched in lowercase, original text is kept only when byte offsets match
+	lower := strings.ToLower(message)
+	if len(lower) != len(message) {
+		message = lower
+	}
+
+	sections := []standupSection{}
+	for _, rule := range rules {
+		s := findStandupSection(lower, rule.keys, texts[rule.section])
+		if s.start >= 0 {
+			sections = append(sections, s)
 		}
 	}
 
-	if !mentionsYesterdayWork {
-		warnings, err := bot.localizer.Localize(&i18n.LocalizeConfig{
-			DefaultMessage: &i18n.Message{
-				ID:    "noYesterdayMention",
-				Other: "- no 'yesterday' keywords detected: {{.Keywords}}",
-			},
-			TemplateData: map[string]interface{}{
-				"Keywords": strings.Join(yesterdayWorkKeys, ", "),
-			},
-		})
-		if err != nil {
-			log.Error(err)
+	sort.Slice(sections, func(i, j int) bool { return sections[i].start < sections[j].start })
+
+	for i, s := range sections {
+		end := len(message)
+		if i+1 < len(sections) {
+			end = sections[i+1].start
 		}
-		errors = append(errors, warnings)
+		if s.end >= end {
+			continue
+		}
+		text := strings.TrimLeft(message[s.end:end], " \t\r\n:-–—,.")
+		*s.text = strings.TrimRight(text, " \t\r\n,;.")
 	}
-	if !mentionsTodayPlans {
-		warnings, err := bot.localizer.Localize(&i18n.LocalizeConfig{
-			DefaultMessage: &i18n.Message{
-				ID:    "noTodayMention",
-				Other: "- no 'today' keywords detected: {{.Keywords}}",
-			},
-			TemplateData: map[string]interface{}{
-				"Keywords": strings.Join(todayPlansKeys, ", "),
-			},
-		})
-		if err != nil {
-			log.Error(err)
+
+	for _, value := range noProblemsValues {
+		if strings.ToLower(strings.Trim(blockers, " .!")) == value {
+			blockers = ""
 		}
-		errors = append(errors, warnings)
 	}
-	if !mentionsProblem {
-		warnings, err := bot.localizer.Localize(&i18n.LocalizeConfig{
-			DefaultMessage: &i18n.Message{
-				ID:    "noProblemsMention",
-				Other: "- no 'problems' keywords detected: {{.Keywords}}",
-			},
-			TemplateData: map[string]interface{}{
-				"Keywords": strings.Join(problemKeys, ", "),
-			},
-		})
-		if err != nil {
-			log.Error(err)
+
+	return done, planned, blockers
+}
+
+// findStandupSection returns position of the first keyword found in message.
+// Section header ends with the word keyword starts
+func findStandupSection(message string, keys []string, text *string) standupSection {
+	section := standupSection{start: -1, end: -1, text: text}
+
+	for _, key := range keys {
+		start, end := findKeyword(message, key)
+		if start < 0 || (section.start >= 0 && start >= section.start) {
+			continue
 		}
-		errors = append(errors, warnings)
+		section.start = start
+		section.end = end
 	}
-	return strings.Join(errors, ", ")
+
+	return section
 }
 
 // SendMessage posts a message in a specified channel visible for everyone
 func (bot *Bot) SendMessage(channel, message string, attachments []slack.Attachment) error {
-	_, _, err := bot.slack.PostMessage(channel, message, slack.PostMessageParameters{Attachments: attachments})
-	return err
+	return bot.chat.SendMessage(channel, message, attachments)
 }
 
 // SendEphemeralMessage posts a message in a specified channel which is visible only for selected user
 func (bot *Bot) SendEphemeralMessage(channel, user, message string) error {
-	_, err := bot.slack.PostEphemeral(channel, user, slack.MsgOptionText(message, true))
-	return err
+	return bot.chat.SendEphemeralMessage(channel, user, message)
 }
 
 // SendUserMessage Direct Message specific user
 func (bot *Bot) SendUserMessage(userID, message string) error {
-	_, _, channelID, err := bot.slack.OpenIMChannel(userID)
-	if err != nil {
-		return err
-	}
-	return bot.SendMessage(channelID, message, nil)
+	return bot.chat.SendUserMessage(userID, message)
 }
 
 //HandleJoin handles comedian joining channel
@@ -376,7 +513,7 @@ func (bot *Bot) HandleJoin(joinEvent *slack.MemberJoinedChannelEvent) (model.Pro
 		return newChannel, nil
 	}
 
-	channel, err := bot.slack.GetConversationInfo(joinEvent.Channel, true)
+	channel, err := bot.chat.GetChannel(joinEvent.Channel)
 	if err != nil {
 		return newChannel, err
 	}
@@ -389,6 +526,7 @@ func (bot *Bot) HandleJoin(joinEvent *slack.MemberJoinedChannelEvent) (model.Pro
 		TZ:               "Asia/Bishkek",
 		OnbordingMessage: "Hello and welcome to " + channel.Name,
 		SubmissionDays:   "monday, tuesday, wednesday, thursday, friday",
+		DMPromptOffset:   defaultDMPromptOffset,
 	})
 	if err != nil {
 		return newChannel, err
@@ -400,6 +538,10 @@ func (bot *Bot) HandleJoin(joinEvent *slack.MemberJoinedChannelEvent) (model.Pro
 func (bot *Bot) ImplementCommands(command slack.SlashCommand) string {
 	log.Info("Bot to implement command: ", bot.workspace)
 
+	if !bot.isAllowed(command) {
+		return bot.notAllowed()
+	}
+
 	switch command.Command {
 	case "/start":
 		return bot.joinCommand(command)
@@ -415,6 +557,22 @@ func (bot *Bot) ImplementCommands(command slack.SlashCommand) string {
 		return bot.modifySubmittionDays(command)
 	case "/onbording_message":
 		return bot.modifyOnbordingMessage(command)
+	case "/standup_keywords":
+		return bot.modifyStandupKeywords(command)
+	case "/blockers":
+		return bot.blockersCommand(command)
+	case "/away":
+		return bot.awayCommand(command)
+	case "/my_deadline":
+		return bot.modifyPersonalDeadline(command)
+	case "/my_tz":
+		return bot.modifyPersonalTZ(command)
+	case "/standup":
+		return bot.openStandupModal(command)
+	case "/dm_mode":
+		return bot.modifyDMMode(command)
+	case "/admin":
+		return bot.adminCommand(command)
 	default:
 		return ""
 	}
@@ -434,19 +592,26 @@ func (bot *Bot) Settings() *model.Workspace {
 func (bot *Bot) SetProperties(settings *model.Workspace) *model.Workspace {
 	bot.workspace = settings
 	bot.localizer = i18n.NewLocalizer(bot.bundle, settings.Language)
+	bot.work = newWorkSource(bot.conf, *settings)
 	return bot.workspace
 }
 
-func (bot *Bot) remindAboutWorklogs() error {
-	if time.Now().AddDate(0, 0, 1).Day() != 1 {
-		return nil
+// remindAboutWorklogs asks to check worklogs at 10:00 of the last day of month if it is in (from, to].
+// Worklogs are counted from the start of that month till the reminder time, even when it is caught up later
+func (bot *Bot) remindAboutWorklogs(from, to time.Time) error {
+	var at time.Time
+	for _, t := range occurrences("10:00", time.Local, from, to) {
+		if t.AddDate(0, 0, 1).Day() == 1 {
+			at = t
+		}
 	}
 
-	if time.Now().Hour() != 10 || time.Now().Minute() != 0 {
+	if at.IsZero() || !bot.tracksWorklogs() {
 		return nil
 	}
+	monthStart := time.Date(at.Year(), at.Month(), 1, 0, 0, 0, 0, time.Local)
 
-	users, err := bot.slack.GetUsers()
+	users, err := bot.chat.ListUsers()
 	if err != nil {
 		return err
 	}
@@ -466,7 +631,7 @@ func (bot *Bot) remindAboutWorklogs() error {
 			continue
 		}
 
-		_, _, err = bot.GetCollectorDataOnMember(standupers[0], time.Date(time.Now().Year(), time.Now().Month(), 1, 0, 0, 0, 0, time.Local), time.Now())
+		_, _, err = bot.MemberWork(standupers[0], monthStart, at)
 		if err != nil {
 			log.Error(err)
 			continue
@@ -476,7 +641,7 @@ func (bot *Bot) remindAboutWorklogs() error {
 		var total int
 
 		for _, member := range standupers {
-			user, userInProject, err := bot.GetCollectorDataOnMember(member, time.Date(time.Now().Year(), time.Now().Month(), 1, 0, 0, 0, 0, time.Local), time.Now())
+			user, userInProject, err := bot.MemberWork(member, monthStart, at)
 			if err != nil {
 				log.Error(err)
 				continue
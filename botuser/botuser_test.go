@@ -7,6 +7,7 @@ import (
 	"github.com/maddevsio/comedian/model"
 	"github.com/maddevsio/comedian/storage"
 	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/nlopes/slack"
 	"github.com/stretchr/testify/assert"
 	"golang.org/x/text/language"
 )
@@ -21,10 +22,7 @@ func setupBot() *Bot {
 		return nil
 	}
 
-	db, err := storage.New(config.DatabaseURL, "../migrations")
-	if err != nil {
-		return nil
-	}
+	db := storage.NewMemory()
 
 	settings := model.Workspace{
 		WorkspaceID:    "testTeam",
@@ -48,14 +46,92 @@ func setupBot() *Bot {
 		TZ:          "Asia/Bishkek",
 	})
 
+	bot.db.CreateAdmin(model.Admin{
+		WorkspaceID: "testTeam",
+		UserID:      "foo123",
+	})
+
 	return bot
 }
 
 func TestAnalizeStandup(t *testing.T) {
 
-	errors := bot.analizeStandup("yesterday, today, issues")
+	rules := standupRules(model.Project{})
+
+	errors := bot.analizeStandup("yesterday, today, issues", rules)
 	assert.Equal(t, "", errors)
 
-	errors = bot.analizeStandup("wrong standup")
+	errors = bot.analizeStandup("yesterday, today, issues-tracker", rules)
+	assert.Equal(t, "- no 'problems' keywords detected: issue, мешает", errors)
+
+	errors = bot.analizeStandup("wrong standup", rules)
 	assert.Equal(t, "- no 'yesterday' keywords detected: yesterday, friday, вчера, пятниц, - no 'today' keywords detected: today, сегодня, - no 'problems' keywords detected: issue, мешает", errors)
+
+	rules = standupRules(model.Project{
+		YesterdayKeywords: "Done",
+		TodayKeywords:     "next, plan",
+		ProblemKeywords:   "blocked",
+		OptionalSections:  "problems",
+	})
+
+	errors = bot.analizeStandup("done: tests, next: deploy", rules)
+	assert.Equal(t, "", errors)
+
+	errors = bot.analizeStandup("yesterday, today, issues", rules)
+	assert.Equal(t, "- no 'yesterday' keywords detected: done, - no 'today' keywords detected: next, plan", errors)
+}
+
+func TestParseStandup(t *testing.T) {
+	testCases := []struct {
+		message  string
+		done     string
+		planned  string
+		blockers string
+	}{
+		{"yesterday: fixed login\ntoday: write tests\nissues: none", "fixed login", "write tests", ""},
+		{"Today I will deploy. Yesterday reviewed PRs. Issues: CI is down", "reviewed PRs", "I will deploy", "CI is down"},
+		{"вчера: починил баг\nсегодня: релиз\nмешает: ничего", "починил баг", "релиз", ""},
+		{"wrong standup", "", "", ""},
+		{"yesterday: set up issues-tracker, news-today feed\ntoday: triage\nissues: none", "set up issues-tracker, news-today feed", "triage", ""},
+		{"yesterday: moved mytoday.txt\ntoday: review\nissue: flaky-issue in CI", "moved mytoday.txt", "review", "flaky-issue in CI"},
+	}
+	for _, tc := range testCases {
+		done, planned, blockers := parseStandup(tc.message, standupRules(model.Project{}))
+		assert.Equal(t, tc.done, done, tc.message)
+		assert.Equal(t, tc.planned, planned, tc.message)
+		assert.Equal(t, tc.blockers, blockers, tc.message)
+	}
+}
+
+func TestModifyStandupKeywords(t *testing.T) {
+	command := slack.SlashCommand{
+		Command:   "/standup_keywords",
+		TeamID:    "testTeam",
+		UserID:    "foo123",
+		ChannelID: "CHAN123",
+	}
+
+	command.Text = "today next, plan"
+	resp := bot.ImplementCommands(command)
+	assert.Equal(t, "'yesterday' is required, keywords: yesterday, friday, вчера, пятниц\n'today' is required, keywords: next, plan\n'problems' is required, keywords: issue, мешает", resp)
+
+	command.Text = "problems optional"
+	resp = bot.ImplementCommands(command)
+	assert.Contains(t, resp, "'problems' is optional, keywords: issue, мешает")
+
+	command.Text = "today default"
+	resp = bot.ImplementCommands(command)
+	assert.Contains(t, resp, "'today' is required, keywords: today, сегодня")
+
+	command.Text = "problems required"
+	resp = bot.ImplementCommands(command)
+	assert.Contains(t, resp, "'problems' is required, keywords: issue, мешает")
+
+	command.Text = "tomorrow plans"
+	resp = bot.ImplementCommands(command)
+	assert.Equal(t, "Use '/standup_keywords <section> <keyword, keyword>', '<section> optional', '<section> required' or '<section> default', where section is one of: yesterday, today, problems", resp)
+
+	command.ChannelID = "UNKNOWN"
+	resp = bot.ImplementCommands(command)
+	assert.Equal(t, "Could not change standup keywords", resp)
 }
@@ -0,0 +1,424 @@
+package botuser
+
+import (
+	"database/sql"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/maddevsio/comedian/model"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/nlopes/slack"
+	log "github.com/sirupsen/logrus"
+)
+
+// defaultDMPromptOffset is how many minutes before deadline standupers are asked by default
+const defaultDMPromptOffset = 30
+
+// skipAnswer leaves optional section of standup collected in direct messages empty
+const skipAnswer = "-"
+
+var sectionQuestions = map[string]*i18n.Message{
+	model.SectionYesterday: {
+		ID:    "dmQuestionYesterday",
+		Other: "What did you do yesterday?",
+	},
+	model.SectionToday: {
+		ID:    "dmQuestionToday",
+		Other: "What are you going to do today?",
+	},
+	model.SectionProblems: {
+		ID:    "dmQuestionProblems",
+		Other: "Is anything blocking you?",
+	},
+}
+
+// isDirectChannel tells private conversation of bot with user apart from project channels.
+// Slack IM channel IDs start with D, Telegram private chat ID equals user ID
+func isDirectChannel(channelID, userID string) bool {
+	return strings.HasPrefix(channelID, "D") || channelID == userID
+}
+
+// promptStandupers asks standupers of projects in DM mode for standup in direct
+// messages when their deadline minus project prompt offset is in (from, to]
+func (bot *Bot) promptStandupers(from, to time.Time) error {
+	channels, err := bot.db.ListWorkspaceProjects(bot.workspace.WorkspaceID)
+	if err != nil {
+		return err
+	}
+
+	for _, channel := range channels {
+		if !channel.DMMode {
+			continue
+		}
+
+		standupers, err := bot.db.ListProjectStandupers(channel.ChannelID)
+		if err != nil {
+			log.Error("ListProjectStandupers failed: ", err)
+			continue
+		}
+
+		offset := time.Duration(channel.DMPromptOffset) * time.Minute
+		for _, standuper := range standupers {
+			deadline, loc := standuperSchedule(standuper, channel)
+			if deadline == "" {
+				continue
+			}
+
+			for _, t := range occurrences(deadline, loc, from.Add(offset), to.Add(offset)) {
+				if bot.shouldSubmitStandupIn(&channel, t) && bot.isNonReporter(standuper, t) {
+					err := bot.startStandupDraft(channel, standuper.UserID)
+					if err != nil {
+						log.Errorf("could not ask %v for standup in %v: %v", standuper.UserID, channel.ChannelID, err)
+					}
+					break
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// startStandupDraft starts collecting standup of user in the channel. Standups
+// of several channels are collected one by one, the next one is asked when previous is complete.
+// Draft left from previous days is dropped and started again
+func (bot *Bot) startStandupDraft(channel model.Project, userID string) error {
+	draft, err := bot.db.FindStandupDraft(userID, channel.ChannelID)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	if err == nil {
+		if !bot.isStaleDraft(draft) {
+			return nil
+		}
+		err = bot.db.DeleteStandupDraft(draft.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	draft, err = bot.db.CreateStandupDraft(model.StandupDraft{
+		CreatedAt:   time.Now().Unix(),
+		WorkspaceID: bot.workspace.WorkspaceID,
+		ChannelID:   channel.ChannelID,
+		UserID:      userID,
+		Section:     model.StandupSections[0],
+	})
+	if err != nil {
+		return err
+	}
+
+	drafts, err := bot.db.ListUserStandupDrafts(userID)
+	if err != nil {
+		return err
+	}
+	drafts, err = bot.dropStaleDrafts(drafts)
+	if err != nil {
+		return err
+	}
+	if len(drafts) > 0 && drafts[0].ID != draft.ID {
+		return nil
+	}
+	return bot.askStandupDraft(draft, true)
+}
+
+// askStandupDraft sends question of the section draft is waiting for
+func (bot *Bot) askStandupDraft(draft model.StandupDraft, intro bool) error {
+	question, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+		DefaultMessage: sectionQuestions[draft.Section],
+	})
+	if err != nil {
+		log.Error(err)
+	}
+
+	project, err := bot.db.SelectProject(draft.ChannelID)
+	if err != nil {
+		return err
+	}
+	for _, rule := range standupRules(project) {
+		if rule.section == draft.Section && !rule.required {
+			dmOptionalSection, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+				DefaultMessage: &i18n.Message{
+					ID:    "dmOptionalSection",
+					Other: "(answer {{.skip}} to skip)",
+				},
+				TemplateData: map[string]interface{}{
+					"skip": skipAnswer,
+				},
+			})
+			if err != nil {
+				log.Error(err)
+			}
+			question += " " + dmOptionalSection
+		}
+	}
+
+	if intro {
+		dmPromptIntro, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+			DefaultMessage: &i18n.Message{
+				ID:    "dmPromptIntro",
+				Other: "Time for standup in <#{{.channel}}>! Answer a few questions here and I will post your standup to the channel.",
+			},
+			TemplateData: map[string]interface{}{
+				"channel": draft.ChannelID,
+			},
+		})
+		if err != nil {
+			log.Error(err)
+		}
+		question = dmPromptIntro + "\n" + question
+	}
+
+	return bot.SendUserMessage(draft.UserID, question)
+}
+
+// handleStandupAnswer stores direct message of user as answer to the oldest
+// standup draft of the user. Returns false if user is not asked for standup
+func (bot *Bot) handleStandupAnswer(msg *slack.MessageEvent) (bool, error) {
+	drafts, err := bot.db.ListUserStandupDrafts(msg.User)
+	if err != nil {
+		return false, err
+	}
+	if len(drafts) == 0 {
+		return false, nil
+	}
+
+	// answers to questions of previous days are too late, the next fresh draft is asked instead
+	if bot.isStaleDraft(drafts[0]) {
+		drafts, err = bot.dropStaleDrafts(drafts)
+		if err != nil || len(drafts) == 0 {
+			return false, err
+		}
+		return true, bot.askStandupDraft(drafts[0], true)
+	}
+
+	draft := drafts[0]
+	answer := strings.TrimSpace(msg.Msg.Text)
+	if answer == skipAnswer {
+		answer = ""
+	}
+
+	switch draft.Section {
+	case model.SectionYesterday:
+		draft.Done = answer
+	case model.SectionToday:
+		draft.Planned = answer
+	case model.SectionProblems:
+		draft.Blockers = answer
+	}
+
+	next := nextSection(draft.Section)
+	if next != "" {
+		draft.Section = next
+		_, err = bot.db.UpdateStandupDraft(draft)
+		if err != nil {
+			return true, err
+		}
+		return true, bot.askStandupDraft(draft, false)
+	}
+
+	err = bot.completeStandupDraft(draft)
+	if err != nil {
+		return true, err
+	}
+
+	if len(drafts) > 1 {
+		return true, bot.askStandupDraft(drafts[1], true)
+	}
+	return true, nil
+}
+
+// completeStandupDraft posts collected standup to the project channel on behalf of user
+// and stores it linked to the posted message
+func (bot *Bot) completeStandupDraft(draft model.StandupDraft) error {
+	standup := model.Standup{
+		CreatedAt:   time.Now().Unix(),
+		WorkspaceID: draft.WorkspaceID,
+		ChannelID:   draft.ChannelID,
+		UserID:      draft.UserID,
+	}
+	text, attachments := bot.compileStandup(&standup, map[string]string{
+		model.SectionYesterday: draft.Done,
+		model.SectionToday:     draft.Planned,
+		model.SectionProblems:  draft.Blockers,
+	})
+
+	messageID, err := bot.chat.PostMessage(draft.ChannelID, text, attachments)
+	if err != nil {
+		return err
+	}
+	standup.MessageTS = messageID
+
+	err = bot.db.DeleteStandupDraft(draft.ID)
+	if err != nil {
+		return err
+	}
+
+	_, err = bot.saveCompiledStandup(standup)
+	if err != nil {
+		return err
+	}
+
+	dmStandupPosted, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+		DefaultMessage: &i18n.Message{
+			ID:    "dmStandupPosted",
+			Other: "Thanks! Your standup is posted to <#{{.channel}}>",
+		},
+		TemplateData: map[string]interface{}{
+			"channel": draft.ChannelID,
+		},
+	})
+	if err != nil {
+		log.Error(err)
+	}
+	return bot.SendUserMessage(draft.UserID, dmStandupPosted)
+}
+
+// isStaleDraft returns true if draft was started before the current standup day of the standuper
+func (bot *Bot) isStaleDraft(draft model.StandupDraft) bool {
+	now := bot.standuperNow(draft.UserID, draft.ChannelID)
+	started := time.Unix(draft.CreatedAt, 0).In(now.Location())
+	return started.YearDay() != now.YearDay() || started.Year() != now.Year()
+}
+
+// dropStaleDrafts deletes drafts left from previous days and returns the rest
+func (bot *Bot) dropStaleDrafts(drafts []model.StandupDraft) ([]model.StandupDraft, error) {
+	fresh := []model.StandupDraft{}
+	for _, draft := range drafts {
+		if !bot.isStaleDraft(draft) {
+			fresh = append(fresh, draft)
+			continue
+		}
+		err := bot.db.DeleteStandupDraft(draft.ID)
+		if err != nil {
+			return fresh, err
+		}
+	}
+	return fresh, nil
+}
+
+// dropStandupDraft deletes draft of user in the channel once standup is saved
+// another way, so that user is not asked for it anymore
+func (bot *Bot) dropStandupDraft(userID, channelID string) {
+	draft, err := bot.db.FindStandupDraft(userID, channelID)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Error("FindStandupDraft failed: ", err)
+		}
+		return
+	}
+
+	err = bot.db.DeleteStandupDraft(draft.ID)
+	if err != nil {
+		log.Error("DeleteStandupDraft failed: ", err)
+	}
+}
+
+func nextSection(section string) string {
+	for i, s := range model.StandupSections {
+		if s == section && i+1 < len(model.StandupSections) {
+			return model.StandupSections[i+1]
+		}
+	}
+	return ""
+}
+
+// modifyDMMode turns collecting standups in direct messages on or off.
+// Optional number after "on" sets how many minutes before deadline standupers are asked
+func (bot *Bot) modifyDMMode(command slack.SlashCommand) string {
+	project, err := bot.db.SelectProject(command.ChannelID)
+	if err != nil {
+		standupModalNoProject, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+			DefaultMessage: &i18n.Message{
+				ID:    "standupModalNoProject",
+				Other: "Standups are not collected in this channel",
+			},
+		})
+		if err != nil {
+			log.Error(err)
+		}
+		return standupModalNoProject
+	}
+
+	fields := strings.Fields(strings.ToLower(command.Text))
+	switch {
+	case len(fields) == 0:
+		return bot.showDMMode(project)
+	case fields[0] == "on" && len(fields) <= 2:
+		project.DMMode = true
+		if project.DMPromptOffset == 0 {
+			project.DMPromptOffset = defaultDMPromptOffset
+		}
+		if len(fields) == 2 {
+			minutes, err := strconv.ParseInt(fields[1], 10, 64)
+			if err != nil || minutes < 0 {
+				return bot.dmModeUsage()
+			}
+			project.DMPromptOffset = minutes
+		}
+	case fields[0] == "off" && len(fields) == 1:
+		project.DMMode = false
+	default:
+		return bot.dmModeUsage()
+	}
+
+	project, err = bot.db.UpdateProject(project)
+	if err != nil {
+		log.Error("UpdateProject failed: ", err)
+		failedUpdateDMMode, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+			DefaultMessage: &i18n.Message{
+				ID:    "failedUpdateDMMode",
+				Other: "Failed to change DM mode",
+			},
+		})
+		if err != nil {
+			log.Error(err)
+		}
+		return failedUpdateDMMode
+	}
+
+	return bot.showDMMode(project)
+}
+
+func (bot *Bot) showDMMode(project model.Project) string {
+	if !project.DMMode {
+		dmModeOff, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+			DefaultMessage: &i18n.Message{
+				ID:    "dmModeOff",
+				Other: "Standups are written in the channel",
+			},
+		})
+		if err != nil {
+			log.Error(err)
+		}
+		return dmModeOff
+	}
+
+	dmModeOn, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+		DefaultMessage: &i18n.Message{
+			ID:    "dmModeOn",
+			Other: "Standupers are asked for standup in direct messages {{.minutes}} minutes before deadline",
+		},
+		TemplateData: map[string]interface{}{
+			"minutes": project.DMPromptOffset,
+		},
+	})
+	if err != nil {
+		log.Error(err)
+	}
+	return dmModeOn
+}
+
+func (bot *Bot) dmModeUsage() string {
+	dmModeUsage, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+		DefaultMessage: &i18n.Message{
+			ID:    "dmModeUsage",
+			Other: "Use '/dm_mode on [minutes before deadline]' or '/dm_mode off'",
+		},
+	})
+	if err != nil {
+		log.Error(err)
+	}
+	return dmModeUsage
+}
@@ -0,0 +1,219 @@
+package botuser
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/maddevsio/comedian/messenger"
+	"github.com/maddevsio/comedian/model"
+	"github.com/nlopes/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeChat records messages instead of sending them
+type fakeChat struct {
+	messenger.Messenger
+	posted []string
+	direct []string
+}
+
+func (f *fakeChat) PostMessage(channelID, text string, attachments []slack.Attachment) (string, error) {
+	f.posted = append(f.posted, channelID+": "+text)
+	return fmt.Sprintf("ts%d", len(f.posted)), nil
+}
+
+func (f *fakeChat) SendMessage(channelID, text string, attachments []slack.Attachment) error {
+	f.posted = append(f.posted, channelID+": "+text)
+	return nil
+}
+
+func (f *fakeChat) AddReaction(channelID, messageID, reaction string) error {
+	return nil
+}
+
+func (f *fakeChat) SendUserMessage(userID, text string) error {
+	f.direct = append(f.direct, userID+": "+text)
+	return nil
+}
+
+func TestModifyDMMode(t *testing.T) {
+	command := slack.SlashCommand{
+		Command:   "/dm_mode",
+		TeamID:    "testTeam",
+		UserID:    "foo123",
+		ChannelID: "CHAN123",
+	}
+
+	assert.Equal(t, "Standups are written in the channel", bot.ImplementCommands(command))
+
+	command.Text = "on"
+	assert.Equal(t, "Standupers are asked for standup in direct messages 30 minutes before deadline", bot.ImplementCommands(command))
+
+	command.Text = "on 45"
+	assert.Equal(t, "Standupers are asked for standup in direct messages 45 minutes before deadline", bot.ImplementCommands(command))
+
+	command.Text = "on soon"
+	assert.Equal(t, "Use '/dm_mode on [minutes before deadline]' or '/dm_mode off'", bot.ImplementCommands(command))
+
+	command.Text = "off"
+	assert.Equal(t, "Standups are written in the channel", bot.ImplementCommands(command))
+
+	project, err := bot.db.SelectProject("CHAN123")
+	require.NoError(t, err)
+	assert.False(t, project.DMMode)
+	assert.Equal(t, int64(45), project.DMPromptOffset)
+}
+
+func TestStandupInDirectMessages(t *testing.T) {
+	chat := &fakeChat{}
+	realChat := bot.chat
+	bot.chat = chat
+	defer func() { bot.chat = realChat }()
+
+	project, err := bot.db.SelectProject("CHAN321")
+	require.NoError(t, err)
+	project.OptionalSections = "problems"
+	_, err = bot.db.UpdateProject(project)
+	require.NoError(t, err)
+	defer func() {
+		project.OptionalSections = ""
+		bot.db.UpdateProject(project)
+	}()
+
+	require.NoError(t, bot.startStandupDraft(project, "DMUSER"))
+	// asking again does not start another draft
+	require.NoError(t, bot.startStandupDraft(project, "DMUSER"))
+	require.Equal(t, 1, len(chat.direct))
+	assert.Contains(t, chat.direct[0], "What did you do yesterday?")
+
+	answer := func(text string) {
+		handled, err := bot.handleStandupAnswer(&slack.MessageEvent{Msg: slack.Msg{
+			Channel: "DIM1",
+			User:    "DMUSER",
+			Text:    text,
+		}})
+		require.NoError(t, err)
+		assert.True(t, handled)
+	}
+
+	answer("fixed login")
+	assert.Equal(t, "DMUSER: What are you going to do today?", chat.direct[1])
+	answer("code review")
+	assert.Equal(t, "DMUSER: Is anything blocking you? (answer - to skip)", chat.direct[2])
+	answer("-")
+
+	require.Equal(t, 1, len(chat.posted))
+	assert.Equal(t, "CHAN321: Standup of <@DMUSER>", chat.posted[0])
+	assert.Equal(t, "DMUSER: Thanks! Your standup is posted to <#CHAN321>", chat.direct[3])
+
+	standup, err := bot.db.SelectStandupByMessageTS("ts1")
+	require.NoError(t, err)
+	assert.Equal(t, "fixed login", standup.Done)
+	assert.Equal(t, "code review", standup.Planned)
+	assert.Equal(t, "", standup.Blockers)
+	assert.NoError(t, bot.db.DeleteStandup(standup.ID))
+
+	// user is not asked anymore, so the message is not an answer
+	handled, err := bot.handleStandupAnswer(&slack.MessageEvent{Msg: slack.Msg{User: "DMUSER", Text: "hi"}})
+	require.NoError(t, err)
+	assert.False(t, handled)
+}
+
+func TestPromptStandupers(t *testing.T) {
+	chat := &fakeChat{}
+	realChat := bot.chat
+	bot.chat = chat
+	defer func() { bot.chat = realChat }()
+
+	project, err := bot.db.SelectProject("CHAN321")
+	require.NoError(t, err)
+	project.DMMode = true
+	project.DMPromptOffset = 30
+	project.SubmissionDays = "monday, tuesday, wednesday, thursday, friday, saturday, sunday"
+	_, err = bot.db.UpdateProject(project)
+	require.NoError(t, err)
+	defer func() {
+		project.DMMode = false
+		project.SubmissionDays = ""
+		bot.db.UpdateProject(project)
+	}()
+
+	standuper, err := bot.db.CreateStanduper(model.Standuper{
+		WorkspaceID: "testTeam",
+		UserID:      "PROMPTED",
+		ChannelID:   "CHAN321",
+		ChannelName: "ChannelWithDeadline",
+		RealName:    "Prompted",
+	})
+	require.NoError(t, err)
+	defer bot.db.DeleteStanduper(standuper.ID)
+
+	loc, err := time.LoadLocation("Asia/Bishkek")
+	require.NoError(t, err)
+	deadline := time.Now().In(loc)
+	deadline = time.Date(deadline.Year(), deadline.Month(), deadline.Day(), 12, 0, 0, 0, loc)
+	prompt := deadline.Add(-30 * time.Minute)
+
+	require.NoError(t, bot.promptStandupers(prompt.Add(-time.Minute), prompt.Add(time.Minute)))
+
+	draft, err := bot.db.FindStandupDraft("PROMPTED", "CHAN321")
+	require.NoError(t, err)
+	assert.Equal(t, model.SectionYesterday, draft.Section)
+	assert.NoError(t, bot.db.DeleteStandupDraft(draft.ID))
+}
+
+func TestStaleStandupDraft(t *testing.T) {
+	chat := &fakeChat{}
+	realChat := bot.chat
+	bot.chat = chat
+	defer func() { bot.chat = realChat }()
+
+	project, err := bot.db.SelectProject("CHAN321")
+	require.NoError(t, err)
+
+	stale, err := bot.db.CreateStandupDraft(model.StandupDraft{
+		CreatedAt:   time.Now().AddDate(0, 0, -2).Unix(),
+		WorkspaceID: "testTeam",
+		ChannelID:   "CHAN321",
+		UserID:      "STALE",
+		Section:     model.SectionToday,
+	})
+	require.NoError(t, err)
+
+	// answer to question of previous days is not stored
+	handled, err := bot.handleStandupAnswer(&slack.MessageEvent{Msg: slack.Msg{User: "STALE", Text: "late answer"}})
+	require.NoError(t, err)
+	assert.False(t, handled)
+	_, err = bot.db.FindStandupDraft("STALE", "CHAN321")
+	assert.Equal(t, sql.ErrNoRows, err)
+	assert.Empty(t, chat.posted)
+
+	// stale draft is started again from the first section
+	stale, err = bot.db.CreateStandupDraft(stale)
+	require.NoError(t, err)
+	require.NoError(t, bot.startStandupDraft(project, "STALE"))
+	draft, err := bot.db.FindStandupDraft("STALE", "CHAN321")
+	require.NoError(t, err)
+	assert.NotEqual(t, stale.ID, draft.ID)
+	assert.Equal(t, model.SectionYesterday, draft.Section)
+	require.Equal(t, 1, len(chat.direct))
+
+	// standup written in the channel drops the draft
+	_, err = bot.handleNewMessage(&slack.MessageEvent{Msg: slack.Msg{
+		Team:      "testTeam",
+		Channel:   "CHAN321",
+		User:      "STALE",
+		Text:      "yesterday fixed login, today code review, issues none",
+		Timestamp: "stale1",
+	}})
+	require.NoError(t, err)
+	_, err = bot.db.FindStandupDraft("STALE", "CHAN321")
+	assert.Equal(t, sql.ErrNoRows, err)
+
+	standup, err := bot.db.SelectStandupByMessageTS("stale1")
+	require.NoError(t, err)
+	assert.NoError(t, bot.db.DeleteStandup(standup.ID))
+}
@@ -1,150 +1,166 @@
 package botuser
 
 import (
+	"database/sql"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/maddevsio/comedian/model"
 	"github.com/nicksnyder/go-i18n/v2/i18n"
-	"github.com/olebedev/when"
-	"github.com/olebedev/when/rules/en"
-	"github.com/olebedev/when/rules/ru"
 	log "github.com/sirupsen/logrus"
 )
 
-func (bot *Bot) notifyChannels() error {
-	channels, err := bot.listTeamActiveChannels()
-	if err != nil {
-		return err
-	}
-
-	if len(channels) == 0 {
-		return nil
-	}
+// warnNonReporters warns standupers whose deadline minus reminder offset passed in (from, to]
+func (bot *Bot) warnNonReporters(from, to time.Time) error {
+	offset := time.Duration(bot.workspace.ReminderOffset) * time.Minute
 
-	for _, channel := range channels {
-		err := bot.notify(channel)
+	return bot.forEachDeadline(from.Add(offset), to.Add(offset), func(channel model.Project, nonReporters []string) error {
+		message, err := bot.composeWarnMessage(nonReporters)
 		if err != nil {
-			log.Error(err)
+			return fmt.Errorf("could not compose Warn Message: %v", err)
 		}
-	}
 
-	return nil
+		return bot.send(&Message{
+			Type:    "message",
+			Channel: channel.ChannelID,
+			Text:    message,
+		})
+	})
 }
 
-func (bot *Bot) notify(channel model.Project) error {
-	if !shouldSubmitStandupIn(&channel, time.Now()) {
-		return nil
-	}
-
-	loc, err := time.LoadLocation(channel.TZ)
-	if err != nil {
-		return err
-	}
-
-	w := when.New(nil)
-	w.Add(en.All...)
-	w.Add(ru.All...)
-
-	//the error is ommited here since to get to this stage the channel
-	//needs to have proper standup time
-	r, _ := w.Parse(channel.Deadline, time.Now())
-
-	alarmtime := time.Unix(r.Time.Unix(), 0)
-	warningTime := time.Unix(r.Time.Unix()-bot.workspace.ReminderOffset*60, 0)
-
-	var message string
-
-	switch {
-	case time.Now().In(loc).Hour() == warningTime.Hour() && time.Now().In(loc).Minute() == warningTime.Minute():
-		nonReporters, err := bot.findChannelNonReporters(channel)
+// alarmNonReporters tags standupers whose deadline passed in (from, to] and
+// starts reminding them
+func (bot *Bot) alarmNonReporters(from, to time.Time) error {
+	return bot.forEachDeadline(from, to, func(channel model.Project, nonReporters []string) error {
+		err := bot.addToNotificationThread(channel.ChannelID, nonReporters)
 		if err != nil {
-			return fmt.Errorf("could not get non reporters: %v", err)
+			return err
 		}
 
-		message, err = bot.composeWarnMessage(nonReporters)
+		for _, userID := range nonReporters {
+			bot.emit(model.EventStandupMissed, missedStandup{
+				ChannelID:   channel.ChannelID,
+				ChannelName: channel.ChannelName,
+				UserID:      userID,
+			})
+		}
+
+		message, err := bot.composeAlarmMessage(nonReporters)
 		if err != nil {
-			return fmt.Errorf("could not compose Warn Message: %v", err)
+			return fmt.Errorf("could not compose Alarm Message: %v", err)
 		}
 
-	case time.Now().In(loc).Hour() == alarmtime.Hour() && time.Now().In(loc).Minute() == alarmtime.Minute():
-		threadTime := time.Now().Unix() + bot.conf.NotificationTime*60
+		return bot.send(&Message{
+			Type:    "message",
+			Channel: channel.ChannelID,
+			Text:    message,
+		})
+	})
+}
 
-		nonReporters, err := bot.findChannelNonReporters(channel)
+// forEachDeadline calls notify for every channel with standupers that did not
+// submit standup and whose deadline is in (from, to]
+func (bot *Bot) forEachDeadline(from, to time.Time, notify func(channel model.Project, nonReporters []string) error) error {
+	channels, err := bot.db.ListWorkspaceProjects(bot.workspace.WorkspaceID)
+	if err != nil {
+		return err
+	}
 
+	for _, channel := range channels {
+		standupers, err := bot.db.ListProjectStandupers(channel.ChannelID)
 		if err != nil {
-			return fmt.Errorf("could not get non reporters: %v", err)
+			log.Error("ListProjectStandupers failed: ", err)
+			continue
 		}
 
-		if len(nonReporters) > 0 {
-			usersNonReport := strings.Join(nonReporters, ",")
+		nonReporters := []string{}
+		for _, standuper := range standupers {
+			deadline, loc := standuperSchedule(standuper, channel)
+			if deadline == "" {
+				continue
+			}
 
-			_, err = bot.db.CreateNotificationThread(model.NotificationThread{
-				ChannelID:        channel.ChannelID,
-				UserIDs:          usersNonReport,
-				NotificationTime: threadTime,
-				ReminderCounter:  0,
-			})
-			if err != nil {
-				log.Error("Error on executing CreateNotificationThread ", err, "ChannelID: ", channel.ChannelID)
-				return err
+			for _, t := range occurrences(deadline, loc, from, to) {
+				if bot.shouldSubmitStandupIn(&channel, t) && bot.isNonReporter(standuper, t) {
+					nonReporters = append(nonReporters, standuper.UserID)
+					break
+				}
 			}
 		}
-		message, err = bot.composeAlarmMessage(nonReporters)
+
+		if len(nonReporters) == 0 {
+			continue
+		}
+
+		err = notify(channel, nonReporters)
 		if err != nil {
-			return fmt.Errorf("could not compose Alarm Message: %v", err)
+			log.Errorf("could not notify %v: %v", channel.ChannelID, err)
 		}
 	}
 
-	bot.send(&Message{
-		Type:    "message",
-		Channel: channel.ChannelID,
-		Text:    message,
-	})
+	return nil
+}
 
-	thread, err := bot.db.SelectNotificationsThread(channel.ChannelID)
-	if err != nil && err.Error() != "sql: no rows in result set" {
-		log.Error("Error on executing SelectNotificationsThread! ", err, "ChannelID: ", channel.ChannelID, "ChannelName: ", channel.ChannelName)
+// remindNonReporters repeats reminders of notification threads due by the end of the window
+func (bot *Bot) remindNonReporters(from, to time.Time) error {
+	channels, err := bot.db.ListWorkspaceProjects(bot.workspace.WorkspaceID)
+	if err != nil {
 		return err
 	}
 
-	var remindTime time.Time
+	for _, channel := range channels {
+		thread, err := bot.db.SelectNotificationsThread(channel.ChannelID)
+		if err != nil {
+			if err != sql.ErrNoRows {
+				log.Error("Error on executing SelectNotificationsThread! ", err, "ChannelID: ", channel.ChannelID, "ChannelName: ", channel.ChannelName)
+			}
+			continue
+		}
 
-	remindTime = time.Unix(thread.NotificationTime, 0)
+		if thread.NotificationTime > to.Unix() {
+			continue
+		}
 
-	if time.Now().In(loc).Hour() != remindTime.Hour() || time.Now().In(loc).Minute() != remindTime.Minute() {
-		return nil
+		err = bot.remind(channel, thread)
+		if err != nil {
+			log.Errorf("could not remind in %v: %v", channel.ChannelID, err)
+		}
 	}
 
+	return nil
+}
+
+func (bot *Bot) remind(channel model.Project, thread model.NotificationThread) error {
 	if thread.ReminderCounter >= bot.workspace.MaxReminders {
-		err = bot.db.DeleteNotificationThread(thread.ID)
+		err := bot.db.DeleteNotificationThread(thread.ID)
 		if err != nil {
 			log.Error("Error on executing DeleteNotificationsThread! ", err, "Thread ID: ", thread.ID)
-			return err
 		}
+		return err
 	}
 
-	stillNonReporters := strings.Split(thread.UserIDs, ",")
-
-	var updatedNonReporters string
+	stillNonReporters := []string{}
 
-	for i, nonReport := range stillNonReporters {
-		if bot.submittedStandupToday(nonReport, thread.ChannelID) {
-			stillNonReporters = append(stillNonReporters[:i], stillNonReporters[i+1:]...)
+	for _, nonReport := range strings.Split(thread.UserIDs, ",") {
+		now := bot.standuperNow(nonReport, thread.ChannelID)
+		if bot.submittedStandupOn(nonReport, thread.ChannelID, now) || bot.isAbsent(nonReport, thread.ChannelID, now) {
+			continue
 		}
+		stillNonReporters = append(stillNonReporters, nonReport)
 	}
-	updatedNonReporters = strings.Join(stillNonReporters, ",")
 
-	if len(updatedNonReporters) == 0 {
-		err = bot.db.DeleteNotificationThread(thread.ID)
+	if len(stillNonReporters) == 0 {
+		err := bot.db.DeleteNotificationThread(thread.ID)
 		if err != nil {
 			log.Error("Error on executing DeleteNotificationsThread! ", err, "Thread ID: ", thread.ID)
-			return err
 		}
+		return err
 	}
 
-	message, err = bot.composeRemindMessage(stillNonReporters)
+	updatedNonReporters := strings.Join(stillNonReporters, ",")
+
+	message, err := bot.composeRemindMessage(stillNonReporters)
 	if err != nil {
 		return fmt.Errorf("could not compose Remind Message: %v", err)
 	}
@@ -164,39 +180,107 @@ func (bot *Bot) notify(channel model.Project) error {
 	return bot.db.UpdateNotificationThread(thread.ID, thread.NotificationTime, updatedNonReporters)
 }
 
-func (bot *Bot) listTeamActiveChannels() ([]model.Project, error) {
-	var channels []model.Project
+func (bot *Bot) findChannelNonReporters(project model.Project) ([]string, error) {
+	nonReporters := []string{}
 
-	chs, err := bot.db.ListWorkspaceProjects(bot.workspace.WorkspaceID)
+	standupers, err := bot.db.ListProjectStandupers(project.ChannelID)
 	if err != nil {
-		return channels, err
+		return nonReporters, err
+	}
+	for _, standuper := range standupers {
+		if bot.isNonReporter(standuper, bot.standuperNow(standuper.UserID, standuper.ChannelID)) {
+			nonReporters = append(nonReporters, standuper.UserID)
+		}
 	}
 
-	for _, channel := range chs {
-		if channel.Deadline == "" {
-			continue
+	return nonReporters, nil
+}
+
+// isNonReporter returns true if standuper is not away and has not submitted standup
+// on the day of at, which is in time zone of the standuper
+func (bot *Bot) isNonReporter(standuper model.Standuper, at time.Time) bool {
+	if bot.isAbsent(standuper.UserID, standuper.ChannelID, at) {
+		return false
+	}
+	return !bot.submittedStandupOn(standuper.UserID, standuper.ChannelID, at)
+}
+
+// addToNotificationThread starts reminding users in the channel, users already
+// reminded there are kept in the same thread
+func (bot *Bot) addToNotificationThread(channelID string, userIDs []string) error {
+	threadTime := time.Now().Unix() + bot.conf.NotificationTime*60
+
+	thread, err := bot.db.SelectNotificationsThread(channelID)
+	if err != nil && err != sql.ErrNoRows {
+		log.Error("Error on executing SelectNotificationsThread! ", err, "ChannelID: ", channelID)
+		return err
+	}
+
+	if err == sql.ErrNoRows {
+		_, err = bot.db.CreateNotificationThread(model.NotificationThread{
+			ChannelID:        channelID,
+			UserIDs:          strings.Join(userIDs, ","),
+			NotificationTime: threadTime,
+			ReminderCounter:  0,
+		})
+		if err != nil {
+			log.Error("Error on executing CreateNotificationThread ", err, "ChannelID: ", channelID)
 		}
+		return err
+	}
 
-		channels = append(channels, channel)
+	users := strings.Split(thread.UserIDs, ",")
+	for _, userID := range userIDs {
+		if !contains(users, userID) {
+			users = append(users, userID)
+		}
 	}
 
-	return channels, nil
+	err = bot.db.UpdateNotificationThread(thread.ID, threadTime, strings.Join(users, ","))
+	if err != nil {
+		log.Error("Error on executing UpdateNotificationThread ", err, "ChannelID: ", channelID)
+	}
+	return err
 }
 
-func (bot *Bot) findChannelNonReporters(project model.Project) ([]string, error) {
-	nonReporters := []string{}
+// standuperSchedule returns deadline and location of the standuper.
+// Personal settings of the standuper take precedence over the channel ones
+func standuperSchedule(standuper model.Standuper, channel model.Project) (string, *time.Location) {
+	deadline := channel.Deadline
+	if standuper.Deadline != "" {
+		deadline = standuper.Deadline
+	}
 
-	standupers, err := bot.db.ListProjectStandupers(project.ChannelID)
+	tz := channel.TZ
+	if standuper.TZ != "" {
+		tz = standuper.TZ
+	}
+
+	loc, err := time.LoadLocation(tz)
 	if err != nil {
-		return nonReporters, err
+		log.Errorf("could not load location %v: %v", tz, err)
+		loc = time.Local
 	}
-	for _, standuper := range standupers {
-		if !bot.submittedStandupToday(standuper.UserID, standuper.ChannelID) {
-			nonReporters = append(nonReporters, standuper.UserID)
-		}
+
+	return deadline, loc
+}
+
+// standuperNow returns current time in time zone of standuper in the channel,
+// so "today" of absences matches the one standups are checked for
+func (bot *Bot) standuperNow(userID, channelID string) time.Time {
+	channel, err := bot.db.SelectProject(channelID)
+	if err != nil {
+		log.Error(err)
+		return time.Now()
 	}
 
-	return nonReporters, nil
+	standuper, err := bot.db.FindStansuperByUserID(userID, channelID)
+	if err != nil && err != sql.ErrNoRows {
+		log.Error(err)
+	}
+
+	_, loc := standuperSchedule(standuper, channel)
+	return time.Now().In(loc)
 }
 
 func (bot *Bot) composeWarnMessage(nonReporters []string) (string, error) {
@@ -299,10 +383,27 @@ func (bot *Bot) composeRemindMessage(nonReporters []string) (string, error) {
 	return remindNonReporters, nil
 }
 
-func shouldSubmitStandupIn(channel *model.Project, t time.Time) bool {
+func (bot *Bot) shouldSubmitStandupIn(channel *model.Project, t time.Time) bool {
 	// TODO need to think of how to include translated versions
-	if strings.Contains(channel.SubmissionDays, strings.ToLower(t.Weekday().String())) {
-		return true
+	if !strings.Contains(channel.SubmissionDays, strings.ToLower(t.Weekday().String())) {
+		return false
+	}
+	return !bot.isHoliday(channel, t)
+}
+
+// isHoliday returns true if t is a holiday of the channel workspace or the channel itself
+func (bot *Bot) isHoliday(channel *model.Project, t time.Time) bool {
+	loc, err := time.LoadLocation(channel.TZ)
+	if err == nil {
+		t = t.In(loc)
+	}
+
+	_, err = bot.db.FindHoliday(channel.WorkspaceID, channel.ChannelID, t.Format(model.AbsenceDateFormat))
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Error("FindHoliday failed: ", err)
+		}
+		return false
 	}
-	return false
+	return true
 }
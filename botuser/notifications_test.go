@@ -6,6 +6,7 @@ import (
 
 	"github.com/maddevsio/comedian/model"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestFindChannelNonReporters(t *testing.T) {
@@ -50,3 +51,104 @@ func TestFindChannelNonReporters(t *testing.T) {
 	assert.NoError(t, bot.db.DeleteStanduper(standuper.ID))
 	assert.NoError(t, bot.db.DeleteStandup(standup.ID))
 }
+
+func TestShouldSubmitStandupIn(t *testing.T) {
+	day := time.Date(2026, time.January, 1, 10, 0, 0, 0, time.UTC)
+	channel := model.Project{
+		WorkspaceID:    "testTeam",
+		ChannelID:      "HOLIDAY1",
+		TZ:             "UTC",
+		SubmissionDays: "monday, tuesday, wednesday, thursday, friday",
+	}
+
+	assert.True(t, bot.shouldSubmitStandupIn(&channel, day))
+	assert.False(t, bot.shouldSubmitStandupIn(&channel, day.AddDate(0, 0, 2)))
+
+	holiday, err := bot.db.CreateHoliday(model.Holiday{
+		WorkspaceID: "testTeam",
+		ChannelID:   "HOLIDAY1",
+		Date:        "2026-01-01",
+		Name:        "New Year",
+	})
+	assert.NoError(t, err)
+
+	assert.False(t, bot.shouldSubmitStandupIn(&channel, day))
+	assert.True(t, bot.shouldSubmitStandupIn(&channel, day.AddDate(0, 0, 1)))
+
+	channel.ChannelID = "HOLIDAY2"
+	assert.True(t, bot.shouldSubmitStandupIn(&channel, day))
+
+	assert.NoError(t, bot.db.DeleteHoliday(holiday.ID))
+}
+
+func TestIsNonReporterAbsentInOwnTimeZone(t *testing.T) {
+	// at any moment at least one of the zones is on another day than the server
+	for _, tz := range []string{"Pacific/Kiritimati", "Etc/GMT+12"} {
+		standuper, err := bot.db.CreateStanduper(model.Standuper{
+			WorkspaceID: "testTeam",
+			ChannelID:   "CHAN123",
+			UserID:      "AWAY1",
+			TZ:          tz,
+		})
+		require.NoError(t, err)
+
+		loc, err := time.LoadLocation(tz)
+		require.NoError(t, err)
+		today := time.Now().In(loc).Format(model.AbsenceDateFormat)
+		absence, err := bot.db.CreateAbsence(model.Absence{
+			WorkspaceID: "testTeam",
+			UserID:      "AWAY1",
+			DateFrom:    today,
+			DateTo:      today,
+		})
+		require.NoError(t, err)
+
+		assert.False(t, bot.isNonReporter(standuper, time.Now().In(loc)), tz)
+
+		require.NoError(t, bot.db.DeleteAbsence(absence.ID))
+		require.NoError(t, bot.db.DeleteStanduper(standuper.ID))
+	}
+}
+
+func TestCaughtUpDeadlineChecksItsDay(t *testing.T) {
+	channel, err := bot.db.CreateProject(model.Project{
+		WorkspaceID:    "testTeam",
+		ChannelID:      "LATE123",
+		ChannelName:    "late",
+		Deadline:       "23:30",
+		TZ:             "UTC",
+		SubmissionDays: "monday, tuesday, wednesday, thursday, friday, saturday, sunday",
+	})
+	require.NoError(t, err)
+	standuper, err := bot.db.CreateStanduper(model.Standuper{WorkspaceID: "testTeam", ChannelID: "LATE123", UserID: "LATE1"})
+	require.NoError(t, err)
+
+	// deadline of 23:30 is caught up at 00:15 of the next day
+	deadline := time.Date(2019, 5, 7, 23, 30, 0, 0, time.UTC)
+	nonReporters := func() []string {
+		found := []string{}
+		err := bot.forEachDeadline(deadline.Add(-time.Hour), deadline.Add(45*time.Minute), func(channel model.Project, nonReporters []string) error {
+			if channel.ChannelID == "LATE123" {
+				found = nonReporters
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		return found
+	}
+	assert.Equal(t, []string{"LATE1"}, nonReporters())
+
+	standup, err := bot.db.CreateStandup(model.Standup{
+		CreatedAt:   deadline.Add(-30 * time.Minute).Unix(),
+		WorkspaceID: "testTeam",
+		ChannelID:   "LATE123",
+		UserID:      "LATE1",
+		MessageTS:   "late1",
+	})
+	require.NoError(t, err)
+	assert.Empty(t, nonReporters())
+
+	assert.NoError(t, bot.db.DeleteStandup(standup.ID))
+	assert.NoError(t, bot.db.DeleteStanduper(standuper.ID))
+	assert.NoError(t, bot.db.DeleteProject(channel.ID))
+}
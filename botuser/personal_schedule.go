@@ -0,0 +1,159 @@
+package botuser
+
+import (
+	"strings"
+	"time"
+
+	"github.com/maddevsio/comedian/model"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/nlopes/slack"
+	"github.com/olebedev/when"
+	"github.com/olebedev/when/rules/en"
+	"github.com/olebedev/when/rules/ru"
+	log "github.com/sirupsen/logrus"
+)
+
+// modifyPersonalDeadline sets deadline of the user in the channel, empty text
+// resets it to the channel deadline
+func (bot *Bot) modifyPersonalDeadline(command slack.SlashCommand) string {
+	standuper, err := bot.db.FindStansuperByUserID(command.UserID, command.ChannelID)
+	if err != nil {
+		return bot.notStanduper()
+	}
+
+	deadline := strings.TrimSpace(command.Text)
+
+	if deadline != "" {
+		w := when.New(nil)
+		w.Add(en.All...)
+		w.Add(ru.All...)
+
+		r, err := w.Parse(deadline, time.Now())
+		if err != nil || r == nil {
+			msg, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+				DefaultMessage: &i18n.Message{
+					ID:    "wrongDeadlineFormat",
+					Other: "Could not recognize deadline time. Use 1pm or 13:00 formats",
+				},
+			})
+			if err != nil {
+				log.Error(err)
+			}
+			return msg
+		}
+		deadline = r.Text
+	}
+
+	standuper.Deadline = deadline
+
+	standuper, err = bot.db.UpdateStanduper(standuper)
+	if err != nil {
+		log.Error("UpdateStanduper failed: ", err)
+		return bot.personalScheduleNotSet()
+	}
+
+	return bot.showPersonalSchedule(standuper)
+}
+
+// modifyPersonalTZ sets timezone of the user in the channel, empty text
+// resets it to the channel timezone
+func (bot *Bot) modifyPersonalTZ(command slack.SlashCommand) string {
+	standuper, err := bot.db.FindStansuperByUserID(command.UserID, command.ChannelID)
+	if err != nil {
+		return bot.notStanduper()
+	}
+
+	tz := strings.TrimSpace(command.Text)
+
+	if tz != "" {
+		_, err = time.LoadLocation(tz)
+		if err != nil {
+			msg, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+				DefaultMessage: &i18n.Message{
+					ID:    "failedRecognizeTZ",
+					Other: "Failed to recognize new TZ you entered, double check the tz name and try again",
+				},
+			})
+			if err != nil {
+				log.Error(err)
+			}
+			return msg
+		}
+	}
+
+	standuper.TZ = tz
+
+	standuper, err = bot.db.UpdateStanduper(standuper)
+	if err != nil {
+		log.Error("UpdateStanduper failed: ", err)
+		return bot.personalScheduleNotSet()
+	}
+
+	return bot.showPersonalSchedule(standuper)
+}
+
+func (bot *Bot) showPersonalSchedule(standuper model.Standuper) string {
+	channel, err := bot.db.SelectProject(standuper.ChannelID)
+	if err != nil {
+		log.Error("SelectProject failed: ", err)
+	}
+
+	deadline, loc := standuperSchedule(standuper, channel)
+
+	if deadline == "" {
+		msg, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+			DefaultMessage: &i18n.Message{
+				ID:    "showPersonalNoDeadline",
+				Other: "You have no standup deadline in this channel, your timezone is {{.TZ}}",
+			},
+			TemplateData: map[string]interface{}{
+				"TZ": loc.String(),
+			},
+		})
+		if err != nil {
+			log.Error(err)
+		}
+		return msg
+	}
+
+	msg, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+		DefaultMessage: &i18n.Message{
+			ID:    "showPersonalDeadline",
+			Other: "Your standup deadline in this channel is {{.Deadline}} in {{.TZ}} timezone",
+		},
+		TemplateData: map[string]interface{}{
+			"Deadline": deadline,
+			"TZ":       loc.String(),
+		},
+	})
+	if err != nil {
+		log.Error(err)
+	}
+	return msg
+}
+
+func (bot *Bot) notStanduper() string {
+	msg, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+		DefaultMessage: &i18n.Message{
+			ID:    "notStanduper",
+			Other: "You do not standup yet",
+		},
+	})
+	if err != nil {
+		log.Error(err)
+	}
+	return msg
+}
+
+func (bot *Bot) personalScheduleNotSet() string {
+	msg, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+		DefaultMessage: &i18n.Message{
+			ID:    "personalScheduleNotSet",
+			Other: "Could not change your standup schedule",
+		},
+	})
+	if err != nil {
+		log.Error(err)
+	}
+	return msg
+}
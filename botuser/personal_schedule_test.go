@@ -0,0 +1,87 @@
+package botuser
+
+import (
+	"testing"
+	"time"
+
+	"github.com/maddevsio/comedian/model"
+	"github.com/nlopes/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPersonalTZCommand(t *testing.T) {
+	command := slack.SlashCommand{
+		Command:   "/my_tz",
+		TeamID:    "testTeam",
+		UserID:    "tz123",
+		ChannelID: "CHAN321",
+		Text:      "Europe/Moscow",
+	}
+
+	resp := bot.ImplementCommands(command)
+	assert.Equal(t, "You do not standup yet", resp)
+
+	standuper, err := bot.db.CreateStanduper(model.Standuper{
+		CreatedAt:   time.Now().Unix(),
+		WorkspaceID: "testTeam",
+		UserID:      "tz123",
+		ChannelID:   "CHAN321",
+	})
+	require.NoError(t, err)
+
+	resp = bot.ImplementCommands(command)
+	assert.Equal(t, "Your standup deadline in this channel is 12:00 in Europe/Moscow timezone", resp)
+
+	command.Text = "Mars/Olympus"
+	resp = bot.ImplementCommands(command)
+	assert.Equal(t, "Failed to recognize new TZ you entered, double check the tz name and try again", resp)
+
+	command.Text = ""
+	resp = bot.ImplementCommands(command)
+	assert.Equal(t, "Your standup deadline in this channel is 12:00 in Asia/Bishkek timezone", resp)
+
+	assert.NoError(t, bot.db.DeleteStanduper(standuper.ID))
+}
+
+func TestPersonalDeadlineCommand(t *testing.T) {
+	command := slack.SlashCommand{
+		Command:   "/my_deadline",
+		TeamID:    "testTeam",
+		UserID:    "deadline123",
+		ChannelID: "CHAN123",
+	}
+
+	standuper, err := bot.db.CreateStanduper(model.Standuper{
+		CreatedAt:   time.Now().Unix(),
+		WorkspaceID: "testTeam",
+		UserID:      "deadline123",
+		ChannelID:   "CHAN123",
+		Deadline:    "10am",
+	})
+	require.NoError(t, err)
+
+	resp := bot.ImplementCommands(command)
+	assert.Equal(t, "You have no standup deadline in this channel, your timezone is Asia/Bishkek", resp)
+
+	standuper, err = bot.db.GetStanduper(standuper.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "", standuper.Deadline)
+
+	assert.NoError(t, bot.db.DeleteStanduper(standuper.ID))
+}
+
+func TestStanduperSchedule(t *testing.T) {
+	channel := model.Project{
+		Deadline: "12:00",
+		TZ:       "Asia/Bishkek",
+	}
+
+	deadline, loc := standuperSchedule(model.Standuper{}, channel)
+	assert.Equal(t, "12:00", deadline)
+	assert.Equal(t, "Asia/Bishkek", loc.String())
+
+	deadline, loc = standuperSchedule(model.Standuper{Deadline: "9am", TZ: "Europe/Berlin"}, channel)
+	assert.Equal(t, "9am", deadline)
+	assert.Equal(t, "Europe/Berlin", loc.String())
+}
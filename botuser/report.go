@@ -0,0 +1,356 @@
+package botuser
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/maddevsio/comedian/model"
+	log "github.com/sirupsen/logrus"
+)
+
+// Kinds of reports
+const (
+	ReportDaily  = "daily"
+	ReportWeekly = "weekly"
+)
+
+// Standup statuses of daily report entries
+const (
+	StandupSubmitted   = "submitted"
+	StandupMissing     = "missing"
+	StandupAbsent      = "absent"
+	StandupNotRequired = "not_required"
+)
+
+// Report is work of standupers over a period. It is built once and then posted
+// to chat by reporting jobs or exported by REST API
+type Report struct {
+	Kind    string        `json:"kind"`
+	From    string        `json:"from"`
+	To      string        `json:"to"`
+	Entries []ReportEntry `json:"entries"`
+}
+
+// ReportEntry is work of standuper in a project. Worklogs are in seconds, work
+// not counted is not expected from the standuper or not known to work source
+type ReportEntry struct {
+	ChannelID       string `json:"channel_id"`
+	ChannelName     string `json:"channel_name"`
+	UserID          string `json:"user_id"`
+	RealName        string `json:"real_name"`
+	Role            string `json:"role"`
+	Worklogs        int    `json:"worklogs"`
+	ProjectWorklogs int    `json:"project_worklogs"`
+	WorklogsCounted bool   `json:"worklogs_counted"`
+	WorklogsEmoji   string `json:"worklogs_emoji"`
+	Commits         int    `json:"commits"`
+	ProjectCommits  int    `json:"project_commits"`
+	CommitsCounted  bool   `json:"commits_counted"`
+	CommitsEmoji    string `json:"commits_emoji"`
+	WorkMissing     bool   `json:"work_missing"`
+	Standup         string `json:"standup,omitempty"`
+	Blockers        string `json:"blockers,omitempty"`
+	Points          int    `json:"points"`
+	Done            bool   `json:"done"`
+	Color           string `json:"color"`
+}
+
+// DailyReport builds report on work of standupers on the day
+func (bot *Bot) DailyReport(date time.Time) (Report, error) {
+	day := noon(date)
+	report := Report{Kind: ReportDaily, From: day.Format(workDateFormat), To: day.Format(workDateFormat), Entries: []ReportEntry{}}
+	weekend := isWeekend(day)
+
+	channels, err := bot.db.ListWorkspaceProjects(bot.workspace.WorkspaceID)
+	if err != nil {
+		return report, err
+	}
+
+	for _, channel := range channels {
+		standupers, err := bot.db.ListProjectStandupers(channel.ChannelID)
+		if err != nil {
+			log.Errorf("ListProjectStandupers failed for channel %v: %v", channel.ChannelName, err)
+			continue
+		}
+
+		holiday := bot.isHoliday(&channel, day)
+		work := bot.collectWork(standupers, day, day)
+
+		entries := []ReportEntry{}
+		for i, standuper := range standupers {
+			rule := bot.workspace.ScoringRules.Rule(standuper.Role)
+			entry := newReportEntry(channel, standuper)
+			bot.scoreWork(&entry, work[i], rule, rule.DailyWorklogs, 0)
+
+			absent := bot.isAbsent(standuper.UserID, standuper.ChannelID, day)
+			entry.Standup, entry.Blockers = bot.standupStatus(channel, standuper, day, absent)
+			if entry.Standup != StandupMissing {
+				entry.Points++
+			}
+
+			//absent standupers are neither tagged nor marked as bad performers
+			if absent {
+				entry.Points = 3
+			}
+			entry.Done = entry.Points >= 3
+
+			switch {
+			case weekend || holiday || entry.Points >= 3:
+				entry.Color = rule.GoodColor
+			case entry.Points == 0:
+				entry.Color = rule.BadColor
+			default:
+				entry.Color = rule.WarningColor
+			}
+
+			entries = append(entries, entry)
+		}
+
+		report.Entries = append(report.Entries, sortReportEntries(entries)...)
+	}
+
+	return report, nil
+}
+
+// WeeklyReport builds report on work of standupers over the period, both days
+// are inclusive. Less hours are expected for every holiday in the period
+func (bot *Bot) WeeklyReport(from, to time.Time) (Report, error) {
+	from, to = noon(from), noon(to)
+	report := Report{Kind: ReportWeekly, From: from.Format(workDateFormat), To: to.Format(workDateFormat), Entries: []ReportEntry{}}
+
+	channels, err := bot.db.ListWorkspaceProjects(bot.workspace.WorkspaceID)
+	if err != nil {
+		return report, err
+	}
+
+	for _, channel := range channels {
+		standupers, err := bot.db.ListProjectStandupers(channel.ChannelID)
+		if err != nil {
+			log.Errorf("ListProjectStandupers failed for channel %v: %v", channel.ChannelName, err)
+			continue
+		}
+
+		holidays := 0
+		for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+			if bot.isHoliday(&channel, day) {
+				holidays++
+			}
+		}
+
+		work := bot.collectWork(standupers, from, to)
+
+		entries := []ReportEntry{}
+		for i, standuper := range standupers {
+			rule := bot.workspace.ScoringRules.Rule(standuper.Role)
+			entry := newReportEntry(channel, standuper)
+			bot.scoreWork(&entry, work[i], rule, rule.WeeklyWorklogs, float64(holidays)*rule.HolidayHours)
+
+			entry.Done = entry.Points >= 2
+			switch entry.Points {
+			case 0:
+				entry.Color = rule.BadColor
+			case 1:
+				entry.Color = rule.WarningColor
+			default:
+				entry.Color = rule.GoodColor
+			}
+
+			entries = append(entries, entry)
+		}
+
+		report.Entries = append(report.Entries, sortReportEntries(entries)...)
+	}
+
+	return report, nil
+}
+
+func newReportEntry(channel model.Project, standuper model.Standuper) ReportEntry {
+	return ReportEntry{
+		ChannelID:   channel.ChannelID,
+		ChannelName: channel.ChannelName,
+		UserID:      standuper.UserID,
+		RealName:    standuper.RealName,
+		Role:        standuper.Role,
+	}
+}
+
+// scoreWork grades worklogs and commits of entry with the rule. Work that is
+// not counted gets its point, so it is not marked missing. Hours are added to
+// worklogs before they are graded
+func (bot *Bot) scoreWork(entry *ReportEntry, work memberWork, rule model.ScoringRule, worklogsLevels []model.ScoreLevel, hours float64) {
+	if work.err != nil {
+		log.Warningf("work of %v in %v is missing: %v", entry.UserID, entry.ChannelName, work.err)
+		entry.WorkMissing = true
+		entry.Points += 2
+		return
+	}
+
+	entry.Worklogs, entry.ProjectWorklogs = work.user.Worklogs, work.project.Worklogs
+	entry.Commits, entry.ProjectCommits = work.user.Commits, work.project.Commits
+
+	if rule.SkipWorklogs || !bot.tracksWorklogs() {
+		entry.Points++
+	} else {
+		level := model.Grade(worklogsLevels, float64(entry.Worklogs)/3600+hours)
+		entry.WorklogsCounted, entry.WorklogsEmoji = true, level.Emoji
+		if level.Good {
+			entry.Points++
+		}
+	}
+
+	if rule.SkipCommits || !bot.tracksCommits() {
+		entry.Points++
+	} else {
+		level := model.Grade(rule.Commits, float64(entry.ProjectCommits))
+		entry.CommitsCounted, entry.CommitsEmoji = true, level.Emoji
+		if level.Good {
+			entry.Points++
+		}
+	}
+}
+
+// standupStatus tells if standuper submitted standup on the day and blockers mentioned in it
+func (bot *Bot) standupStatus(channel model.Project, member model.Standuper, day time.Time, absent bool) (string, string) {
+	timeFrom := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.Local).Unix()
+	timeTo := time.Date(day.Year(), day.Month(), day.Day(), 23, 59, 59, 0, time.Local).Unix()
+
+	standup, err := bot.db.GetStandupForPeriod(member.UserID, member.ChannelID, timeFrom, timeTo)
+	if err != nil && err != sql.ErrNoRows {
+		log.Error("GetStandupForPeriod failed: ", err)
+		return StandupMissing, ""
+	}
+	if err == nil && standup != nil {
+		return StandupSubmitted, standup.Blockers
+	}
+	if !bot.shouldSubmitStandupIn(&channel, day) {
+		return StandupNotRequired, ""
+	}
+	if absent {
+		return StandupAbsent, ""
+	}
+	return StandupMissing, ""
+}
+
+// sortReportEntries puts standupers who logged more time to the project first
+func sortReportEntries(entries []ReportEntry) []ReportEntry {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].ProjectWorklogs > entries[j].ProjectWorklogs
+	})
+	return entries
+}
+
+// noon moves t to the middle of its day, so the day stays the same in time zones of channels
+func noon(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 12, 0, 0, 0, time.Local)
+}
+
+func isWeekend(t time.Time) bool {
+	return t.Weekday() == time.Saturday || t.Weekday() == time.Sunday
+}
+
+var reportColumns = []string{"channel_id", "channel_name", "user_id", "real_name", "role", "worklogs", "project_worklogs", "commits", "project_commits", "standup", "blockers", "points", "done"}
+
+// WriteCSV writes report entries as CSV, worklogs are in H:MM format and
+// work not counted is left empty
+func (r Report) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	err := writer.Write(reportColumns)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range r.Entries {
+		worklogs, projectWorklogs, commits, projectCommits := e.work()
+		err := writer.Write([]string{
+			e.ChannelID,
+			e.ChannelName,
+			e.UserID,
+			e.RealName,
+			e.Role,
+			worklogs,
+			projectWorklogs,
+			commits,
+			projectCommits,
+			e.Standup,
+			e.Blockers,
+			fmt.Sprint(e.Points),
+			fmt.Sprint(e.Done),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// Markdown renders report as a table for every project
+func (r Report) Markdown() string {
+	var b bytes.Buffer
+	period := r.From
+	if r.To != r.From {
+		period += " – " + r.To
+	}
+	fmt.Fprintf(&b, "# %s report %s\n", strings.Title(r.Kind), period)
+
+	channelID := ""
+	for i, e := range r.Entries {
+		if i == 0 || e.ChannelID != channelID {
+			channelID = e.ChannelID
+			fmt.Fprintf(&b, "\n## #%s\n\n", markdownCell(e.ChannelName))
+			b.WriteString("| Member | Role | Worklogs | Commits | Standup | Blockers | Done |\n")
+			b.WriteString("|---|---|---|---|---|---|---|\n")
+		}
+
+		name := e.RealName
+		if name == "" {
+			name = e.UserID
+		}
+		worklogs, projectWorklogs, _, projectCommits := e.work()
+		if worklogs != projectWorklogs {
+			worklogs = projectWorklogs + " of " + worklogs
+		}
+		if e.WorklogsCounted {
+			worklogs = strings.TrimSpace(worklogs + " " + e.WorklogsEmoji)
+		}
+		if e.CommitsCounted {
+			projectCommits = strings.TrimSpace(projectCommits + " " + e.CommitsEmoji)
+		}
+		done := ""
+		if e.Done {
+			done = "✓"
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s | %s |\n",
+			markdownCell(name), markdownCell(e.Role), worklogs, projectCommits,
+			strings.Replace(e.Standup, "_", " ", -1), markdownCell(e.Blockers), done)
+	}
+
+	if len(r.Entries) == 0 {
+		b.WriteString("\nNo standupers to report on\n")
+	}
+	return b.String()
+}
+
+// work returns worklogs and commits of entry as text, work not counted is empty
+func (e ReportEntry) work() (string, string, string, string) {
+	var worklogs, projectWorklogs, commits, projectCommits string
+	if e.WorklogsCounted {
+		worklogs, projectWorklogs = SecondsToHuman(e.Worklogs), SecondsToHuman(e.ProjectWorklogs)
+	}
+	if e.CommitsCounted {
+		commits, projectCommits = fmt.Sprint(e.Commits), fmt.Sprint(e.ProjectCommits)
+	}
+	return worklogs, projectWorklogs, commits, projectCommits
+}
+
+// markdownCell escapes text for a cell of markdown table
+func markdownCell(text string) string {
+	return strings.NewReplacer("|", `\|`, "\r", "", "\n", "<br>").Replace(strings.TrimSpace(text))
+}
@@ -1,87 +1,54 @@
 package botuser
 
 import (
-	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"math"
-	"net/http"
 	"time"
 
-	"github.com/maddevsio/comedian/model"
 	"github.com/nicksnyder/go-i18n/v2/i18n"
 	"github.com/nlopes/slack"
-	"github.com/olebedev/when"
-	"github.com/olebedev/when/rules/en"
-	"github.com/olebedev/when/rules/ru"
 	log "github.com/sirupsen/logrus"
 )
 
-//CollectorData used to parse data on user from Collector
-type CollectorData struct {
-	Commits  int `json:"total_commits"`
-	Worklogs int `json:"worklogs"`
-}
-
-//AttachmentItem is needed to sort attachments
-type AttachmentItem struct {
-	SlackAttachment slack.Attachment
-	Points          int
-}
-
-// CallDisplayYesterdayTeamReport calls displayYesterdayTeamReport
-func (bot *Bot) CallDisplayYesterdayTeamReport() error {
+// CallDisplayYesterdayTeamReport calls displayYesterdayTeamReport for every reporting time in (from, to],
+// so report caught up after downtime is on the day before the missed reporting time
+func (bot *Bot) CallDisplayYesterdayTeamReport(from, to time.Time) error {
 	if bot.workspace.ReportingTime == "" {
 		return nil
 	}
 
-	w := when.New(nil)
-	w.Add(en.All...)
-	w.Add(ru.All...)
-
-	r, err := w.Parse(bot.workspace.ReportingTime, time.Now())
-	if err != nil {
-		return err
-	}
-
-	if time.Now().Hour() != r.Time.Hour() || time.Now().Minute() != r.Time.Minute() {
-		return nil
+	for _, t := range occurrences(bot.workspace.ReportingTime, time.Local, from, to) {
+		_, err := bot.displayYesterdayTeamReport(t)
+		if err != nil {
+			return err
+		}
 	}
 
-	_, err = bot.displayYesterdayTeamReport()
-	return err
-
+	return nil
 }
 
-// CallDisplayWeeklyTeamReport calls displayWeeklyTeamReport
-func (bot *Bot) CallDisplayWeeklyTeamReport() error {
-	if int(time.Now().Weekday()) != 0 {
-		return nil
-	}
-
+// CallDisplayWeeklyTeamReport calls displayWeeklyTeamReport if reporting time on Sunday is in (from, to]
+func (bot *Bot) CallDisplayWeeklyTeamReport(from, to time.Time) error {
 	if bot.workspace.ReportingTime == "" {
 		return nil
 	}
 
-	w := when.New(nil)
-	w.Add(en.All...)
-	w.Add(ru.All...)
-
-	r, err := w.Parse(bot.workspace.ReportingTime, time.Now())
+	for _, t := range occurrences(bot.workspace.ReportingTime, time.Local, from, to) {
+		if t.Weekday() != time.Sunday {
+			continue
+		}
 
-	if time.Now().Hour() != r.Time.Hour() || time.Now().Minute() != r.Time.Minute() {
-		return nil
+		_, err := bot.displayWeeklyTeamReport(t)
+		return err
 	}
 
-	_, err = bot.displayWeeklyTeamReport()
-	return err
+	return nil
 }
 
-// displayYesterdayTeamReport generates report on users who submit standups
-func (bot *Bot) displayYesterdayTeamReport() (string, error) {
-	var allReports []slack.Attachment
-
-	channels, err := bot.db.ListWorkspaceProjects(bot.workspace.WorkspaceID)
+// displayYesterdayTeamReport posts report on work of standupers on the day before reporting time
+func (bot *Bot) displayYesterdayTeamReport(at time.Time) (string, error) {
+	yesterday := at.AddDate(0, 0, -1)
+	report, err := bot.DailyReport(yesterday)
 	if err != nil {
 		return "", err
 	}
@@ -96,160 +63,12 @@ func (bot *Bot) displayYesterdayTeamReport() (string, error) {
 		log.Error(err)
 	}
 
-	for _, channel := range channels {
-
-		var attachments []slack.Attachment
-		var attachmentsPull []AttachmentItem
-
-		standupers, err := bot.db.ListProjectStandupers(channel.ChannelID)
-		if err != nil {
-			log.Errorf("ListProjectStandupers failed for channel %v: %v", channel.ChannelName, err)
-			continue
-		}
-
-		if len(standupers) == 0 {
-			continue
-		}
-
-		for _, standuper := range standupers {
-			var attachment slack.Attachment
-			var attachmentFields []slack.AttachmentField
-			var worklogs, commits, standup string
-			var worklogsPoints, commitsPoints, standupPoints int
-
-			dataOnUser, dataOnUserInProject, collectorError := bot.GetCollectorDataOnMember(standuper, time.Now().AddDate(0, 0, -1), time.Now().AddDate(0, 0, -1))
-
-			if collectorError == nil {
-				worklogs, worklogsPoints = bot.processWorklogs(dataOnUser.Worklogs, dataOnUserInProject.Worklogs)
-				commits, commitsPoints = bot.processCommits(dataOnUser.Commits, dataOnUserInProject.Commits)
-			}
-
-			if standuper.Role == "pm" || standuper.Role == "designer" {
-				commits = ""
-				commitsPoints++
-			}
-
-			if collectorError != nil {
-				worklogs = ""
-				worklogsPoints++
-				commits = ""
-				commitsPoints++
-			}
-
-			standup, standupPoints = bot.processStandup(standuper)
-
-			fieldValue := worklogs + commits + standup
-
-			//if there is nothing to show, do not create attachment
-			if fieldValue == "" {
-				log.Warningf("Nothing to show... skip standuper! %v", standuper)
-				continue
-			}
-
-			attachmentFields = append(attachmentFields, slack.AttachmentField{
-				Value: fieldValue,
-				Short: false,
-			})
-
-			points := worklogsPoints + commitsPoints + standupPoints
-
-			//attachment text will be depend on worklogsPoints,commitsPoints and standupPoints
-			if points >= 3 {
-				notTagStanduper, err := bot.localizer.Localize(&i18n.LocalizeConfig{
-					DefaultMessage: &i18n.Message{
-						ID:    "notTagStanduper",
-						Other: "",
-					},
-					TemplateData: map[string]interface{}{"user": standuper.RealName, "channel": channel.ChannelName},
-				})
-				if err != nil {
-					log.Error(err)
-				}
-				attachment.Text = notTagStanduper
-			} else {
-				tagStanduper, err := bot.localizer.Localize(&i18n.LocalizeConfig{
-					DefaultMessage: &i18n.Message{
-						ID:    "tagStanduper",
-						Other: "",
-					},
-					TemplateData: map[string]interface{}{"user": standuper.UserID, "channel": channel.ChannelName},
-				})
-				if err != nil {
-					log.Error(err)
-				}
-				attachment.Text = tagStanduper
-			}
-
-			switch points {
-			case 0:
-				attachment.Color = "danger"
-			case 1, 2:
-				attachment.Color = "warning"
-			case 3:
-				attachment.Color = "good"
-			}
-
-			if int(time.Now().Weekday()) == 0 || int(time.Now().Weekday()) == 1 {
-				attachment.Color = "good"
-			}
-
-			attachment.Fields = attachmentFields
-
-			item := AttachmentItem{
-				SlackAttachment: attachment,
-				Points:          dataOnUserInProject.Worklogs,
-			}
-
-			attachmentsPull = append(attachmentsPull, item)
-		}
-
-		if len(attachmentsPull) == 0 {
-			continue
-		}
-
-		attachments = bot.sortReportEntries(attachmentsPull)
-		if bot.workspace.ProjectsReportsEnabled {
-			err := bot.send(&Message{
-				Type:        "message",
-				Channel:     channel.ChannelID,
-				Text:        reportHeader,
-				Attachments: attachments,
-			})
-			if err != nil {
-				log.Error("send message failed ", err)
-			}
-		}
-
-		allReports = append(allReports, attachments...)
-	}
-
-	if len(allReports) == 0 {
-		return "", nil
-	}
-
-	var reportingChannelID string
-
-	for _, ch := range channels {
-		if (ch.ChannelName == bot.workspace.ReportingChannel && ch.WorkspaceID == bot.workspace.WorkspaceID) || (ch.ChannelID == bot.workspace.ReportingChannel && ch.WorkspaceID == bot.workspace.WorkspaceID) {
-			reportingChannelID = ch.ChannelID
-		}
-	}
-
-	err = bot.send(&Message{
-		Type:        "message",
-		Channel:     reportingChannelID,
-		Text:        reportHeader,
-		Attachments: allReports,
-	})
-
-	return fmt.Sprintf(reportHeader, allReports), err
+	return bot.postReport(report, reportHeader, isWeekend(yesterday))
 }
 
-// displayWeeklyTeamReport generates report on users who submit standups
-func (bot *Bot) displayWeeklyTeamReport() (string, error) {
-	var allReports []slack.Attachment
-
-	channels, err := bot.db.ListProjects()
+// displayWeeklyTeamReport posts report on work of standupers during the week before reporting time
+func (bot *Bot) displayWeeklyTeamReport(at time.Time) (string, error) {
+	report, err := bot.WeeklyReport(at.AddDate(0, 0, -7), at.AddDate(0, 0, -1))
 	if err != nil {
 		return "", err
 	}
@@ -264,132 +83,58 @@ func (bot *Bot) displayWeeklyTeamReport() (string, error) {
 		log.Error(err)
 	}
 
-	for _, channel := range channels {
-		var attachmentsPull []AttachmentItem
-		var attachments []slack.Attachment
+	return bot.postReport(report, reportHeaderWeekly, false)
+}
 
-		standupers, err := bot.db.ListProjectStandupers(channel.ChannelID)
-		if err != nil {
-			log.Errorf("ListProjectStandupers failed for channel %v: %v", channel.ChannelName, err)
-			continue
-		}
+// postReport sends report to channels of projects if project reports are enabled
+// and to reporting channel of the workspace. On weekends missing work is not shown
+func (bot *Bot) postReport(report Report, header string, weekend bool) (string, error) {
+	var allReports []slack.Attachment
 
-		if len(standupers) == 0 {
+	channelIDs := []string{}
+	attachments := map[string][]slack.Attachment{}
+	for _, entry := range report.Entries {
+		attachment, ok := bot.reportAttachment(entry, weekend)
+		//if there is nothing to show, do not create attachment
+		if !ok {
+			log.Warningf("Nothing to show... skip standuper! %v in %v", entry.UserID, entry.ChannelName)
 			continue
 		}
-
-		for _, standuper := range standupers {
-			var attachment slack.Attachment
-			var attachmentFields []slack.AttachmentField
-			var worklogs, commits string
-			var worklogsPoints, commitsPoints int
-
-			dataOnUser, dataOnUserInProject, collectorError := bot.GetCollectorDataOnMember(standuper, time.Now().AddDate(0, 0, -7), time.Now().AddDate(0, 0, -1))
-
-			if collectorError == nil {
-				worklogs, worklogsPoints = bot.processWeeklyWorklogs(dataOnUser.Worklogs, dataOnUserInProject.Worklogs)
-				commits, commitsPoints = bot.processCommits(dataOnUser.Commits, dataOnUserInProject.Commits)
-			}
-
-			if standuper.Role == "pm" || standuper.Role == "designer" {
-				commits = ""
-				commitsPoints++
-			}
-
-			if collectorError != nil {
-				worklogs = ""
-				worklogsPoints++
-				commits = ""
-				commitsPoints++
-			}
-
-			fieldValue := worklogs + commits
-
-			//if there is nothing to show, do not create attachment
-			if fieldValue == "" {
-				continue
-			}
-
-			attachmentFields = append(attachmentFields, slack.AttachmentField{
-				Value: fieldValue,
-				Short: false,
-			})
-
-			points := worklogsPoints + commitsPoints
-
-			if points >= 2 {
-				notTagStanduper, err := bot.localizer.Localize(&i18n.LocalizeConfig{
-					DefaultMessage: &i18n.Message{
-						ID:    "notTagStanduper",
-						Other: "",
-					},
-					TemplateData: map[string]interface{}{"user": standuper.RealName, "channel": channel.ChannelName},
-				})
-				if err != nil {
-					log.Error(err)
-				}
-				attachment.Text = notTagStanduper
-			} else {
-				tagStanduper, err := bot.localizer.Localize(&i18n.LocalizeConfig{
-					DefaultMessage: &i18n.Message{
-						ID:    "tagStanduper",
-						Other: "",
-					},
-					TemplateData: map[string]interface{}{"user": standuper.UserID, "channel": channel.ChannelName},
-				})
-				if err != nil {
-					log.Error(err)
-				}
-				attachment.Text = tagStanduper
-			}
-
-			switch points {
-			case 0:
-				attachment.Color = "danger"
-			case 1:
-				attachment.Color = "warning"
-			case 2:
-				attachment.Color = "good"
-			}
-
-			attachment.Fields = attachmentFields
-
-			item := AttachmentItem{
-				SlackAttachment: attachment,
-				Points:          dataOnUserInProject.Worklogs,
-			}
-
-			attachmentsPull = append(attachmentsPull, item)
-		}
-
-		if len(attachmentsPull) == 0 {
-			continue
+		if _, ok := attachments[entry.ChannelID]; !ok {
+			channelIDs = append(channelIDs, entry.ChannelID)
 		}
+		attachments[entry.ChannelID] = append(attachments[entry.ChannelID], attachment)
+	}
 
-		attachments = bot.sortReportEntries(attachmentsPull)
-
+	for _, channelID := range channelIDs {
 		if bot.workspace.ProjectsReportsEnabled {
 			err := bot.send(&Message{
 				Type:        "message",
-				Channel:     channel.ChannelID,
-				Text:        reportHeaderWeekly,
-				Attachments: attachments,
+				Channel:     channelID,
+				Text:        header,
+				Attachments: attachments[channelID],
 			})
 			if err != nil {
-				log.Error(err)
+				log.Error("send message failed ", err)
 			}
 		}
-		allReports = append(allReports, attachments...)
+
+		allReports = append(allReports, attachments[channelID]...)
 	}
 
 	if len(allReports) == 0 {
 		return "", nil
 	}
 
+	channels, err := bot.db.ListWorkspaceProjects(bot.workspace.WorkspaceID)
+	if err != nil {
+		return "", err
+	}
+
 	var reportingChannelID string
 
 	for _, ch := range channels {
-		if (ch.ChannelName == bot.workspace.ReportingChannel && ch.WorkspaceID == bot.workspace.WorkspaceID) || (ch.ChannelID == bot.workspace.ReportingChannel && ch.WorkspaceID == bot.workspace.WorkspaceID) {
+		if ch.ChannelName == bot.workspace.ReportingChannel || ch.ChannelID == bot.workspace.ReportingChannel {
 			reportingChannelID = ch.ChannelID
 		}
 	}
@@ -397,93 +142,81 @@ func (bot *Bot) displayWeeklyTeamReport() (string, error) {
 	err = bot.send(&Message{
 		Type:        "message",
 		Channel:     reportingChannelID,
-		Text:        reportHeaderWeekly,
+		Text:        header,
 		Attachments: allReports,
 	})
 
-	return fmt.Sprintf(reportHeaderWeekly, allReports), err
+	return fmt.Sprintf(header, allReports), err
 }
 
-func (bot *Bot) processWorklogs(totalWorklogs, projectWorklogs int) (string, int) {
-
-	var points int
-	worklogsEmoji := ""
-
-	w := totalWorklogs / 3600
-	switch {
-	case w < 3:
-		worklogsEmoji = ":angry:"
-	case w >= 3 && w < 7:
-		worklogsEmoji = ":disappointed:"
-	case w >= 7 && w < 9:
-		worklogsEmoji = ":wink:"
-		points++
-	case w >= 9:
-		worklogsEmoji = ":sunglasses:"
-		points++
+// reportAttachment formats report entry for chat, it returns false if there is nothing to show
+func (bot *Bot) reportAttachment(entry ReportEntry, weekend bool) (slack.Attachment, bool) {
+	var attachment slack.Attachment
+
+	fieldValue := bot.worklogsText(entry, weekend) + bot.commitsText(entry, weekend) + bot.standupText(entry)
+	if fieldValue == "" {
+		return attachment, false
 	}
 
-	worklogsTime := SecondsToHuman(totalWorklogs)
+	attachment.Fields = []slack.AttachmentField{{
+		Value: fieldValue,
+		Short: false,
+	}}
 
-	if totalWorklogs != projectWorklogs {
-		var err error
-		worklogsTime, err = bot.localizer.Localize(&i18n.LocalizeConfig{
+	//standupers who have done everything are not tagged
+	if entry.Done {
+		notTagStanduper, err := bot.localizer.Localize(&i18n.LocalizeConfig{
 			DefaultMessage: &i18n.Message{
-				ID:    "worklogsTime",
+				ID:    "notTagStanduper",
 				Other: "",
 			},
-			TemplateData: map[string]interface{}{"projectWorklogs": SecondsToHuman(projectWorklogs), "totalWorklogs": SecondsToHuman(totalWorklogs)},
+			TemplateData: map[string]interface{}{"user": entry.RealName, "channel": entry.ChannelName},
 		})
 		if err != nil {
 			log.Error(err)
 		}
-	}
-
-	if int(time.Now().Weekday()) == 0 || int(time.Now().Weekday()) == 1 {
-		worklogsEmoji = ""
-		if projectWorklogs == 0 {
-			return "", points
+		attachment.Text = notTagStanduper
+	} else {
+		tagStanduper, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+			DefaultMessage: &i18n.Message{
+				ID:    "tagStanduper",
+				Other: "",
+			},
+			TemplateData: map[string]interface{}{"user": entry.UserID, "channel": entry.ChannelName},
+		})
+		if err != nil {
+			log.Error(err)
 		}
+		attachment.Text = tagStanduper
 	}
 
-	worklogsTranslation, err := bot.localizer.Localize(&i18n.LocalizeConfig{
-		DefaultMessage: &i18n.Message{
-			ID:    "worklogsTranslation",
-			Other: "",
-		},
-		TemplateData: map[string]interface{}{"worklogsTime": worklogsTime, "worklogsEmoji": worklogsEmoji},
-	})
-	if err != nil {
-		log.Error(err)
-	}
-	return worklogsTranslation, points
+	attachment.Color = entry.Color
+	return attachment, true
 }
 
-func (bot *Bot) processWeeklyWorklogs(totalWorklogs, projectWorklogs int) (string, int) {
-	var points int
-	worklogsEmoji := ""
-
-	w := totalWorklogs / 3600
-	switch {
-	case w < 31:
-		worklogsEmoji = ":disappointed:"
-	case w >= 31 && w < 35:
-		worklogsEmoji = ":wink:"
-		points++
-	case w >= 35:
-		worklogsEmoji = ":sunglasses:"
-		points++
+func (bot *Bot) worklogsText(entry ReportEntry, weekend bool) string {
+	if !entry.WorklogsCounted {
+		return ""
 	}
-	worklogsTime := SecondsToHuman(totalWorklogs)
 
-	if totalWorklogs != projectWorklogs {
+	worklogsEmoji := entry.WorklogsEmoji
+	if weekend {
+		worklogsEmoji = ""
+		if entry.ProjectWorklogs == 0 {
+			return ""
+		}
+	}
+
+	worklogsTime := SecondsToHuman(entry.Worklogs)
+
+	if entry.Worklogs != entry.ProjectWorklogs {
 		var err error
 		worklogsTime, err = bot.localizer.Localize(&i18n.LocalizeConfig{
 			DefaultMessage: &i18n.Message{
 				ID:    "worklogsTime",
 				Other: "",
 			},
-			TemplateData: map[string]interface{}{"projectWorklogs": SecondsToHuman(projectWorklogs), "totalWorklogs": SecondsToHuman(totalWorklogs)},
+			TemplateData: map[string]interface{}{"projectWorklogs": SecondsToHuman(entry.ProjectWorklogs), "totalWorklogs": SecondsToHuman(entry.Worklogs)},
 		})
 		if err != nil {
 			log.Error(err)
@@ -500,27 +233,19 @@ func (bot *Bot) processWeeklyWorklogs(totalWorklogs, projectWorklogs int) (strin
 	if err != nil {
 		log.Error(err)
 	}
-
-	return worklogsTranslation, points
+	return worklogsTranslation
 }
 
-func (bot *Bot) processCommits(totalCommits, projectCommits int) (string, int) {
-	var points int
-	commitsEmoji := ""
-
-	c := projectCommits
-	switch {
-	case c == 0:
-		commitsEmoji = ":shit:"
-	case c > 0:
-		commitsEmoji = ":wink:"
-		points++
+func (bot *Bot) commitsText(entry ReportEntry, weekend bool) string {
+	if !entry.CommitsCounted {
+		return ""
 	}
 
-	if int(time.Now().Weekday()) == 0 || int(time.Now().Weekday()) == 1 {
+	commitsEmoji := entry.CommitsEmoji
+	if weekend {
 		commitsEmoji = ""
-		if projectCommits == 0 {
-			return "", points
+		if entry.ProjectCommits == 0 {
+			return ""
 		}
 	}
 
@@ -529,39 +254,28 @@ func (bot *Bot) processCommits(totalCommits, projectCommits int) (string, int) {
 			ID:    "commitsTranslation",
 			Other: "",
 		},
-		TemplateData: map[string]interface{}{"projectCommits": projectCommits, "commitsEmoji": commitsEmoji},
+		TemplateData: map[string]interface{}{"projectCommits": entry.ProjectCommits, "commitsEmoji": commitsEmoji},
 	})
 	if err != nil {
 		log.Error(err)
 	}
-	return commitsTranslation, points
+	return commitsTranslation
 }
 
-func (bot *Bot) processStandup(member model.Standuper) (string, int) {
-	var text string
-	var points int
-
-	t := time.Now().AddDate(0, 0, -1)
-
-	timeFrom := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local).Unix()
-	timeTo := time.Date(t.Year(), t.Month(), t.Day(), 23, 59, 59, 0, time.Local).Unix()
-
-	channel, err := bot.db.SelectProject(member.ChannelID)
-	if err != nil {
-		log.Error("reporting SelectProject failed: ", err)
-		return "", points
-	}
-
-	standup, err := bot.db.GetStandupForPeriod(member.UserID, member.ChannelID, timeFrom, timeTo)
-	if err != nil {
-		log.Error("GetStandupForPeriod failed: ", err)
-		return "", points
-	}
-	if standup == nil {
-		if !shouldSubmitStandupIn(&channel, t) {
-			return "", points + 1
+func (bot *Bot) standupText(entry ReportEntry) string {
+	switch entry.Standup {
+	case StandupAbsent:
+		absent, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+			DefaultMessage: &i18n.Message{
+				ID:    "absentStanduper",
+				Other: "Absent :palm_tree:\n",
+			},
+		})
+		if err != nil {
+			log.Error(err)
 		}
-
+		return absent
+	case StandupMissing:
 		noStandup, err := bot.localizer.Localize(&i18n.LocalizeConfig{
 			DefaultMessage: &i18n.Message{
 				ID:    "noStandup",
@@ -571,9 +285,9 @@ func (bot *Bot) processStandup(member model.Standuper) (string, int) {
 		if err != nil {
 			log.Error(err)
 		}
-		text = noStandup
-	} else {
-		hasStandup, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+		return noStandup
+	case StandupSubmitted:
+		text, err := bot.localizer.Localize(&i18n.LocalizeConfig{
 			DefaultMessage: &i18n.Message{
 				ID:    "hasStandup",
 				Other: "",
@@ -582,99 +296,23 @@ func (bot *Bot) processStandup(member model.Standuper) (string, int) {
 		if err != nil {
 			log.Error(err)
 		}
-		text = hasStandup
-		points++
-	}
-
-	return text, points
-}
-
-func (bot *Bot) sortReportEntries(entries []AttachmentItem) []slack.Attachment {
-	var attachments []slack.Attachment
-
-	for i := 0; i < len(entries); i++ {
-		if !sweep(entries, i) {
-			break
-		}
-	}
-
-	for _, item := range entries {
-		attachments = append(attachments, item.SlackAttachment)
-	}
-
-	return attachments
-}
-
-func sweep(entries []AttachmentItem, prevPasses int) bool {
-	var N = len(entries)
-	var didSwap = false
-	var firstIndex = 0
-	var secondIndex = 1
-
-	for secondIndex < (N - prevPasses) {
 
-		var firstItem = entries[firstIndex]
-		var secondItem = entries[secondIndex]
-		if entries[firstIndex].Points < entries[secondIndex].Points {
-			entries[firstIndex] = secondItem
-			entries[secondIndex] = firstItem
-			didSwap = true
+		if entry.Blockers != "" {
+			standupBlockers, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+				DefaultMessage: &i18n.Message{
+					ID:    "standupBlockers",
+					Other: "Blockers: {{.blockers}}\n",
+				},
+				TemplateData: map[string]interface{}{"blockers": entry.Blockers},
+			})
+			if err != nil {
+				log.Error(err)
+			}
+			text += standupBlockers
 		}
-		firstIndex++
-		secondIndex++
-	}
-
-	return didSwap
-}
-
-//GetCollectorDataOnMember sends API request to Collector endpoint and returns CollectorData type
-func (bot *Bot) GetCollectorDataOnMember(member model.Standuper, startDate, endDate time.Time) (CollectorData, CollectorData, error) {
-	dateFrom := fmt.Sprintf("%d-%02d-%02d", startDate.Year(), startDate.Month(), startDate.Day())
-	dateTo := fmt.Sprintf("%d-%02d-%02d", endDate.Year(), endDate.Month(), endDate.Day())
-
-	project, err := bot.db.SelectProject(member.ChannelID)
-	if err != nil {
-		return CollectorData{}, CollectorData{}, err
-	}
-
-	dataOnUser, err := bot.GetCollectorData("users", member.UserID, dateFrom, dateTo)
-	if err != nil {
-		return CollectorData{}, CollectorData{}, err
-	}
-
-	userInProject := fmt.Sprintf("%v/%v", member.UserID, project.ChannelName)
-	dataOnUserInProject, err := bot.GetCollectorData("user-in-project", userInProject, dateFrom, dateTo)
-	if err != nil {
-		return CollectorData{}, CollectorData{}, err
-	}
-
-	return dataOnUser, dataOnUserInProject, err
-}
-
-//GetCollectorData sends api request to collector servise and returns collector object
-func (bot *Bot) GetCollectorData(getDataOn, data, dateFrom, dateTo string) (CollectorData, error) {
-	var collectorData CollectorData
-	linkURL := fmt.Sprintf("%s/rest/api/v1/logger/%s/%s/%s/%s/%s/", bot.conf.CollectorURL, bot.workspace.WorkspaceID, getDataOn, data, dateFrom, dateTo)
-	req, err := http.NewRequest("GET", linkURL, nil)
-	if err != nil {
-		return collectorData, err
-	}
-	token := bot.conf.CollectorToken
-	req.Header.Add("Authorization", fmt.Sprintf("Token %s", token))
-	res, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return collectorData, err
-	}
-
-	defer res.Body.Close()
-	body, _ := ioutil.ReadAll(res.Body)
-
-	if res.StatusCode != 200 {
-		log.WithFields(log.Fields(map[string]interface{}{"body": string(body), "requestURL": linkURL, "res.StatusCode": res.StatusCode})).Warning("Failed to get collector data on member!")
-		return collectorData, fmt.Errorf("failed to get collector data. %v", res.StatusCode)
+		return text
 	}
-	json.Unmarshal(body, &collectorData)
-	return collectorData, nil
+	return ""
 }
 
 //SecondsToHuman converts seconds (int) to HH:MM format
@@ -0,0 +1,312 @@
+package botuser
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/maddevsio/comedian/messenger"
+	"github.com/maddevsio/comedian/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fixedSource returns the same work of a member in all projects
+type fixedSource map[string]WorkData
+
+func (s fixedSource) Work(member model.Standuper, from, to time.Time) (WorkData, error) {
+	return s[member.UserID], nil
+}
+
+func (s fixedSource) ProjectWork(member model.Standuper, project model.Project, from, to time.Time) (WorkData, error) {
+	return s[member.UserID], nil
+}
+
+func (s fixedSource) TracksCommits() bool  { return true }
+func (s fixedSource) TracksWorklogs() bool { return true }
+
+// reportOn returns entries of report on the users
+func reportOn(report Report, users ...string) []ReportEntry {
+	entries := []ReportEntry{}
+	for _, entry := range report.Entries {
+		for _, user := range users {
+			if entry.UserID == user {
+				entries = append(entries, entry)
+			}
+		}
+	}
+	return entries
+}
+
+func setupReportStandupers(t *testing.T) func() {
+	realWork := bot.work
+	bot.work = fixedSource{
+		"REPORT1": {Commits: 3, Worklogs: 8 * 3600},
+		"REPORT2": {Commits: 0, Worklogs: 30 * 3600},
+	}
+
+	project, err := bot.db.SelectProject("CHAN123")
+	require.NoError(t, err)
+	project.SubmissionDays = "monday, tuesday, wednesday, thursday, friday"
+	_, err = bot.db.UpdateProject(project)
+	require.NoError(t, err)
+
+	alice, err := bot.db.CreateStanduper(model.Standuper{WorkspaceID: "testTeam", ChannelID: "CHAN123", UserID: "REPORT1", RealName: "Alice"})
+	require.NoError(t, err)
+	bob, err := bot.db.CreateStanduper(model.Standuper{WorkspaceID: "testTeam", ChannelID: "CHAN123", UserID: "REPORT2", RealName: "Bob", Role: "designer"})
+	require.NoError(t, err)
+	standup, err := bot.db.CreateStandup(model.Standup{
+		CreatedAt:   time.Date(2019, 5, 7, 10, 0, 0, 0, time.Local).Unix(),
+		WorkspaceID: "testTeam",
+		ChannelID:   "CHAN123",
+		UserID:      "REPORT1",
+		MessageTS:   "report1",
+		Blockers:    "waiting for design",
+	})
+	require.NoError(t, err)
+
+	return func() {
+		bot.work = realWork
+		project.SubmissionDays = ""
+		bot.db.UpdateProject(project)
+		bot.db.DeleteStanduper(alice.ID)
+		bot.db.DeleteStanduper(bob.ID)
+		bot.db.DeleteStandup(standup.ID)
+	}
+}
+
+func TestDailyReport(t *testing.T) {
+	defer setupReportStandupers(t)()
+
+	report, err := bot.DailyReport(time.Date(2019, 5, 7, 0, 0, 0, 0, time.Local))
+	require.NoError(t, err)
+	assert.Equal(t, ReportDaily, report.Kind)
+	assert.Equal(t, "2019-05-07", report.From)
+	assert.Equal(t, "2019-05-07", report.To)
+
+	entries := reportOn(report, "REPORT1", "REPORT2")
+	require.Len(t, entries, 2)
+	// Bob logged more time, so he goes first
+	assert.Equal(t, ReportEntry{
+		ChannelID:       "CHAN123",
+		ChannelName:     "ChannelWithNoDeadline",
+		UserID:          "REPORT2",
+		RealName:        "Bob",
+		Role:            "designer",
+		Worklogs:        30 * 3600,
+		ProjectWorklogs: 30 * 3600,
+		WorklogsCounted: true,
+		WorklogsEmoji:   ":sunglasses:",
+		Standup:         StandupMissing,
+		Points:          2,
+		Color:           "warning",
+	}, entries[0])
+	assert.Equal(t, ReportEntry{
+		ChannelID:       "CHAN123",
+		ChannelName:     "ChannelWithNoDeadline",
+		UserID:          "REPORT1",
+		RealName:        "Alice",
+		Worklogs:        8 * 3600,
+		ProjectWorklogs: 8 * 3600,
+		WorklogsCounted: true,
+		WorklogsEmoji:   ":wink:",
+		Commits:         3,
+		ProjectCommits:  3,
+		CommitsCounted:  true,
+		CommitsEmoji:    ":wink:",
+		Standup:         StandupSubmitted,
+		Blockers:        "waiting for design",
+		Points:          3,
+		Done:            true,
+		Color:           "good",
+	}, entries[1])
+
+	// standups are not expected on weekends
+	report, err = bot.DailyReport(time.Date(2019, 5, 5, 0, 0, 0, 0, time.Local))
+	require.NoError(t, err)
+	entries = reportOn(report, "REPORT2")
+	require.Len(t, entries, 1)
+	assert.Equal(t, StandupNotRequired, entries[0].Standup)
+	assert.Equal(t, 3, entries[0].Points)
+}
+
+func TestWeeklyReport(t *testing.T) {
+	defer setupReportStandupers(t)()
+
+	report, err := bot.WeeklyReport(workFrom, workTo)
+	require.NoError(t, err)
+	assert.Equal(t, ReportWeekly, report.Kind)
+	assert.Equal(t, "2019-05-01", report.From)
+	assert.Equal(t, "2019-05-07", report.To)
+
+	entries := reportOn(report, "REPORT2")
+	require.Len(t, entries, 1)
+	assert.Equal(t, ":disappointed:", entries[0].WorklogsEmoji)
+	assert.Equal(t, 1, entries[0].Points)
+	assert.False(t, entries[0].Done)
+	assert.Equal(t, "", entries[0].Standup)
+
+	holiday, err := bot.db.CreateHoliday(model.Holiday{WorkspaceID: "testTeam", Date: "2019-05-01", Name: "Labour Day"})
+	require.NoError(t, err)
+	defer bot.db.DeleteHoliday(holiday.ID)
+
+	report, err = bot.WeeklyReport(workFrom, workTo)
+	require.NoError(t, err)
+	entries = reportOn(report, "REPORT2")
+	require.Len(t, entries, 1)
+	assert.Equal(t, ":sunglasses:", entries[0].WorklogsEmoji)
+	assert.Equal(t, 2, entries[0].Points)
+	assert.True(t, entries[0].Done)
+	assert.Equal(t, "good", entries[0].Color)
+}
+
+func TestScoreWork(t *testing.T) {
+	realWork := bot.work
+	defer func() { bot.work = realWork }()
+	bot.work = fixedSource{}
+
+	partTime := model.ScoringRules{{
+		Role:           "part-time",
+		DailyWorklogs:  []model.ScoreLevel{{Min: 0, Emoji: ":coffee:"}, {Min: 3.5, Emoji: ":wink:", Good: true}},
+		WeeklyWorklogs: []model.ScoreLevel{{Min: 0, Emoji: ":coffee:"}, {Min: 17.5, Emoji: ":wink:", Good: true}},
+		Commits:        []model.ScoreLevel{{Min: 0, Emoji: ":zzz:", Good: true}},
+		HolidayHours:   3.5,
+	}}.Rule("part-time")
+
+	work := memberWork{user: WorkData{Worklogs: 3*3600 + 1800}, project: WorkData{Worklogs: 3600}}
+	entry := ReportEntry{}
+	bot.scoreWork(&entry, work, partTime, partTime.DailyWorklogs, 0)
+	assert.Equal(t, 2, entry.Points)
+	assert.Equal(t, ":wink:", entry.WorklogsEmoji)
+	assert.Equal(t, ":zzz:", entry.CommitsEmoji)
+
+	entry = ReportEntry{}
+	bot.scoreWork(&entry, memberWork{user: WorkData{Worklogs: 14 * 3600}}, partTime, partTime.WeeklyWorklogs, partTime.HolidayHours)
+	assert.Equal(t, 2, entry.Points)
+
+	entry = ReportEntry{}
+	bot.scoreWork(&entry, memberWork{user: WorkData{Worklogs: 6 * 3600}}, model.ScoringRules(nil).Rule(""), partTime.DailyWorklogs, 0)
+	assert.Equal(t, 1, entry.Points)
+
+	entry = ReportEntry{}
+	bot.scoreWork(&entry, memberWork{err: errNoWorkSource}, partTime, partTime.DailyWorklogs, 0)
+	assert.Equal(t, ReportEntry{WorkMissing: true, Points: 2}, entry)
+}
+
+func TestReportFormats(t *testing.T) {
+	report := Report{Kind: ReportDaily, From: "2019-05-07", To: "2019-05-07", Entries: []ReportEntry{
+		{ChannelID: "C1", ChannelName: "web", UserID: "U1", RealName: "Alice", Worklogs: 9000, ProjectWorklogs: 5400, WorklogsCounted: true, WorklogsEmoji: ":angry:", Commits: 3, ProjectCommits: 2, CommitsCounted: true, CommitsEmoji: ":wink:", Standup: StandupSubmitted, Blockers: "review | deploy", Points: 2},
+		{ChannelID: "C2", ChannelName: "mobile", UserID: "U2", Role: "pm", Standup: StandupNotRequired, Points: 3, Done: true},
+	}}
+
+	var csv bytes.Buffer
+	require.NoError(t, report.WriteCSV(&csv))
+	assert.Equal(t, `channel_id,channel_name,user_id,real_name,role,worklogs,project_worklogs,commits,project_commits,standup,blockers,points,done
+C1,web,U1,Alice,,2:30,1:30,3,2,submitted,review | deploy,2,false
+C2,mobile,U2,,pm,,,,,not_required,,3,true
+`, csv.String())
+
+	assert.Equal(t, `# Daily report 2019-05-07
+
+## #web
+
+| Member | Role | Worklogs | Commits | Standup | Blockers | Done |
+|---|---|---|---|---|---|---|
+| Alice |  | 1:30 of 2:30 :angry: | 2 :wink: | submitted | review \| deploy |  |
+
+## #mobile
+
+| Member | Role | Worklogs | Commits | Standup | Blockers | Done |
+|---|---|---|---|---|---|---|
+| U2 | pm |  |  | not required |  | ✓ |
+`, report.Markdown())
+
+	report.Kind, report.From, report.Entries = ReportWeekly, "2019-05-01", nil
+	assert.Equal(t, "# Weekly report 2019-05-01 – 2019-05-07\n\nNo standupers to report on\n", report.Markdown())
+}
+
+func TestPostReport(t *testing.T) {
+	chat := &fakeChat{}
+	realChat, realSettings := bot.chat, *bot.workspace
+	bot.chat = chat
+	defer func() { bot.chat, *bot.workspace = realChat, realSettings }()
+	bot.workspace.ReportingChannel = "ChannelWithDeadline"
+	bot.workspace.ProjectsReportsEnabled = true
+
+	report := Report{Kind: ReportDaily, Entries: []ReportEntry{
+		{ChannelID: "CHAN123", UserID: "U1", Standup: StandupAbsent},
+		{ChannelID: "CHAN123", UserID: "U2", Standup: StandupNotRequired},
+		{ChannelID: "CHAN321", UserID: "U3", Standup: StandupAbsent},
+	}}
+
+	_, err := bot.postReport(report, "Report", false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"CHAN123: Report", "CHAN321: Report", "CHAN321: Report"}, chat.posted)
+
+	// nothing is posted when there is nothing to show
+	chat.posted = nil
+	_, err = bot.postReport(Report{Entries: report.Entries[1:2]}, "Report", false)
+	require.NoError(t, err)
+	assert.Empty(t, chat.posted)
+}
+
+// periodSource records periods work is asked about
+type periodSource struct {
+	fixedSource
+	mu      *sync.Mutex
+	periods map[string]bool
+}
+
+func (s periodSource) Work(member model.Standuper, from, to time.Time) (WorkData, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.periods[from.Format(workDateFormat)+" "+to.Format(workDateFormat)] = true
+	return s.fixedSource.Work(member, from, to)
+}
+
+func TestCaughtUpReports(t *testing.T) {
+	defer setupReportStandupers(t)()
+	realChat, realSettings := bot.chat, *bot.workspace
+	bot.chat = &fakeChat{}
+	defer func() { bot.chat, *bot.workspace = realChat, realSettings }()
+	bot.workspace.ReportingTime = "10:00"
+
+	source := periodSource{fixedSource: bot.work.(fixedSource), mu: &sync.Mutex{}, periods: map[string]bool{}}
+	bot.work = source
+
+	// reporting times missed long ago are reported on the days before them, not before now
+	missed := time.Date(2019, 5, 7, 10, 0, 0, 0, time.Local)
+	require.NoError(t, bot.CallDisplayYesterdayTeamReport(missed.Add(-time.Hour), missed.Add(time.Hour)))
+	assert.Equal(t, map[string]bool{"2019-05-06 2019-05-06": true}, source.periods)
+
+	delete(source.periods, "2019-05-06 2019-05-06")
+	missed = time.Date(2019, 5, 12, 10, 0, 0, 0, time.Local) // Sunday
+	require.NoError(t, bot.CallDisplayWeeklyTeamReport(missed.Add(-time.Hour), missed.Add(time.Hour)))
+	assert.Equal(t, map[string]bool{"2019-05-05 2019-05-11": true}, source.periods)
+}
+
+// usersChat lists the same users as members of all workspaces
+type usersChat struct {
+	fakeChat
+	users []messenger.User
+}
+
+func (c *usersChat) ListUsers() ([]messenger.User, error) {
+	return c.users, nil
+}
+
+func TestCaughtUpWorklogsReminder(t *testing.T) {
+	defer setupReportStandupers(t)()
+	realChat := bot.chat
+	bot.chat = &usersChat{users: []messenger.User{{ID: "REPORT1", TeamID: "testTeam"}}}
+	defer func() { bot.chat = realChat }()
+
+	source := periodSource{fixedSource: bot.work.(fixedSource), mu: &sync.Mutex{}, periods: map[string]bool{}}
+	bot.work = source
+
+	// worklogs are counted till the missed reminder, not till now
+	missed := time.Date(2019, 5, 31, 10, 0, 0, 0, time.Local)
+	require.NoError(t, bot.remindAboutWorklogs(missed.Add(-time.Hour), missed.Add(time.Hour)))
+	assert.Equal(t, map[string]bool{"2019-05-01 2019-05-31": true}, source.periods)
+}
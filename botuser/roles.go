@@ -0,0 +1,243 @@
+package botuser
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/maddevsio/comedian/model"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/nlopes/slack"
+	log "github.com/sirupsen/logrus"
+)
+
+// projectSettingsCommands change settings of the project and are allowed to its PMs and workspace admins only
+var projectSettingsCommands = map[string]bool{
+	"/deadline":          true,
+	"/tz":                true,
+	"/submittion_days":   true,
+	"/onbording_message": true,
+}
+
+// UserRole returns the most privileged role of user in the channel:
+// workspace admin, PM of the project or member
+func (bot *Bot) UserRole(userID, channelID string) string {
+	_, err := bot.db.FindAdmin(bot.workspace.WorkspaceID, userID)
+	if err == nil {
+		return model.RoleAdmin
+	}
+	if err != sql.ErrNoRows {
+		log.Error("FindAdmin failed: ", err)
+	}
+
+	if channelID == "" {
+		return model.RoleMember
+	}
+
+	standuper, err := bot.db.FindStansuperByUserID(userID, channelID)
+	if err == nil && standuper.Role == model.RolePM {
+		return model.RolePM
+	}
+	return model.RoleMember
+}
+
+// CanManageProject tells if user is allowed to change settings of the project
+func (bot *Bot) CanManageProject(userID, channelID string) bool {
+	role := bot.UserRole(userID, channelID)
+	return role == model.RoleAdmin || role == model.RolePM
+}
+
+// isAllowed checks role of the user running command. Commands showing settings
+// when run without arguments are restricted only when they change something
+func (bot *Bot) isAllowed(command slack.SlashCommand) bool {
+	switch {
+	case projectSettingsCommands[command.Command]:
+	case command.Command == "/standup_keywords" || command.Command == "/dm_mode":
+		if strings.TrimSpace(command.Text) == "" {
+			return true
+		}
+	case command.Command == "/start":
+		return bot.canJoinAs(command)
+	default:
+		return true
+	}
+	return bot.CanManageProject(command.UserID, command.ChannelID)
+}
+
+// canJoinAs lets only existing PMs and workspace admins join standup team as PM
+func (bot *Bot) canJoinAs(command slack.SlashCommand) bool {
+	if strings.TrimSpace(command.Text) != model.RolePM {
+		return true
+	}
+	return bot.CanManageProject(command.UserID, command.ChannelID)
+}
+
+func (bot *Bot) notAllowed() string {
+	onlyPMCanModify, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+		DefaultMessage: &i18n.Message{
+			ID:    "onlyPMCanModify",
+			Other: "Only PMs of the project and workspace admins can do this",
+		},
+	})
+	if err != nil {
+		log.Error(err)
+	}
+	return onlyPMCanModify
+}
+
+// adminCommand lists workspace admins, "add @user" and "remove @user" manage them.
+// The first admin is the user who installed Comedian or one set with ADMINS
+func (bot *Bot) adminCommand(command slack.SlashCommand) string {
+	fields := strings.Fields(command.Text)
+	if len(fields) == 0 {
+		return bot.listAdmins()
+	}
+	if len(fields) != 2 || (fields[0] != "add" && fields[0] != "remove") {
+		return bot.adminUsage()
+	}
+
+	if bot.UserRole(command.UserID, "") != model.RoleAdmin {
+		onlyAdminCanManage, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+			DefaultMessage: &i18n.Message{
+				ID:    "onlyAdminCanManage",
+				Other: "Only workspace admins can manage admins",
+			},
+		})
+		if err != nil {
+			log.Error(err)
+		}
+		return onlyAdminCanManage
+	}
+
+	userID := mentionedUserID(fields[1])
+	if fields[0] == "add" {
+		_, err := bot.db.CreateAdmin(model.Admin{
+			CreatedAt:   time.Now().Unix(),
+			WorkspaceID: bot.workspace.WorkspaceID,
+			UserID:      userID,
+		})
+		if err != nil {
+			log.Error("CreateAdmin failed: ", err)
+			return bot.failedManageAdmins()
+		}
+		return bot.listAdmins()
+	}
+
+	admins, err := bot.db.ListWorkspaceAdmins(bot.workspace.WorkspaceID)
+	if err != nil {
+		log.Error("ListWorkspaceAdmins failed: ", err)
+		return bot.failedManageAdmins()
+	}
+	if len(admins) == 1 && admins[0].UserID == userID {
+		lastAdmin, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+			DefaultMessage: &i18n.Message{
+				ID:    "lastAdmin",
+				Other: "Workspace must have at least one admin",
+			},
+		})
+		if err != nil {
+			log.Error(err)
+		}
+		return lastAdmin
+	}
+
+	admin, err := bot.db.FindAdmin(bot.workspace.WorkspaceID, userID)
+	if err != nil {
+		notAdmin, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+			DefaultMessage: &i18n.Message{
+				ID:    "notAdmin",
+				Other: "<@{{.user}}> is not an admin",
+			},
+			TemplateData: map[string]interface{}{
+				"user": userID,
+			},
+		})
+		if err != nil {
+			log.Error(err)
+		}
+		return notAdmin
+	}
+
+	err = bot.db.DeleteAdmin(admin.ID)
+	if err != nil {
+		log.Error("DeleteAdmin failed: ", err)
+		return bot.failedManageAdmins()
+	}
+	return bot.listAdmins()
+}
+
+func (bot *Bot) listAdmins() string {
+	admins, err := bot.db.ListWorkspaceAdmins(bot.workspace.WorkspaceID)
+	if err != nil {
+		log.Error("ListWorkspaceAdmins failed: ", err)
+		return bot.failedManageAdmins()
+	}
+
+	if len(admins) == 0 {
+		noAdmins, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+			DefaultMessage: &i18n.Message{
+				ID:    "noAdmins",
+				Other: "Workspace has no admins yet, ask whoever runs Comedian to add one",
+			},
+		})
+		if err != nil {
+			log.Error(err)
+		}
+		return noAdmins
+	}
+
+	users := []string{}
+	for _, admin := range admins {
+		users = append(users, "<@"+admin.UserID+">")
+	}
+
+	listAdmins, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+		DefaultMessage: &i18n.Message{
+			ID:    "listAdmins",
+			Other: "Workspace admins: {{.admins}}",
+		},
+		TemplateData: map[string]interface{}{
+			"admins": strings.Join(users, ", "),
+		},
+	})
+	if err != nil {
+		log.Error(err)
+	}
+	return listAdmins
+}
+
+func (bot *Bot) adminUsage() string {
+	adminUsage, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+		DefaultMessage: &i18n.Message{
+			ID:    "adminUsage",
+			Other: "Use '/admin', '/admin add @user' or '/admin remove @user'",
+		},
+	})
+	if err != nil {
+		log.Error(err)
+	}
+	return adminUsage
+}
+
+func (bot *Bot) failedManageAdmins() string {
+	failedManageAdmins, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+		DefaultMessage: &i18n.Message{
+			ID:    "failedManageAdmins",
+			Other: "Failed to manage workspace admins",
+		},
+	})
+	if err != nil {
+		log.Error(err)
+	}
+	return failedManageAdmins
+}
+
+// mentionedUserID extracts user ID from Slack mention <@U123|name>,
+// other platforms pass user ID as is
+func mentionedUserID(mention string) string {
+	mention = strings.TrimPrefix(strings.TrimSuffix(mention, ">"), "<@")
+	if i := strings.Index(mention, "|"); i != -1 {
+		mention = mention[:i]
+	}
+	return strings.TrimPrefix(mention, "@")
+}
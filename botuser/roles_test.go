@@ -0,0 +1,89 @@
+package botuser
+
+import (
+	"testing"
+
+	"github.com/maddevsio/comedian/model"
+	"github.com/nlopes/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectSettingsRoles(t *testing.T) {
+	command := slack.SlashCommand{
+		Command:   "/tz",
+		TeamID:    "testTeam",
+		UserID:    "member123",
+		ChannelID: "CHAN123",
+		Text:      "Asia/Bishkek",
+	}
+	assert.Equal(t, model.RoleMember, bot.UserRole("member123", "CHAN123"))
+	assert.Equal(t, "Only PMs of the project and workspace admins can do this", bot.ImplementCommands(command))
+
+	command.Command = "/dm_mode"
+	command.Text = ""
+	assert.Equal(t, "Standups are written in the channel", bot.ImplementCommands(command))
+
+	command.Text = "on"
+	assert.Equal(t, "Only PMs of the project and workspace admins can do this", bot.ImplementCommands(command))
+
+	pm, err := bot.db.CreateStanduper(model.Standuper{
+		WorkspaceID: "testTeam",
+		UserID:      "pm123",
+		ChannelID:   "CHAN123",
+		Role:        model.RolePM,
+	})
+	require.NoError(t, err)
+	defer bot.db.DeleteStanduper(pm.ID)
+
+	assert.Equal(t, model.RolePM, bot.UserRole("pm123", "CHAN123"))
+	assert.Equal(t, model.RoleMember, bot.UserRole("pm123", "CHAN321"))
+	assert.Equal(t, model.RoleAdmin, bot.UserRole("foo123", "CHAN321"))
+
+	assert.True(t, bot.isAllowed(slack.SlashCommand{Command: "/deadline", UserID: "pm123", ChannelID: "CHAN123"}))
+	assert.False(t, bot.isAllowed(slack.SlashCommand{Command: "/deadline", UserID: "pm123", ChannelID: "CHAN321"}))
+	assert.True(t, bot.isAllowed(slack.SlashCommand{Command: "/deadline", UserID: "foo123", ChannelID: "CHAN321"}))
+
+	// the project already has PM, so members can not join as one
+	assert.False(t, bot.isAllowed(slack.SlashCommand{Command: "/start", Text: "pm", UserID: "member123", ChannelID: "CHAN123"}))
+	assert.True(t, bot.isAllowed(slack.SlashCommand{Command: "/start", Text: "developer", UserID: "member123", ChannelID: "CHAN123"}))
+	// PMs are assigned by admins and PMs even in projects without one
+	assert.False(t, bot.isAllowed(slack.SlashCommand{Command: "/start", Text: "pm", UserID: "member123", ChannelID: "CHAN321"}))
+	assert.True(t, bot.isAllowed(slack.SlashCommand{Command: "/start", Text: "pm", UserID: "foo123", ChannelID: "CHAN321"}))
+}
+
+func TestAdminCommand(t *testing.T) {
+	command := slack.SlashCommand{
+		Command:   "/admin",
+		TeamID:    "testTeam",
+		UserID:    "member123",
+		ChannelID: "CHAN123",
+	}
+	assert.Equal(t, "Workspace admins: <@foo123>", bot.ImplementCommands(command))
+
+	command.Text = "add <@member123|member>"
+	assert.Equal(t, "Only workspace admins can manage admins", bot.ImplementCommands(command))
+
+	command.Text = "promote <@member123>"
+	assert.Equal(t, "Use '/admin', '/admin add @user' or '/admin remove @user'", bot.ImplementCommands(command))
+
+	command.UserID = "foo123"
+	command.Text = "add <@member123|member>"
+	assert.Equal(t, "Workspace admins: <@foo123>, <@member123>", bot.ImplementCommands(command))
+	assert.Equal(t, model.RoleAdmin, bot.UserRole("member123", ""))
+
+	command.Text = "remove <@member123>"
+	assert.Equal(t, "Workspace admins: <@foo123>", bot.ImplementCommands(command))
+
+	command.Text = "remove <@member123>"
+	assert.Equal(t, "<@member123> is not an admin", bot.ImplementCommands(command))
+
+	command.Text = "remove <@foo123>"
+	assert.Equal(t, "Workspace must have at least one admin", bot.ImplementCommands(command))
+}
+
+func TestMentionedUserID(t *testing.T) {
+	assert.Equal(t, "U123", mentionedUserID("<@U123|john>"))
+	assert.Equal(t, "U123", mentionedUserID("<@U123>"))
+	assert.Equal(t, "123456", mentionedUserID("123456"))
+}
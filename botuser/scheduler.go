@@ -0,0 +1,168 @@
+package botuser
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/maddevsio/comedian/config"
+	"github.com/maddevsio/comedian/model"
+	"github.com/olebedev/when"
+	"github.com/olebedev/when/rules/en"
+	"github.com/olebedev/when/rules/ru"
+	log "github.com/sirupsen/logrus"
+)
+
+const schedulerInterval = 30 * time.Second
+
+// leaseDuration is how long a replica owns scheduled jobs of the workspace.
+// Lease is renewed on every tick, so another replica takes over once the
+// owner misses a few ticks in a row
+const leaseDuration = 3 * schedulerInterval
+
+// job is a task the scheduler runs over consecutive time windows.
+// Last processed moment is stored in database, so every window is processed
+// once even if the bot was stopped or the tick was late
+type job struct {
+	name string
+	// catchUp limits how far in the past missed windows are still processed
+	catchUp time.Duration
+	run     func(from, to time.Time) error
+}
+
+func (bot *Bot) jobs() []job {
+	return []job{
+		{name: "dm_prompt", catchUp: 15 * time.Minute, run: bot.promptStandupers},
+		{name: "deadline_warning", catchUp: 15 * time.Minute, run: bot.warnNonReporters},
+		{name: "deadline_alarm", catchUp: time.Hour, run: bot.alarmNonReporters},
+		{name: "reminder", catchUp: time.Hour, run: bot.remindNonReporters},
+		{name: "daily_report", catchUp: 12 * time.Hour, run: bot.CallDisplayYesterdayTeamReport},
+		{name: "weekly_report", catchUp: 12 * time.Hour, run: bot.CallDisplayWeeklyTeamReport},
+		{name: "worklog_reminder", catchUp: 12 * time.Hour, run: bot.remindAboutWorklogs},
+		{name: "webhook_delivery", catchUp: time.Hour, run: bot.deliverWebhooks},
+	}
+}
+
+// replicaID identifies this process among Comedian replicas sharing the database
+func replicaID(conf *config.Config) string {
+	if conf != nil && conf.ReplicaID != "" {
+		return conf.ReplicaID
+	}
+	host, err := os.Hostname()
+	if err != nil {
+		host = "comedian"
+	}
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}
+
+func (bot *Bot) leaseName() string {
+	return "scheduler:" + bot.workspace.WorkspaceID
+}
+
+// holdLease takes or renews lease on scheduled jobs of the workspace,
+// only the replica holding it runs the jobs
+func (bot *Bot) holdLease(now time.Time) bool {
+	held, err := bot.db.AcquireLease(bot.leaseName(), bot.replicaID, now.Unix(), now.Add(leaseDuration).Unix())
+	if err != nil {
+		log.Error("AcquireLease failed: ", err)
+		return false
+	}
+	return held
+}
+
+// releaseLease lets other replicas take over jobs without waiting for the lease to expire
+func (bot *Bot) releaseLease() {
+	err := bot.db.ReleaseLease(bot.leaseName(), bot.replicaID)
+	if err != nil {
+		log.Error("ReleaseLease failed: ", err)
+	}
+}
+
+func (bot *Bot) runJobs(now time.Time) {
+	for _, j := range bot.jobs() {
+		err := bot.runJob(j, now)
+		if err != nil {
+			log.Errorf("job %v failed: %v", j.name, err)
+		}
+	}
+}
+
+// runJob runs job over the window from its last run till now. The window is
+// claimed before running, so a job is never run twice for the same moment.
+// Window of failed run is given back and retried on the next tick
+func (bot *Bot) runJob(j job, now time.Time) error {
+	state, err := bot.db.FindJob(bot.workspace.WorkspaceID, j.name)
+	if err == sql.ErrNoRows {
+		// nothing to catch up on the very first run
+		_, err = bot.db.CreateJob(model.Job{
+			WorkspaceID: bot.workspace.WorkspaceID,
+			Name:        j.name,
+			LastRun:     now.Unix(),
+		})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	if now.Unix() <= state.LastRun {
+		return nil
+	}
+
+	from := time.Unix(state.LastRun, 0)
+	if now.Sub(from) > j.catchUp {
+		log.Warningf("job %v missed runs from %v, catching up from %v", j.name, from, now.Add(-j.catchUp))
+		from = now.Add(-j.catchUp)
+	}
+
+	claimed, err := bot.db.ClaimJob(state, now.Unix())
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		return nil
+	}
+
+	err = j.run(from, now)
+	if err != nil {
+		claimedState := state
+		claimedState.LastRun = now.Unix()
+		_, releaseErr := bot.db.ClaimJob(claimedState, state.LastRun)
+		if releaseErr != nil {
+			log.Errorf("could not give back window of job %v: %v", j.name, releaseErr)
+		}
+	}
+	return err
+}
+
+// occurrences returns moments in (from, to] when clock in loc shows the time
+// written in text, such as "10am" or "13:00"
+func occurrences(text string, loc *time.Location, from, to time.Time) []time.Time {
+	w := when.New(nil)
+	w.Add(en.All...)
+	w.Add(ru.All...)
+
+	result := []time.Time{}
+
+	from = from.In(loc)
+	to = to.In(loc)
+	day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc)
+
+	for !day.After(to) {
+		r, err := w.Parse(text, day)
+		if err != nil || r == nil {
+			log.Errorf("could not parse time %v: %v", text, err)
+			return result
+		}
+
+		t := time.Date(day.Year(), day.Month(), day.Day(), r.Time.Hour(), r.Time.Minute(), 0, 0, loc)
+		if t.After(from) && !t.After(to) {
+			result = append(result, t)
+		}
+
+		day = day.AddDate(0, 0, 1)
+	}
+
+	return result
+}
@@ -0,0 +1,113 @@
+package botuser
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunJob(t *testing.T) {
+	windows := [][2]time.Time{}
+	j := job{
+		name:    "test_job",
+		catchUp: time.Hour,
+		run: func(from, to time.Time) error {
+			windows = append(windows, [2]time.Time{from, to})
+			return nil
+		},
+	}
+
+	start := time.Date(2026, time.March, 2, 9, 0, 0, 0, time.UTC)
+
+	require.NoError(t, bot.runJob(j, start))
+	assert.Equal(t, 0, len(windows))
+
+	require.NoError(t, bot.runJob(j, start.Add(time.Minute)))
+	require.Equal(t, 1, len(windows))
+	assert.Equal(t, start.Unix(), windows[0][0].Unix())
+	assert.Equal(t, start.Add(time.Minute).Unix(), windows[0][1].Unix())
+
+	// the same moment is never processed twice
+	require.NoError(t, bot.runJob(j, start.Add(time.Minute)))
+	require.NoError(t, bot.runJob(j, start.Add(30*time.Second)))
+	assert.Equal(t, 1, len(windows))
+
+	// missed windows are caught up within catch up limit
+	require.NoError(t, bot.runJob(j, start.Add(3*time.Hour)))
+	require.Equal(t, 2, len(windows))
+	assert.Equal(t, start.Add(2*time.Hour).Unix(), windows[1][0].Unix())
+	assert.Equal(t, start.Add(3*time.Hour).Unix(), windows[1][1].Unix())
+
+	state, err := bot.db.FindJob("testTeam", "test_job")
+	require.NoError(t, err)
+	assert.Equal(t, start.Add(3*time.Hour).Unix(), state.LastRun)
+
+	// window claimed by another instance is skipped
+	claimed, err := bot.db.ClaimJob(state, start.Add(4*time.Hour).Unix())
+	require.NoError(t, err)
+	assert.True(t, claimed)
+
+	require.NoError(t, bot.runJob(j, start.Add(4*time.Hour)))
+	assert.Equal(t, 2, len(windows))
+
+	assert.NoError(t, bot.db.DeleteJob(state.ID))
+}
+
+func TestRunJobRetriesFailedWindow(t *testing.T) {
+	windows := [][2]time.Time{}
+	fail := true
+	j := job{
+		name:    "failing_job",
+		catchUp: time.Hour,
+		run: func(from, to time.Time) error {
+			windows = append(windows, [2]time.Time{from, to})
+			if fail {
+				return errors.New("chat is down")
+			}
+			return nil
+		},
+	}
+
+	start := time.Date(2026, time.March, 2, 9, 0, 0, 0, time.UTC)
+	require.NoError(t, bot.runJob(j, start))
+
+	assert.Error(t, bot.runJob(j, start.Add(time.Minute)))
+	state, err := bot.db.FindJob("testTeam", "failing_job")
+	require.NoError(t, err)
+	assert.Equal(t, start.Unix(), state.LastRun)
+
+	// the failed window is run again together with the next one
+	fail = false
+	require.NoError(t, bot.runJob(j, start.Add(2*time.Minute)))
+	require.Equal(t, 2, len(windows))
+	assert.Equal(t, start.Unix(), windows[1][0].Unix())
+	assert.Equal(t, start.Add(2*time.Minute).Unix(), windows[1][1].Unix())
+
+	state, err = bot.db.FindJob("testTeam", "failing_job")
+	require.NoError(t, err)
+	assert.Equal(t, start.Add(2*time.Minute).Unix(), state.LastRun)
+
+	assert.NoError(t, bot.db.DeleteJob(state.ID))
+}
+
+func TestHoldLease(t *testing.T) {
+	now := time.Now()
+
+	replica := *bot
+	replica.replicaID = "another replica"
+
+	assert.True(t, bot.holdLease(now))
+	assert.False(t, replica.holdLease(now))
+
+	// owner stopped renewing the lease
+	assert.True(t, replica.holdLease(now.Add(leaseDuration+time.Second)))
+	assert.False(t, bot.holdLease(now.Add(leaseDuration+2*time.Second)))
+
+	replica.releaseLease()
+	assert.True(t, bot.holdLease(now.Add(leaseDuration+3*time.Second)))
+
+	bot.releaseLease()
+}
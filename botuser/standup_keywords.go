@@ -0,0 +1,212 @@
+package botuser
+
+import (
+	"database/sql"
+	"strings"
+
+	"github.com/maddevsio/comedian/model"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/nlopes/slack"
+	log "github.com/sirupsen/logrus"
+)
+
+type standupRule struct {
+	section  string
+	keys     []string
+	required bool
+}
+
+var sectionWarnings = map[string]*i18n.Message{
+	model.SectionYesterday: {
+		ID:    "noYesterdayMention",
+		Other: "- no 'yesterday' keywords detected: {{.Keywords}}",
+	},
+	model.SectionToday: {
+		ID:    "noTodayMention",
+		Other: "- no 'today' keywords detected: {{.Keywords}}",
+	},
+	model.SectionProblems: {
+		ID:    "noProblemsMention",
+		Other: "- no 'problems' keywords detected: {{.Keywords}}",
+	},
+}
+
+// standupRules returns keywords of every standup section configured in project.
+// Sections without custom keywords use the default ones, all sections are required unless marked optional
+func standupRules(project model.Project) []standupRule {
+	keywords := map[string][]string{
+		model.SectionYesterday: splitList(project.YesterdayKeywords),
+		model.SectionToday:     splitList(project.TodayKeywords),
+		model.SectionProblems:  splitList(project.ProblemKeywords),
+	}
+	defaults := map[string][]string{
+		model.SectionYesterday: yesterdayWorkKeys,
+		model.SectionToday:     todayPlansKeys,
+		model.SectionProblems:  problemKeys,
+	}
+	optional := splitList(project.OptionalSections)
+
+	rules := []standupRule{}
+	for _, section := range model.StandupSections {
+		keys := keywords[section]
+		if len(keys) == 0 {
+			keys = defaults[section]
+		}
+		rules = append(rules, standupRule{
+			section:  section,
+			keys:     keys,
+			required: !contains(optional, section),
+		})
+	}
+	return rules
+}
+
+// standupRules returns rules of the channel project, or default ones if channel is not a project
+func (bot *Bot) standupRules(channelID string) []standupRule {
+	project, err := bot.db.SelectProject(channelID)
+	if err != nil && err != sql.ErrNoRows {
+		log.Error("SelectProject failed: ", err)
+	}
+	return standupRules(project)
+}
+
+func (bot *Bot) modifyStandupKeywords(command slack.SlashCommand) string {
+	channel, err := bot.db.SelectProject(command.ChannelID)
+	if err != nil {
+		keywordsNotSet, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+			DefaultMessage: &i18n.Message{
+				ID:    "keywordsNotSet",
+				Other: "Could not change standup keywords",
+			},
+		})
+		if err != nil {
+			log.Error(err)
+		}
+		return keywordsNotSet
+	}
+
+	text := strings.TrimSpace(command.Text)
+	if text == "" {
+		return bot.showStandupKeywords(channel)
+	}
+
+	fields := strings.SplitN(text, " ", 2)
+	section := strings.ToLower(fields[0])
+	value := ""
+	if len(fields) > 1 {
+		value = strings.TrimSpace(fields[1])
+	}
+
+	if !contains(model.StandupSections, section) || value == "" {
+		msg, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+			DefaultMessage: &i18n.Message{
+				ID:    "wrongStandupKeywordsFormat",
+				Other: "Use '/standup_keywords <section> <keyword, keyword>', '<section> optional', '<section> required' or '<section> default', where section is one of: {{.Sections}}",
+			},
+			TemplateData: map[string]interface{}{
+				"Sections": strings.Join(model.StandupSections, ", "),
+			},
+		})
+		if err != nil {
+			log.Error(err)
+		}
+		return msg
+	}
+
+	optional := splitList(channel.OptionalSections)
+
+	switch strings.ToLower(value) {
+	case "optional":
+		if !contains(optional, section) {
+			optional = append(optional, section)
+		}
+		channel.OptionalSections = strings.Join(optional, ",")
+	case "required":
+		sections := []string{}
+		for _, s := range optional {
+			if s != section {
+				sections = append(sections, s)
+			}
+		}
+		channel.OptionalSections = strings.Join(sections, ",")
+	default:
+		keywords := strings.Join(splitList(value), ",")
+		if strings.ToLower(value) == "default" {
+			keywords = ""
+		}
+		switch section {
+		case model.SectionYesterday:
+			channel.YesterdayKeywords = keywords
+		case model.SectionToday:
+			channel.TodayKeywords = keywords
+		case model.SectionProblems:
+			channel.ProblemKeywords = keywords
+		}
+	}
+
+	channel, err = bot.db.UpdateProject(channel)
+	if err != nil {
+		log.Error(err)
+		msg, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+			DefaultMessage: &i18n.Message{
+				ID:    "failedUpdateStandupKeywords",
+				Other: "Failed to update standup keywords",
+			},
+		})
+		if err != nil {
+			log.Error(err)
+		}
+		return msg
+	}
+
+	return bot.showStandupKeywords(channel)
+}
+
+func (bot *Bot) showStandupKeywords(channel model.Project) string {
+	lines := []string{}
+	for _, rule := range standupRules(channel) {
+		message := &i18n.Message{
+			ID:    "showRequiredSection",
+			Other: "'{{.Section}}' is required, keywords: {{.Keywords}}",
+		}
+		if !rule.required {
+			message = &i18n.Message{
+				ID:    "showOptionalSection",
+				Other: "'{{.Section}}' is optional, keywords: {{.Keywords}}",
+			}
+		}
+		line, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+			DefaultMessage: message,
+			TemplateData: map[string]interface{}{
+				"Section":  rule.section,
+				"Keywords": strings.Join(rule.keys, ", "),
+			},
+		})
+		if err != nil {
+			log.Error(err)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// splitList splits comma separated list into lowercase non empty items
+func splitList(list string) []string {
+	items := []string{}
+	for _, item := range strings.Split(list, ",") {
+		item = strings.ToLower(strings.TrimSpace(item))
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func contains(items []string, item string) bool {
+	for _, i := range items {
+		if i == item {
+			return true
+		}
+	}
+	return false
+}
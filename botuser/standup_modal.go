@@ -0,0 +1,251 @@
+package botuser
+
+import (
+	"strings"
+	"time"
+
+	"github.com/maddevsio/comedian/messenger"
+	"github.com/maddevsio/comedian/model"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/nlopes/slack"
+	log "github.com/sirupsen/logrus"
+)
+
+// StandupModalCallbackID identifies submissions of standup modal
+const StandupModalCallbackID = "standup_modal"
+
+// StandupModalActionID is action ID of every input of standup modal, inputs are told apart by block ID
+const StandupModalActionID = "value"
+
+var sectionTitles = map[string]*i18n.Message{
+	model.SectionYesterday: {
+		ID:    "sectionYesterday",
+		Other: "Yesterday",
+	},
+	model.SectionToday: {
+		ID:    "sectionToday",
+		Other: "Today",
+	},
+	model.SectionProblems: {
+		ID:    "sectionProblems",
+		Other: "Blockers",
+	},
+}
+
+// openStandupModal shows form with a field per standup section,
+// submitted form is handled by HandleStandupSubmission
+func (bot *Bot) openStandupModal(command slack.SlashCommand) string {
+	project, err := bot.db.SelectProject(command.ChannelID)
+	if err != nil {
+		standupModalNoProject, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+			DefaultMessage: &i18n.Message{
+				ID:    "standupModalNoProject",
+				Other: "Standups are not collected in this channel",
+			},
+		})
+		if err != nil {
+			log.Error(err)
+		}
+		return standupModalNoProject
+	}
+
+	opener, ok := bot.chat.(messenger.ViewOpener)
+	if !ok {
+		standupModalNotSupported, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+			DefaultMessage: &i18n.Message{
+				ID:    "standupModalNotSupported",
+				Other: "Standup form is not supported here, write standup mentioning the bot instead",
+			},
+		})
+		if err != nil {
+			log.Error(err)
+		}
+		return standupModalNotSupported
+	}
+
+	err = opener.OpenView(command.TriggerID, bot.standupModal(project))
+	if err != nil {
+		log.Error("OpenView failed: ", err)
+		failedOpenStandupModal, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+			DefaultMessage: &i18n.Message{
+				ID:    "failedOpenStandupModal",
+				Other: "Failed to open standup form",
+			},
+		})
+		if err != nil {
+			log.Error(err)
+		}
+		return failedOpenStandupModal
+	}
+	return ""
+}
+
+// standupModal builds Block Kit modal of the project. Channel is kept in
+// private metadata, since submission payload does not tell where modal was opened
+func (bot *Bot) standupModal(project model.Project) map[string]interface{} {
+	blocks := []map[string]interface{}{}
+	for _, rule := range standupRules(project) {
+		blocks = append(blocks, map[string]interface{}{
+			"type":     "input",
+			"block_id": rule.section,
+			"optional": !rule.required,
+			"label":    plainText(bot.sectionTitle(rule.section)),
+			"element": map[string]interface{}{
+				"type":      "plain_text_input",
+				"action_id": StandupModalActionID,
+				"multiline": true,
+			},
+		})
+	}
+
+	standupModalTitle, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+		DefaultMessage: &i18n.Message{
+			ID:    "standupModalTitle",
+			Other: "Standup",
+		},
+	})
+	if err != nil {
+		log.Error(err)
+	}
+
+	standupModalSubmit, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+		DefaultMessage: &i18n.Message{
+			ID:    "standupModalSubmit",
+			Other: "Submit",
+		},
+	})
+	if err != nil {
+		log.Error(err)
+	}
+
+	return map[string]interface{}{
+		"type":             "modal",
+		"callback_id":      StandupModalCallbackID,
+		"private_metadata": project.ChannelID,
+		"title":            plainText(standupModalTitle),
+		"submit":           plainText(standupModalSubmit),
+		"blocks":           blocks,
+	}
+}
+
+// HandleStandupSubmission saves standup submitted with modal and posts it to the channel.
+// Values are keyed by standup section, view ID of the modal is stored in place of message timestamp.
+// Returned map contains errors of invalid sections
+func (bot *Bot) HandleStandupSubmission(viewID, channelID, userID string, values map[string]string) (map[string]string, error) {
+	project, err := bot.db.SelectProject(channelID)
+	if err != nil {
+		return nil, err
+	}
+
+	fieldErrors := map[string]string{}
+	for _, rule := range standupRules(project) {
+		if rule.required && strings.TrimSpace(values[rule.section]) == "" {
+			sectionRequired, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+				DefaultMessage: &i18n.Message{
+					ID:    "sectionRequired",
+					Other: "This section is required in the channel",
+				},
+			})
+			if err != nil {
+				log.Error(err)
+			}
+			fieldErrors[rule.section] = sectionRequired
+		}
+	}
+	if len(fieldErrors) > 0 {
+		return fieldErrors, nil
+	}
+
+	standup := model.Standup{
+		CreatedAt:   time.Now().Unix(),
+		WorkspaceID: bot.workspace.WorkspaceID,
+		ChannelID:   channelID,
+		UserID:      userID,
+		MessageTS:   viewID,
+	}
+	text, attachments := bot.compileStandup(&standup, values)
+
+	_, err = bot.saveCompiledStandup(standup)
+	if err != nil {
+		return nil, err
+	}
+
+	// standup is already saved, so the modal is closed even if it was not posted
+	err = bot.SendMessage(channelID, text, attachments)
+	if err != nil {
+		log.Error("Failed to post standup submitted with modal: ", err)
+	}
+	return nil, nil
+}
+
+// compileStandup fills sections and comment of standup with answers keyed by section
+// and returns message presenting the standup in the channel
+func (bot *Bot) compileStandup(standup *model.Standup, values map[string]string) (string, []slack.Attachment) {
+	standup.Done = strings.TrimSpace(values[model.SectionYesterday])
+	standup.Planned = strings.TrimSpace(values[model.SectionToday])
+	standup.Blockers = strings.TrimSpace(values[model.SectionProblems])
+
+	fields := []slack.AttachmentField{}
+	comment := []string{}
+	for _, section := range model.StandupSections {
+		value := strings.TrimSpace(values[section])
+		if value == "" {
+			continue
+		}
+		title := bot.sectionTitle(section)
+		fields = append(fields, slack.AttachmentField{Title: title, Value: value})
+		comment = append(comment, title+":\n"+value)
+	}
+	standup.Comment = strings.Join(comment, "\n")
+
+	standupSubmitted, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+		DefaultMessage: &i18n.Message{
+			ID:    "standupSubmitted",
+			Other: "Standup of <@{{.user}}>",
+		},
+		TemplateData: map[string]interface{}{
+			"user": standup.UserID,
+		},
+	})
+	if err != nil {
+		log.Error(err)
+	}
+
+	return standupSubmitted, []slack.Attachment{{
+		Color:  "good",
+		Fields: fields,
+	}}
+}
+
+// saveCompiledStandup stores standup and tracks its blockers the same way as of standups written in channel
+func (bot *Bot) saveCompiledStandup(standup model.Standup) (model.Standup, error) {
+	blockers := standup.Blockers
+	standup.Blockers = bot.carryBlockers(standup)
+
+	standup, err := bot.db.CreateStandup(standup)
+	if err != nil {
+		return standup, err
+	}
+	bot.trackBlocker(standup, blockers)
+	bot.dropStandupDraft(standup.UserID, standup.ChannelID)
+	bot.emit(model.EventStandupCreated, standup)
+	return standup, nil
+}
+
+func (bot *Bot) sectionTitle(section string) string {
+	title, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+		DefaultMessage: sectionTitles[section],
+	})
+	if err != nil {
+		log.Error(err)
+	}
+	return title
+}
+
+func plainText(text string) map[string]interface{} {
+	return map[string]interface{}{
+		"type":  "plain_text",
+		"text":  text,
+		"emoji": true,
+	}
+}
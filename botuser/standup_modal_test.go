@@ -0,0 +1,63 @@
+package botuser
+
+import (
+	"testing"
+
+	"github.com/maddevsio/comedian/model"
+	"github.com/nlopes/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStandupModal(t *testing.T) {
+	view := bot.standupModal(model.Project{ChannelID: "CHAN123", OptionalSections: "problems"})
+	assert.Equal(t, StandupModalCallbackID, view["callback_id"])
+	assert.Equal(t, "CHAN123", view["private_metadata"])
+
+	blocks := view["blocks"].([]map[string]interface{})
+	require.Equal(t, 3, len(blocks))
+	assert.Equal(t, model.SectionYesterday, blocks[0]["block_id"])
+	assert.Equal(t, false, blocks[0]["optional"])
+	assert.Equal(t, model.SectionProblems, blocks[2]["block_id"])
+	assert.Equal(t, true, blocks[2]["optional"])
+
+	resp := bot.ImplementCommands(slack.SlashCommand{
+		Command:   "/standup",
+		TeamID:    "testTeam",
+		UserID:    "foo123",
+		ChannelID: "UNKNOWN",
+	})
+	assert.Equal(t, "Standups are not collected in this channel", resp)
+}
+
+func TestHandleStandupSubmission(t *testing.T) {
+	_, err := bot.HandleStandupSubmission("V1", "UNKNOWN", "USER1", map[string]string{})
+	assert.Error(t, err)
+
+	fieldErrors, err := bot.HandleStandupSubmission("V1", "CHAN123", "USER1", map[string]string{
+		model.SectionYesterday: "fixed login",
+		model.SectionToday:     " ",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		model.SectionToday:    "This section is required in the channel",
+		model.SectionProblems: "This section is required in the channel",
+	}, fieldErrors)
+
+	// message can not be posted to Slack in tests, standup is saved anyway
+	fieldErrors, err = bot.HandleStandupSubmission("V1", "CHAN123", "USER1", map[string]string{
+		model.SectionYesterday: "fixed login",
+		model.SectionToday:     "review",
+		model.SectionProblems:  "none",
+	})
+	require.NoError(t, err)
+	assert.Nil(t, fieldErrors)
+
+	standup, err := bot.db.SelectLatestStandupByUser("USER1", "CHAN123")
+	require.NoError(t, err)
+	assert.Equal(t, "fixed login", standup.Done)
+	assert.Equal(t, "review", standup.Planned)
+	assert.Equal(t, "V1", standup.MessageTS)
+	assert.Equal(t, "Yesterday:\nfixed login\nToday:\nreview\nBlockers:\nnone", standup.Comment)
+	assert.NoError(t, bot.db.DeleteStandup(standup.ID))
+}
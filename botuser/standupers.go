@@ -5,6 +5,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/maddevsio/comedian/messenger"
 	"github.com/maddevsio/comedian/model"
 	"github.com/nicksnyder/go-i18n/v2/i18n"
 	"github.com/nlopes/slack"
@@ -26,17 +27,16 @@ func (bot *Bot) joinCommand(command slack.SlashCommand) string {
 		return youAlreadyStandup
 	}
 
-	u, err := bot.slack.GetUserInfo(command.UserID)
+	u, err := bot.chat.GetUser(command.UserID)
 	if err != nil {
-		log.Error("joinCommand bot.slack.GetUserInfo failed: ", err)
-		u = &slack.User{RealName: command.UserName}
+		log.Error("joinCommand bot.chat.GetUser failed: ", err)
+		u = messenger.User{RealName: command.UserName}
 	}
 
-	ch, err := bot.slack.GetConversationInfo(command.ChannelID, true)
+	ch, err := bot.chat.GetChannel(command.ChannelID)
 	if err != nil {
-		log.Error("joinCommand bot.slack.GetChannelInfo failed: ", err)
-		ch = &slack.Channel{}
-		ch.Name = command.ChannelName
+		log.Error("joinCommand bot.chat.GetChannel failed: ", err)
+		ch = messenger.Channel{Name: command.ChannelName}
 	}
 
 	_, err = bot.db.CreateStanduper(model.Standuper{
@@ -108,11 +108,10 @@ func (bot *Bot) showCommand(command slack.SlashCommand) string {
 	var deadline, tz, submittionDays string
 	channel, err := bot.db.SelectProject(command.ChannelID)
 	if err != nil {
-		ch, err := bot.slack.GetChannelInfo(command.ChannelID)
+		ch, err := bot.chat.GetChannel(command.ChannelID)
 		if err != nil {
-			log.Error("Failed to GetChannelInfo in show command: ", err)
-			ch = &slack.Channel{}
-			ch.Name = command.ChannelName
+			log.Error("Failed to GetChannel in show command: ", err)
+			ch = messenger.Channel{Name: command.ChannelName}
 		}
 
 		channel, err = bot.db.CreateProject(model.Project{
@@ -0,0 +1,206 @@
+package botuser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/maddevsio/comedian/messenger"
+	"github.com/nlopes/slack"
+	log "github.com/sirupsen/logrus"
+)
+
+// telegramPollTimeout is how many seconds Bot API holds getUpdates request
+// open while waiting for new updates
+const telegramPollTimeout = 25
+
+// telegramRetryInterval is a pause after failed poll or when another
+// replica polls updates of the bot
+const telegramRetryInterval = 5 * time.Second
+
+// Bot API refuses concurrent getUpdates requests of one bot, so only the
+// replica holding the lease polls. Lease outlives one poll with a margin
+const telegramLeaseDuration = 2 * telegramPollTimeout * time.Second
+
+func (bot *Bot) telegramLeaseName() string {
+	return "telegram:" + bot.workspace.WorkspaceID
+}
+
+// pollTelegram receives updates of Telegram bot with long polling until the bot is stopped.
+// Updates are confirmed by the next request, so updates of a replica that died
+// in the middle of polling are delivered to the one taking over
+func (bot *Bot) pollTelegram(chat *messenger.Telegram) {
+	var offset int64
+	for {
+		select {
+		case <-bot.quitChan:
+			err := bot.db.ReleaseLease(bot.telegramLeaseName(), bot.replicaID)
+			if err != nil {
+				log.Error("ReleaseLease failed: ", err)
+			}
+			return
+		default:
+		}
+
+		now := time.Now()
+		held, err := bot.db.AcquireLease(bot.telegramLeaseName(), bot.replicaID, now.Unix(), now.Add(telegramLeaseDuration).Unix())
+		if err != nil {
+			log.Error("AcquireLease failed: ", err)
+		}
+		if !held {
+			// updates may have been confirmed by another replica meanwhile
+			offset = 0
+			bot.waitTelegramRetry()
+			continue
+		}
+
+		updates, err := chat.Updates(offset, telegramPollTimeout)
+		if err != nil {
+			log.Error("Telegram getUpdates failed: ", err)
+			bot.waitTelegramRetry()
+			continue
+		}
+
+		for _, update := range updates {
+			offset = update.UpdateID + 1
+			bot.handleTelegramUpdate(chat, update)
+		}
+	}
+}
+
+func (bot *Bot) waitTelegramRetry() {
+	select {
+	case <-time.After(telegramRetryInterval):
+	case <-bot.quitChan:
+	}
+}
+
+// handleTelegramUpdate passes Telegram message to the same handlers Slack events
+// and slash commands go through. Group chats are projects, Telegram users are standupers,
+// private chat ID is the user ID
+func (bot *Bot) handleTelegramUpdate(chat *messenger.Telegram, update messenger.TelegramUpdate) {
+	if update.Message != nil {
+		msg := update.Message
+		if msg.From == nil || msg.From.IsBot {
+			return
+		}
+		bot.ensureTelegramProject(msg.Chat)
+
+		if strings.HasPrefix(msg.Text, "/") {
+			command, ok := bot.telegramCommand(msg)
+			if !ok {
+				return
+			}
+			reply := bot.ImplementCommands(command)
+			if reply == "" {
+				return
+			}
+			err := chat.SendMessage(command.ChannelID, reply, nil)
+			if err != nil {
+				log.Error("Failed to reply to telegram command: ", err)
+			}
+			return
+		}
+
+		// private chats carry answers to standup questions of DM mode
+		err := bot.HandleMessage(&slack.MessageEvent{
+			Msg: slack.Msg{
+				Type:      "message",
+				Channel:   telegramID(msg.Chat.ID),
+				User:      telegramID(msg.From.ID),
+				Text:      msg.Text,
+				Timestamp: telegramMessageID(msg),
+			},
+		})
+		if err != nil {
+			log.Error("Failed to handle telegram message: ", err)
+		}
+		return
+	}
+
+	if update.EditedMessage != nil {
+		msg := update.EditedMessage
+		if msg.From == nil || msg.From.IsBot || !msg.Chat.IsGroup() {
+			return
+		}
+		err := bot.HandleMessage(&slack.MessageEvent{
+			Msg: slack.Msg{
+				Type:    "message",
+				SubType: typeEditMessage,
+				Channel: telegramID(msg.Chat.ID),
+				User:    telegramID(msg.From.ID),
+				Text:    msg.Text,
+			},
+			SubMessage: &slack.Msg{
+				User:      telegramID(msg.From.ID),
+				Text:      msg.Text,
+				Timestamp: telegramMessageID(msg),
+			},
+		})
+		if err != nil {
+			log.Error("Failed to handle edited telegram message: ", err)
+		}
+	}
+}
+
+// telegramCommand converts "/command@bot arguments" into slash command.
+// Commands addressed to other bots of the group are skipped
+func (bot *Bot) telegramCommand(msg *messenger.TelegramMessage) (slack.SlashCommand, bool) {
+	text := strings.TrimSpace(msg.Text)
+	name, args := text, ""
+	if i := strings.IndexAny(text, " \n"); i != -1 {
+		name, args = text[:i], strings.TrimSpace(text[i+1:])
+	}
+
+	if i := strings.Index(name, "@"); i != -1 {
+		if !strings.EqualFold(name[i:], bot.workspace.BotUserID) {
+			return slack.SlashCommand{}, false
+		}
+		name = name[:i]
+	}
+
+	userName := strings.TrimSpace(msg.From.FirstName + " " + msg.From.LastName)
+	if userName == "" {
+		userName = msg.From.Username
+	}
+
+	return slack.SlashCommand{
+		TeamID:      bot.workspace.WorkspaceID,
+		ChannelID:   telegramID(msg.Chat.ID),
+		ChannelName: msg.Chat.Title,
+		UserID:      telegramID(msg.From.ID),
+		UserName:    userName,
+		Command:     name,
+		Text:        args,
+	}, true
+}
+
+// ensureTelegramProject creates project for the group, since bot is not
+// notified about groups it was added to before Comedian started
+func (bot *Bot) ensureTelegramProject(chat messenger.TelegramChat) {
+	if !chat.IsGroup() {
+		return
+	}
+	_, err := bot.db.SelectProject(telegramID(chat.ID))
+	if err == nil {
+		return
+	}
+
+	_, err = bot.HandleJoin(&slack.MemberJoinedChannelEvent{
+		Channel: telegramID(chat.ID),
+		Team:    bot.workspace.WorkspaceID,
+	})
+	if err != nil {
+		log.Error("could not create project for telegram group: ", err)
+	}
+}
+
+func telegramID(id int64) string {
+	return strconv.FormatInt(id, 10)
+}
+
+// telegramMessageID is unique across chats, unlike message ID Telegram counts per chat
+func telegramMessageID(msg *messenger.TelegramMessage) string {
+	return fmt.Sprintf("%d:%d", msg.Chat.ID, msg.MessageID)
+}
@@ -0,0 +1,48 @@
+package botuser
+
+import (
+	"testing"
+
+	"github.com/maddevsio/comedian/messenger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTelegramCommand(t *testing.T) {
+	botUserID := bot.workspace.BotUserID
+	bot.workspace.BotUserID = "@comedian_bot"
+	defer func() { bot.workspace.BotUserID = botUserID }()
+
+	testCases := []struct {
+		text    string
+		ok      bool
+		command string
+		args    string
+	}{
+		{"/start", true, "/start", ""},
+		{"/deadline@comedian_bot 10am", true, "/deadline", "10am"},
+		{"/tz@Comedian_Bot  Asia/Bishkek ", true, "/tz", "Asia/Bishkek"},
+		{"/start@other_bot", false, "", ""},
+	}
+
+	for _, tt := range testCases {
+		msg := &messenger.TelegramMessage{
+			MessageID: 3,
+			From:      &messenger.TelegramUser{ID: 7, FirstName: "John", LastName: "Doe"},
+			Chat:      messenger.TelegramChat{ID: -100, Type: "supergroup", Title: "Backend"},
+			Text:      tt.text,
+		}
+		command, ok := bot.telegramCommand(msg)
+		assert.Equal(t, tt.ok, ok, tt.text)
+		if !ok {
+			continue
+		}
+		assert.Equal(t, tt.command, command.Command)
+		assert.Equal(t, tt.args, command.Text)
+		assert.Equal(t, "testTeam", command.TeamID)
+		assert.Equal(t, "-100", command.ChannelID)
+		assert.Equal(t, "Backend", command.ChannelName)
+		assert.Equal(t, "7", command.UserID)
+		assert.Equal(t, "John Doe", command.UserName)
+		assert.Equal(t, "-100:3", telegramMessageID(msg))
+	}
+}
@@ -0,0 +1,116 @@
+package botuser
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/maddevsio/comedian/config"
+	"github.com/maddevsio/comedian/model"
+	log "github.com/sirupsen/logrus"
+)
+
+const defaultWebhookTimeout = 10 * time.Second
+
+// webhookBatch limits deliveries attempted per scheduler tick,
+// so slow endpoints do not hold up other jobs for long
+const webhookBatch = 20
+
+// webhookEvent is the body POSTed to webhooks
+type webhookEvent struct {
+	Event       string      `json:"event"`
+	WorkspaceID string      `json:"workspace_id"`
+	CreatedAt   int64       `json:"created_at"`
+	Data        interface{} `json:"data"`
+}
+
+// missedStandup is data of standup.missed event
+type missedStandup struct {
+	ChannelID   string `json:"channel_id"`
+	ChannelName string `json:"channel_name"`
+	UserID      string `json:"user_id"`
+}
+
+func webhookTimeout(conf *config.Config) time.Duration {
+	if conf == nil || conf.WebhookTimeout <= 0 {
+		return defaultWebhookTimeout
+	}
+	return conf.WebhookTimeout
+}
+
+// emit queues event for every enabled webhook of the workspace subscribed to it.
+// Events are delivered by the scheduler, so failures never affect the caller
+func (bot *Bot) emit(event string, data interface{}) {
+	webhooks, err := bot.db.ListWorkspaceWebhooks(bot.workspace.WorkspaceID)
+	if err != nil {
+		log.Error("ListWorkspaceWebhooks failed: ", err)
+		return
+	}
+
+	now := time.Now().Unix()
+	payload, err := json.Marshal(webhookEvent{
+		Event:       event,
+		WorkspaceID: bot.workspace.WorkspaceID,
+		CreatedAt:   now,
+		Data:        data,
+	})
+	if err != nil {
+		log.Error("could not marshal webhook event: ", err)
+		return
+	}
+
+	for _, w := range webhooks {
+		if !w.Subscribed(event) {
+			continue
+		}
+		_, err := bot.db.CreateWebhookDelivery(model.WebhookDelivery{
+			CreatedAt:     now,
+			WorkspaceID:   bot.workspace.WorkspaceID,
+			WebhookID:     w.ID,
+			Event:         event,
+			Payload:       string(payload),
+			Status:        model.DeliveryPending,
+			NextAttemptAt: now,
+		})
+		if err != nil {
+			log.Error("CreateWebhookDelivery failed: ", err)
+		}
+	}
+}
+
+// deliverWebhooks attempts deliveries due by the end of the window
+func (bot *Bot) deliverWebhooks(from, to time.Time) error {
+	deliveries, err := bot.db.ListDueWebhookDeliveries(bot.workspace.WorkspaceID, to.Unix(), webhookBatch)
+	if err != nil {
+		return err
+	}
+
+	webhooks := map[int64]model.Webhook{}
+	for _, d := range deliveries {
+		w, ok := webhooks[d.WebhookID]
+		if !ok {
+			w, err = bot.db.GetWebhook(d.WebhookID)
+			if err != nil {
+				log.Errorf("could not find webhook %v of delivery %v: %v", d.WebhookID, d.ID, err)
+				continue
+			}
+			webhooks[w.ID] = w
+		}
+
+		if w.Enabled {
+			d = bot.webhooks.Deliver(w, d, time.Now())
+		} else {
+			d.Status = model.DeliveryFailed
+			d.Error = "webhook is disabled"
+		}
+
+		_, err = bot.db.UpdateWebhookDelivery(d)
+		if err != nil {
+			log.Error("UpdateWebhookDelivery failed: ", err)
+		}
+		if d.Status == model.DeliveryFailed {
+			log.Warningf("delivery %v of %v to webhook %v failed: %v", d.ID, d.Event, w.ID, d.Error)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,124 @@
+package botuser
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/maddevsio/comedian/model"
+	"github.com/maddevsio/comedian/webhook"
+	"github.com/nlopes/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookDelivery(t *testing.T) {
+	events := []webhookEvent{}
+	signatures := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		var event webhookEvent
+		json.Unmarshal(body, &event)
+		events = append(events, event)
+		signatures = append(signatures, webhook.Sign("secret", r.Header.Get(webhook.TimestampHeader), body))
+		assert.Equal(t, r.Header.Get(webhook.SignatureHeader), signatures[len(signatures)-1])
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	all, err := bot.db.CreateWebhook(model.Webhook{
+		WorkspaceID: "testTeam",
+		URL:         server.URL,
+		Secret:      "secret",
+		Enabled:     true,
+	})
+	require.NoError(t, err)
+	defer bot.db.DeleteWebhook(all.ID)
+
+	missedOnly, err := bot.db.CreateWebhook(model.Webhook{
+		WorkspaceID: "testTeam",
+		URL:         server.URL,
+		Secret:      "secret",
+		Events:      model.EventStandupMissed,
+		Enabled:     true,
+	})
+	require.NoError(t, err)
+	defer bot.db.DeleteWebhook(missedOnly.ID)
+
+	standup, err := bot.db.CreateStandup(model.Standup{
+		WorkspaceID: "testTeam",
+		ChannelID:   "CHAN123",
+		UserID:      "HOOKED",
+		MessageTS:   "webhook.ts",
+	})
+	require.NoError(t, err)
+
+	result, err := bot.handleDeleteMessage(&slack.MessageEvent{Msg: slack.Msg{DeletedTimestamp: "webhook.ts"}})
+	require.NoError(t, err)
+	assert.Equal(t, "standup deleted", result)
+
+	bot.emit(model.EventStandupMissed, missedStandup{ChannelID: "CHAN123", UserID: "HOOKED"})
+
+	require.NoError(t, bot.deliverWebhooks(time.Now().Add(-time.Minute), time.Now()))
+	require.Equal(t, 3, len(events))
+	assert.Equal(t, model.EventStandupDeleted, events[0].Event)
+	assert.Equal(t, "testTeam", events[0].WorkspaceID)
+	assert.Equal(t, float64(standup.ID), events[0].Data.(map[string]interface{})["id"])
+	assert.Equal(t, model.EventStandupMissed, events[1].Event)
+	assert.Equal(t, model.EventStandupMissed, events[2].Event)
+	assert.Equal(t, "HOOKED", events[2].Data.(map[string]interface{})["user_id"])
+
+	deliveries, err := bot.db.ListWebhookDeliveries(all.ID, 10)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(deliveries))
+	assert.Equal(t, model.DeliveryDelivered, deliveries[0].Status)
+	assert.Equal(t, http.StatusNoContent, deliveries[0].ResponseCode)
+
+	// delivered events are not sent again
+	require.NoError(t, bot.deliverWebhooks(time.Now().Add(-time.Minute), time.Now()))
+	assert.Equal(t, 3, len(events))
+}
+
+func TestWebhookRetry(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	w, err := bot.db.CreateWebhook(model.Webhook{
+		WorkspaceID: "testTeam",
+		URL:         server.URL,
+		Secret:      "secret",
+		Enabled:     true,
+	})
+	require.NoError(t, err)
+	defer bot.db.DeleteWebhook(w.ID)
+
+	bot.emit(model.EventStandupCreated, model.Standup{ChannelID: "CHAN123", UserID: "HOOKED"})
+	require.NoError(t, bot.deliverWebhooks(time.Now().Add(-time.Minute), time.Now()))
+
+	deliveries, err := bot.db.ListWebhookDeliveries(w.ID, 10)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(deliveries))
+	assert.Equal(t, model.DeliveryPending, deliveries[0].Status)
+	assert.Equal(t, 1, deliveries[0].Attempts)
+	assert.Equal(t, http.StatusServiceUnavailable, deliveries[0].ResponseCode)
+	assert.True(t, deliveries[0].NextAttemptAt > time.Now().Unix())
+
+	// retry is not due yet
+	require.NoError(t, bot.deliverWebhooks(time.Now().Add(-time.Minute), time.Now()))
+	deliveries, err = bot.db.ListWebhookDeliveries(w.ID, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, deliveries[0].Attempts)
+
+	w.Enabled = false
+	_, err = bot.db.UpdateWebhook(w)
+	require.NoError(t, err)
+	require.NoError(t, bot.deliverWebhooks(time.Now(), time.Now().Add(2*time.Minute)))
+	deliveries, err = bot.db.ListWebhookDeliveries(w.ID, 10)
+	require.NoError(t, err)
+	assert.Equal(t, model.DeliveryFailed, deliveries[0].Status)
+}
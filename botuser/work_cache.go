@@ -0,0 +1,85 @@
+package botuser
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/maddevsio/comedian/model"
+)
+
+// cachedSource remembers work returned by source for ttl, so reports, reminders
+// and commands asking about the same member and period do not repeat requests.
+// Errors are not cached
+type cachedSource struct {
+	source      WorkSource
+	workspaceID string
+	ttl         time.Duration
+	now         func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]cachedWork
+}
+
+type cachedWork struct {
+	data    WorkData
+	expires time.Time
+}
+
+func newCachedSource(source WorkSource, workspaceID string, ttl time.Duration) *cachedSource {
+	return &cachedSource{
+		source:      source,
+		workspaceID: workspaceID,
+		ttl:         ttl,
+		now:         time.Now,
+		entries:     map[string]cachedWork{},
+	}
+}
+
+func (s *cachedSource) Work(member model.Standuper, from, to time.Time) (WorkData, error) {
+	key := s.key(member.UserID+"/"+member.WorkAccount, from, to)
+	return s.cached(key, func() (WorkData, error) {
+		return s.source.Work(member, from, to)
+	})
+}
+
+func (s *cachedSource) ProjectWork(member model.Standuper, project model.Project, from, to time.Time) (WorkData, error) {
+	key := s.key(member.UserID+"/"+member.WorkAccount+"@"+project.ChannelID+"/"+project.WorkProject, from, to)
+	return s.cached(key, func() (WorkData, error) {
+		return s.source.ProjectWork(member, project, from, to)
+	})
+}
+
+func (s *cachedSource) TracksCommits() bool  { return s.source.TracksCommits() }
+func (s *cachedSource) TracksWorklogs() bool { return s.source.TracksWorklogs() }
+
+// key identifies work of subject in the workspace over the date range
+func (s *cachedSource) key(subject string, from, to time.Time) string {
+	return fmt.Sprintf("%s|%s|%s|%s", s.workspaceID, subject, from.Format(workDateFormat), to.Format(workDateFormat))
+}
+
+func (s *cachedSource) cached(key string, get func() (WorkData, error)) (WorkData, error) {
+	now := s.now()
+
+	s.mu.Lock()
+	entry, ok := s.entries[key]
+	s.mu.Unlock()
+	if ok && now.Before(entry.expires) {
+		return entry.data, nil
+	}
+
+	data, err := get()
+	if err != nil {
+		return data, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for k, e := range s.entries {
+		if !now.Before(e.expires) {
+			delete(s.entries, k)
+		}
+	}
+	s.entries[key] = cachedWork{data: data, expires: now.Add(s.ttl)}
+	return data, nil
+}
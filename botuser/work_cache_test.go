@@ -0,0 +1,116 @@
+package botuser
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/maddevsio/comedian/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingSource counts requests and returns number of the request as commits
+type countingSource struct {
+	mu      sync.Mutex
+	calls   int
+	err     error
+	delay   time.Duration
+	running int
+	maxRun  int
+}
+
+func (s *countingSource) Work(member model.Standuper, from, to time.Time) (WorkData, error) {
+	s.mu.Lock()
+	s.calls++
+	calls := s.calls
+	s.running++
+	if s.running > s.maxRun {
+		s.maxRun = s.running
+	}
+	s.mu.Unlock()
+
+	time.Sleep(s.delay)
+
+	s.mu.Lock()
+	s.running--
+	s.mu.Unlock()
+	return WorkData{Commits: calls}, s.err
+}
+
+func (s *countingSource) ProjectWork(member model.Standuper, project model.Project, from, to time.Time) (WorkData, error) {
+	return s.Work(member, from, to)
+}
+
+func (s *countingSource) TracksCommits() bool  { return true }
+func (s *countingSource) TracksWorklogs() bool { return false }
+
+func TestCachedSource(t *testing.T) {
+	now := time.Date(2019, 5, 8, 10, 0, 0, 0, time.UTC)
+	source := &countingSource{}
+	cached := newCachedSource(source, "T1", time.Minute)
+	cached.now = func() time.Time { return now }
+	member := model.Standuper{UserID: "U1", WorkAccount: "u1"}
+	project := model.Project{ChannelID: "C1", WorkProject: "p1"}
+
+	data, err := cached.Work(member, workFrom, workTo)
+	require.NoError(t, err)
+	assert.Equal(t, 1, data.Commits)
+	data, err = cached.Work(member, workFrom, workTo)
+	require.NoError(t, err)
+	assert.Equal(t, 1, data.Commits)
+
+	// other period, member or project is requested separately
+	data, _ = cached.Work(member, workFrom, workFrom)
+	assert.Equal(t, 2, data.Commits)
+	data, _ = cached.Work(model.Standuper{UserID: "U2", WorkAccount: "u2"}, workFrom, workTo)
+	assert.Equal(t, 3, data.Commits)
+	data, _ = cached.ProjectWork(member, project, workFrom, workTo)
+	assert.Equal(t, 4, data.Commits)
+	data, _ = cached.ProjectWork(member, project, workFrom, workTo)
+	assert.Equal(t, 4, data.Commits)
+
+	now = now.Add(time.Minute)
+	data, _ = cached.Work(member, workFrom, workTo)
+	assert.Equal(t, 5, data.Commits)
+	assert.Len(t, cached.entries, 1)
+
+	source.err = errors.New("unavailable")
+	_, err = cached.Work(member, workTo, workTo)
+	assert.Error(t, err)
+	_, err = cached.Work(member, workTo, workTo)
+	assert.Error(t, err)
+	assert.Equal(t, 7, source.calls)
+
+	assert.True(t, cached.TracksCommits())
+	assert.False(t, cached.TracksWorklogs())
+}
+
+func TestCollectWork(t *testing.T) {
+	realWork, realConcurrency := bot.work, bot.conf.WorkSourceConcurrency
+	defer func() { bot.work, bot.conf.WorkSourceConcurrency = realWork, realConcurrency }()
+
+	source := &countingSource{delay: 10 * time.Millisecond}
+	bot.work = source
+	bot.conf.WorkSourceConcurrency = 2
+
+	standupers := []model.Standuper{}
+	for i := 0; i < 5; i++ {
+		standupers = append(standupers, model.Standuper{UserID: "U1", ChannelID: "CHAN123"})
+	}
+
+	work := bot.collectWork(standupers, workFrom, workTo)
+	require.Len(t, work, 5)
+	for _, w := range work {
+		assert.NoError(t, w.err)
+		assert.NotZero(t, w.user.Commits)
+		assert.NotZero(t, w.project.Commits)
+	}
+	assert.Equal(t, 10, source.calls)
+	assert.Equal(t, 2, source.maxRun)
+
+	bot.work = nil
+	work = bot.collectWork(standupers[:1], workFrom, workTo)
+	assert.Equal(t, errNoWorkSource, work[0].err)
+}
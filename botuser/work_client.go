@@ -0,0 +1,177 @@
+package botuser
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/maddevsio/comedian/config"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	defaultWorkTimeout     = 10 * time.Second
+	defaultWorkCacheTTL    = 10 * time.Minute
+	defaultWorkConcurrency = 4
+	// workRetryBackoff is delay before the first retry, it doubles with every next one
+	workRetryBackoff = 500 * time.Millisecond
+	// breakerThreshold consecutive failures open the circuit for breakerCooldown,
+	// then one request is let through to check if work source is back
+	breakerThreshold = 5
+	breakerCooldown  = time.Minute
+)
+
+var errCircuitOpen = errors.New("work source is unavailable, requests are paused")
+
+// workClient sends requests to work source of a workspace. Requests failing
+// because of network or server errors are retried, and after several failures
+// in a row the source is not asked for a while, so reports do not wait on it
+type workClient struct {
+	http    *http.Client
+	retries int
+	backoff time.Duration
+	breaker *breaker
+}
+
+func newWorkClient(conf *config.Config) *workClient {
+	timeout := defaultWorkTimeout
+	retries := 0
+	if conf != nil {
+		if conf.WorkSourceTimeout > 0 {
+			timeout = conf.WorkSourceTimeout
+		}
+		retries = conf.WorkSourceRetries
+	}
+	return &workClient{
+		http:    &http.Client{Timeout: timeout},
+		retries: retries,
+		backoff: workRetryBackoff,
+		breaker: &breaker{threshold: breakerThreshold, cooldown: breakerCooldown},
+	}
+}
+
+// workHTTPError is response status other than 200
+type workHTTPError struct {
+	code int
+}
+
+func (e workHTTPError) Error() string {
+	return fmt.Sprintf("failed to get work data. %v", e.code)
+}
+
+// temporary tells errors worth retrying: network errors, rate limits and server errors
+func temporary(err error) bool {
+	if e, ok := err.(workHTTPError); ok {
+		return e.code == http.StatusTooManyRequests || e.code >= 500
+	}
+	return true
+}
+
+// fetchJSON sends request and decodes JSON response into v
+func (c *workClient) fetchJSON(req *http.Request, v interface{}) (http.Header, error) {
+	if !c.breaker.allow(time.Now()) {
+		return nil, errCircuitOpen
+	}
+
+	var headers http.Header
+	var body []byte
+	var err error
+	for attempt := 0; attempt <= c.retries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(c.backoff << uint(attempt-1))
+			if req.GetBody != nil {
+				req.Body, err = req.GetBody()
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		headers, body, err = c.send(req)
+		if err == nil || !temporary(err) {
+			break
+		}
+		log.Warningf("work source request %v failed, attempt %d: %v", req.URL.Path, attempt+1, err)
+	}
+
+	if err != nil && temporary(err) {
+		c.breaker.failure(time.Now())
+		return nil, err
+	}
+	c.breaker.success()
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(body, v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse work data: %v", err)
+	}
+	return headers, nil
+}
+
+func (c *workClient) send(req *http.Request) (http.Header, []byte, error) {
+	req.Header.Set("Accept", "application/json")
+	res, err := c.http.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		log.WithFields(log.Fields(map[string]interface{}{"body": string(body), "requestURL": req.URL.String(), "res.StatusCode": res.StatusCode})).Warning("Failed to get work data!")
+		return nil, nil, workHTTPError{res.StatusCode}
+	}
+	return res.Header, body, nil
+}
+
+// breaker is a circuit breaker: it opens after threshold consecutive failures
+// and lets a single trial request through once cooldown passes
+type breaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	trial     bool
+}
+
+func (b *breaker) allow(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures < b.threshold {
+		return true
+	}
+	if now.Before(b.openUntil) || b.trial {
+		return false
+	}
+	b.trial = true
+	return true
+}
+
+func (b *breaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures = 0
+	b.trial = false
+}
+
+func (b *breaker) failure(now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	b.trial = false
+	if b.failures >= b.threshold {
+		b.openUntil = now.Add(b.cooldown)
+	}
+}
@@ -0,0 +1,119 @@
+package botuser
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testWorkClient(retries int) *workClient {
+	client := newWorkClient(nil)
+	client.retries = retries
+	client.backoff = time.Millisecond
+	return client
+}
+
+func TestWorkClientRetries(t *testing.T) {
+	calls := 0
+	bodies := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if calls < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `{"total_commits": 5}`)
+	}))
+	defer server.Close()
+
+	req, err := http.NewRequest("POST", server.URL, strings.NewReader(`{"a": 1}`))
+	require.NoError(t, err)
+	var data WorkData
+	_, err = testWorkClient(2).fetchJSON(req, &data)
+	require.NoError(t, err)
+	assert.Equal(t, 5, data.Commits)
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, []string{`{"a": 1}`, `{"a": 1}`, `{"a": 1}`}, bodies)
+
+	calls = 0
+	req, err = http.NewRequest("GET", server.URL, nil)
+	require.NoError(t, err)
+	_, err = testWorkClient(1).fetchJSON(req, &data)
+	assert.Equal(t, workHTTPError{http.StatusServiceUnavailable}, err)
+	assert.Equal(t, 2, calls)
+}
+
+func TestWorkClientDoesNotRetryClientErrors(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := testWorkClient(2)
+	for i := 0; i < breakerThreshold+1; i++ {
+		req, err := http.NewRequest("GET", server.URL, nil)
+		require.NoError(t, err)
+		_, err = client.fetchJSON(req, &WorkData{})
+		assert.Equal(t, workHTTPError{http.StatusNotFound}, err)
+	}
+	assert.Equal(t, breakerThreshold+1, calls)
+}
+
+func TestWorkClientCircuitBreaker(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := testWorkClient(0)
+	for i := 0; i < breakerThreshold+2; i++ {
+		req, err := http.NewRequest("GET", server.URL, nil)
+		require.NoError(t, err)
+		_, err = client.fetchJSON(req, &WorkData{})
+		if i < breakerThreshold {
+			assert.Equal(t, workHTTPError{http.StatusInternalServerError}, err)
+		} else {
+			assert.Equal(t, errCircuitOpen, err)
+		}
+	}
+	assert.Equal(t, breakerThreshold, calls)
+}
+
+func TestBreaker(t *testing.T) {
+	now := time.Date(2019, 5, 1, 10, 0, 0, 0, time.UTC)
+	b := &breaker{threshold: 2, cooldown: time.Minute}
+
+	assert.True(t, b.allow(now))
+	b.failure(now)
+	assert.True(t, b.allow(now))
+	b.success()
+	b.failure(now)
+	assert.True(t, b.allow(now))
+	b.failure(now)
+	assert.False(t, b.allow(now))
+	assert.False(t, b.allow(now.Add(59*time.Second)))
+
+	// a single trial request is let through after cooldown
+	assert.True(t, b.allow(now.Add(time.Minute)))
+	assert.False(t, b.allow(now.Add(time.Minute)))
+	b.failure(now.Add(time.Minute))
+	assert.False(t, b.allow(now.Add(90*time.Second)))
+
+	assert.True(t, b.allow(now.Add(2*time.Minute)))
+	b.success()
+	assert.True(t, b.allow(now.Add(2*time.Minute)))
+	assert.True(t, b.allow(now.Add(2*time.Minute)))
+}
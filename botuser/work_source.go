@@ -0,0 +1,219 @@
+package botuser
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/maddevsio/comedian/config"
+	"github.com/maddevsio/comedian/model"
+)
+
+const workDateFormat = "2006-01-02"
+
+var errNoWorkSource = errors.New("work source is not configured")
+
+// WorkData is work of a team member over a period: number of commits
+// and seconds of logged time
+type WorkData struct {
+	Commits  int `json:"total_commits"`
+	Worklogs int `json:"worklogs"`
+}
+
+// WorkSource provides work of team members for reports. Periods are given
+// by dates, both of them inclusive
+type WorkSource interface {
+	// Work returns work of member in all projects
+	Work(member model.Standuper, from, to time.Time) (WorkData, error)
+	// ProjectWork returns work of member in the project
+	ProjectWork(member model.Standuper, project model.Project, from, to time.Time) (WorkData, error)
+	// TracksCommits tells if the source knows about commits,
+	// reports do not count commits missing otherwise
+	TracksCommits() bool
+	// TracksWorklogs tells if the source knows about logged time
+	TracksWorklogs() bool
+}
+
+// newWorkSource returns work source configured for the workspace. Workspaces
+// without one keep using Collector of the deployment if it is set
+func newWorkSource(conf *config.Config, settings model.Workspace) WorkSource {
+	source := workSource(conf, settings, newWorkClient(conf))
+	if source == nil {
+		return nil
+	}
+
+	ttl := defaultWorkCacheTTL
+	if conf != nil {
+		ttl = conf.WorkSourceCacheTTL
+	}
+	if ttl <= 0 {
+		return source
+	}
+	return newCachedSource(source, settings.WorkspaceID, ttl)
+}
+
+func workSource(conf *config.Config, settings model.Workspace, client *workClient) WorkSource {
+	switch settings.WorkSource {
+	case model.WorkSourceJira:
+		return jiraSource{url: settings.WorkSourceURL, token: settings.WorkSourceToken, client: client}
+	case model.WorkSourceGitLab:
+		return gitlabSource{url: settings.WorkSourceURL, token: settings.WorkSourceToken, client: client}
+	case model.WorkSourceGitHub:
+		return githubSource{url: settings.WorkSourceURL, token: settings.WorkSourceToken, client: client}
+	case model.WorkSourceToggl:
+		return togglSource{url: settings.WorkSourceURL, token: settings.WorkSourceToken, client: client}
+	case model.WorkSourceGit:
+		return newGitSource(conf, settings.WorkspaceID)
+	case model.WorkSourceCollector:
+		return collectorSource{url: settings.WorkSourceURL, token: settings.WorkSourceToken, workspaceID: settings.WorkspaceID, client: client}
+	}
+
+	if conf == nil || conf.CollectorURL == "" {
+		return nil
+	}
+	return collectorSource{url: conf.CollectorURL, token: conf.CollectorToken, workspaceID: settings.WorkspaceID, client: client}
+}
+
+// MemberWork returns work of member in all projects and in the project of the standuper
+func (bot *Bot) MemberWork(member model.Standuper, from, to time.Time) (WorkData, WorkData, error) {
+	if bot.work == nil {
+		return WorkData{}, WorkData{}, errNoWorkSource
+	}
+
+	dataOnUser, err := bot.work.Work(member, from, to)
+	if err != nil {
+		return WorkData{}, WorkData{}, err
+	}
+
+	dataOnUserInProject, err := bot.ProjectWork(member, from, to)
+	if err != nil {
+		return WorkData{}, WorkData{}, err
+	}
+
+	return dataOnUser, dataOnUserInProject, nil
+}
+
+// ProjectWork returns work of member in the project of the standuper
+func (bot *Bot) ProjectWork(member model.Standuper, from, to time.Time) (WorkData, error) {
+	if bot.work == nil {
+		return WorkData{}, errNoWorkSource
+	}
+
+	project, err := bot.db.SelectProject(member.ChannelID)
+	if err != nil {
+		return WorkData{}, err
+	}
+
+	return bot.work.ProjectWork(member, project, from, to)
+}
+
+// UserWork returns work of user in all projects. Account of the user in work source
+// is taken from any of projects the user is standuper in
+func (bot *Bot) UserWork(userID string, from, to time.Time) (WorkData, error) {
+	if bot.work == nil {
+		return WorkData{}, errNoWorkSource
+	}
+
+	member := model.Standuper{WorkspaceID: bot.workspace.WorkspaceID, UserID: userID}
+	standupers, err := bot.db.FindStansupersByUserID(userID)
+	if err != nil {
+		return WorkData{}, err
+	}
+	for _, standuper := range standupers {
+		if standuper.WorkAccount != "" {
+			member = standuper
+			break
+		}
+	}
+
+	return bot.work.Work(member, from, to)
+}
+
+// memberWork is result of MemberWork
+type memberWork struct {
+	user    WorkData
+	project WorkData
+	err     error
+}
+
+// collectWork gets work of standupers concurrently, asking work source about
+// at most WORK_SOURCE_CONCURRENCY standupers at once. Results are in order of standupers
+func (bot *Bot) collectWork(standupers []model.Standuper, from, to time.Time) []memberWork {
+	concurrency := defaultWorkConcurrency
+	if bot.conf != nil && bot.conf.WorkSourceConcurrency > 0 {
+		concurrency = bot.conf.WorkSourceConcurrency
+	}
+
+	results := make([]memberWork, len(standupers))
+	slots := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, standuper := range standupers {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(i int, standuper model.Standuper) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			user, project, err := bot.MemberWork(standuper, from, to)
+			results[i] = memberWork{user: user, project: project, err: err}
+		}(i, standuper)
+	}
+	wg.Wait()
+	return results
+}
+
+func (bot *Bot) tracksCommits() bool {
+	return bot.work != nil && bot.work.TracksCommits()
+}
+
+func (bot *Bot) tracksWorklogs() bool {
+	return bot.work != nil && bot.work.TracksWorklogs()
+}
+
+// collectorSource is Collector service of Mad Devs, it knows both commits and worklogs
+type collectorSource struct {
+	url         string
+	token       string
+	workspaceID string
+	client      *workClient
+}
+
+func (s collectorSource) Work(member model.Standuper, from, to time.Time) (WorkData, error) {
+	return s.get("users", member.UserID, from, to)
+}
+
+func (s collectorSource) ProjectWork(member model.Standuper, project model.Project, from, to time.Time) (WorkData, error) {
+	return s.get("user-in-project", fmt.Sprintf("%v/%v", member.UserID, project.ChannelName), from, to)
+}
+
+func (s collectorSource) TracksCommits() bool  { return true }
+func (s collectorSource) TracksWorklogs() bool { return true }
+
+func (s collectorSource) get(getDataOn, data string, from, to time.Time) (WorkData, error) {
+	var workData WorkData
+	linkURL := fmt.Sprintf("%s/rest/api/v1/logger/%s/%s/%s/%s/%s/", s.url, s.workspaceID, getDataOn, data, from.Format(workDateFormat), to.Format(workDateFormat))
+	req, err := http.NewRequest("GET", linkURL, nil)
+	if err != nil {
+		return workData, err
+	}
+	req.Header.Add("Authorization", fmt.Sprintf("Token %s", s.token))
+	_, err = s.client.fetchJSON(req, &workData)
+	return workData, err
+}
+
+// account returns account of member in work source
+func account(member model.Standuper) (string, error) {
+	if member.WorkAccount == "" {
+		return "", fmt.Errorf("work account of %v is not set", member.UserID)
+	}
+	return member.WorkAccount, nil
+}
+
+// workProject returns project in work source linked to the channel
+func workProject(project model.Project) (string, error) {
+	if project.WorkProject == "" {
+		return "", fmt.Errorf("work project of %v is not set", project.ChannelName)
+	}
+	return project.WorkProject, nil
+}
@@ -0,0 +1,178 @@
+package botuser
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/maddevsio/comedian/config"
+	"github.com/maddevsio/comedian/model"
+)
+
+var errNoGitReposDir = errors.New("git repositories directory is not configured")
+
+// gitSource counts commits in git repositories cloned on the server. Repositories
+// of a workspace are kept in GIT_REPOS_DIR/<workspace id>, project is path of
+// repository inside the directory and account of member is comma separated list
+// of emails the member commits with
+type gitSource struct {
+	dir     string
+	timeout time.Duration
+}
+
+func newGitSource(conf *config.Config, workspaceID string) gitSource {
+	source := gitSource{timeout: defaultWorkTimeout}
+	if conf == nil {
+		return source
+	}
+	if conf.GitReposDir != "" {
+		source.dir = filepath.Join(conf.GitReposDir, workspaceID)
+	}
+	if conf.WorkSourceTimeout > 0 {
+		source.timeout = conf.WorkSourceTimeout
+	}
+	return source
+}
+
+func (s gitSource) Work(member model.Standuper, from, to time.Time) (WorkData, error) {
+	emails, err := gitEmails(member)
+	if err != nil {
+		return WorkData{}, err
+	}
+	repos, err := s.repos()
+	if err != nil {
+		return WorkData{}, err
+	}
+
+	data := WorkData{}
+	for _, repo := range repos {
+		commits, err := s.commits(repo, emails, from, to)
+		if err != nil {
+			return WorkData{}, err
+		}
+		data.Commits += commits
+	}
+	return data, nil
+}
+
+func (s gitSource) ProjectWork(member model.Standuper, project model.Project, from, to time.Time) (WorkData, error) {
+	emails, err := gitEmails(member)
+	if err != nil {
+		return WorkData{}, err
+	}
+	repo, err := s.repo(project)
+	if err != nil {
+		return WorkData{}, err
+	}
+
+	commits, err := s.commits(repo, emails, from, to)
+	return WorkData{Commits: commits}, err
+}
+
+func (s gitSource) TracksCommits() bool  { return true }
+func (s gitSource) TracksWorklogs() bool { return false }
+
+// repo returns path of repository of the project, it may not point outside of repositories directory
+func (s gitSource) repo(project model.Project) (string, error) {
+	if s.dir == "" {
+		return "", errNoGitReposDir
+	}
+	name, err := workProject(project)
+	if err != nil {
+		return "", err
+	}
+	name = filepath.Clean(name)
+	if filepath.IsAbs(name) || name == ".." || strings.HasPrefix(name, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("work project of %v is outside of git repositories directory", project.ChannelName)
+	}
+	return filepath.Join(s.dir, name), nil
+}
+
+// repos finds all repositories of the workspace, both bare and with working tree
+func (s gitSource) repos() ([]string, error) {
+	if s.dir == "" {
+		return nil, errNoGitReposDir
+	}
+
+	repos := []string{}
+	err := filepath.Walk(s.dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		if isGitRepo(path) {
+			repos = append(repos, path)
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	return repos, err
+}
+
+func isGitRepo(path string) bool {
+	if _, err := os.Stat(filepath.Join(path, ".git")); err == nil {
+		return true
+	}
+	_, headErr := os.Stat(filepath.Join(path, "HEAD"))
+	objects, objectsErr := os.Stat(filepath.Join(path, "objects"))
+	return headErr == nil && objectsErr == nil && objects.IsDir()
+}
+
+// commits counts commits of all branches authored with one of emails in the
+// period. Author dates are compared in time zone of author, like other sources do
+func (s gitSource) commits(repo string, emails []string, from, to time.Time) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	// commits are filtered by author date below, committer date git filters on
+	// is never earlier, a day more covers time zones
+	since := from.AddDate(0, 0, -1).Format(workDateFormat)
+	cmd := exec.CommandContext(ctx, "git", "-C", repo, "log", "--all", "--no-merges", "--since="+since, "--date=short", "--format=%aE%x09%ad")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read commits of %v: %v %s", filepath.Base(repo), err, strings.TrimSpace(stderr.String()))
+	}
+
+	first, last := from.Format(workDateFormat), to.Format(workDateFormat)
+	commits := 0
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		fields := strings.SplitN(scanner.Text(), "\t", 2)
+		if len(fields) != 2 || fields[1] < first || fields[1] > last {
+			continue
+		}
+		for _, email := range emails {
+			if strings.EqualFold(email, fields[0]) {
+				commits++
+				break
+			}
+		}
+	}
+	return commits, scanner.Err()
+}
+
+// gitEmails returns emails member commits with
+func gitEmails(member model.Standuper) ([]string, error) {
+	accounts, err := account(member)
+	if err != nil {
+		return nil, err
+	}
+	emails := []string{}
+	for _, email := range strings.Split(accounts, ",") {
+		if email = strings.TrimSpace(email); email != "" {
+			emails = append(emails, email)
+		}
+	}
+	return emails, nil
+}
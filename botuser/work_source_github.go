@@ -0,0 +1,72 @@
+package botuser
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/maddevsio/comedian/model"
+)
+
+const defaultGitHubURL = "https://api.github.com"
+
+// githubSource counts commits with GitHub commit search. Account of member is
+// GitHub login, project is repository such as "owner/repo". URL is API root
+// of GitHub Enterprise, GitHub.com is used by default
+type githubSource struct {
+	url    string
+	token  string
+	client *workClient
+}
+
+type githubSearch struct {
+	TotalCount int `json:"total_count"`
+}
+
+func (s githubSource) Work(member model.Standuper, from, to time.Time) (WorkData, error) {
+	return s.commits(member, "", from, to)
+}
+
+func (s githubSource) ProjectWork(member model.Standuper, project model.Project, from, to time.Time) (WorkData, error) {
+	repo, err := workProject(project)
+	if err != nil {
+		return WorkData{}, err
+	}
+	return s.commits(member, repo, from, to)
+}
+
+func (s githubSource) TracksCommits() bool  { return true }
+func (s githubSource) TracksWorklogs() bool { return false }
+
+func (s githubSource) commits(member model.Standuper, repo string, from, to time.Time) (WorkData, error) {
+	login, err := account(member)
+	if err != nil {
+		return WorkData{}, err
+	}
+
+	q := fmt.Sprintf("author:%s author-date:%s..%s", login, from.Format(workDateFormat), to.Format(workDateFormat))
+	if repo != "" {
+		q += " repo:" + repo
+	}
+
+	base := s.url
+	if base == "" {
+		base = defaultGitHubURL
+	}
+	req, err := http.NewRequest("GET", strings.TrimSuffix(base, "/")+"/search/commits?"+url.Values{"q": {q}, "per_page": {"1"}}.Encode(), nil)
+	if err != nil {
+		return WorkData{}, err
+	}
+	if s.token != "" {
+		req.Header.Set("Authorization", "token "+s.token)
+	}
+
+	var search githubSearch
+	_, err = s.client.fetchJSON(req, &search)
+	if err != nil {
+		return WorkData{}, err
+	}
+	return WorkData{Commits: search.TotalCount}, nil
+}
@@ -0,0 +1,93 @@
+package botuser
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/maddevsio/comedian/model"
+)
+
+const defaultGitLabURL = "https://gitlab.com"
+
+// gitlabSource counts commits pushed to GitLab. Account of member is GitLab
+// username, project is path of the project such as "group/project"
+type gitlabSource struct {
+	url    string
+	token  string
+	client *workClient
+}
+
+type gitlabEvent struct {
+	AuthorID       int    `json:"author_id"`
+	AuthorUsername string `json:"author_username"`
+	PushData       struct {
+		CommitCount int `json:"commit_count"`
+	} `json:"push_data"`
+}
+
+func (s gitlabSource) Work(member model.Standuper, from, to time.Time) (WorkData, error) {
+	username, err := account(member)
+	if err != nil {
+		return WorkData{}, err
+	}
+	return s.pushedCommits("/api/v4/users/"+url.PathEscape(username)+"/events", username, from, to)
+}
+
+func (s gitlabSource) ProjectWork(member model.Standuper, project model.Project, from, to time.Time) (WorkData, error) {
+	username, err := account(member)
+	if err != nil {
+		return WorkData{}, err
+	}
+	path, err := workProject(project)
+	if err != nil {
+		return WorkData{}, err
+	}
+	return s.pushedCommits("/api/v4/projects/"+url.PathEscape(path)+"/events", username, from, to)
+}
+
+func (s gitlabSource) TracksCommits() bool  { return true }
+func (s gitlabSource) TracksWorklogs() bool { return false }
+
+// pushedCommits sums commits of push events of the user. GitLab takes
+// exclusive dates, so the period is widened by a day on both sides
+func (s gitlabSource) pushedCommits(path, username string, from, to time.Time) (WorkData, error) {
+	data := WorkData{}
+	for page := "1"; page != ""; {
+		events := []gitlabEvent{}
+		headers, err := s.get(path, url.Values{
+			"action":   {"pushed"},
+			"after":    {from.AddDate(0, 0, -1).Format(workDateFormat)},
+			"before":   {to.AddDate(0, 0, 1).Format(workDateFormat)},
+			"per_page": {"100"},
+			"page":     {page},
+		}, &events)
+		if err != nil {
+			return WorkData{}, err
+		}
+
+		for _, e := range events {
+			if e.AuthorUsername == username || strconv.Itoa(e.AuthorID) == username {
+				data.Commits += e.PushData.CommitCount
+			}
+		}
+		page = headers.Get("X-Next-Page")
+	}
+	return data, nil
+}
+
+func (s gitlabSource) get(path string, query url.Values, v interface{}) (http.Header, error) {
+	base := s.url
+	if base == "" {
+		base = defaultGitLabURL
+	}
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s%s?%s", strings.TrimSuffix(base, "/"), path, query.Encode()), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("PRIVATE-TOKEN", s.token)
+	return s.client.fetchJSON(req, v)
+}
@@ -0,0 +1,161 @@
+package botuser
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/maddevsio/comedian/model"
+)
+
+const jiraPageSize = 100
+
+// jiraSource sums time logged to Jira issues. Account of member is Jira
+// account ID, username or email, project is Jira project key. Token is
+// "email:api_token" for Jira Cloud or personal access token for Jira Server
+type jiraSource struct {
+	url    string
+	token  string
+	client *workClient
+}
+
+type jiraWorklog struct {
+	Author struct {
+		AccountID    string `json:"accountId"`
+		Name         string `json:"name"`
+		Key          string `json:"key"`
+		EmailAddress string `json:"emailAddress"`
+	} `json:"author"`
+	Started          string `json:"started"`
+	TimeSpentSeconds int    `json:"timeSpentSeconds"`
+}
+
+type jiraWorklogs struct {
+	Total    int           `json:"total"`
+	Worklogs []jiraWorklog `json:"worklogs"`
+}
+
+type jiraSearch struct {
+	Total  int `json:"total"`
+	Issues []struct {
+		Key    string `json:"key"`
+		Fields struct {
+			Worklog jiraWorklogs `json:"worklog"`
+		} `json:"fields"`
+	} `json:"issues"`
+}
+
+func (s jiraSource) Work(member model.Standuper, from, to time.Time) (WorkData, error) {
+	return s.worklogs(member, "", from, to)
+}
+
+func (s jiraSource) ProjectWork(member model.Standuper, project model.Project, from, to time.Time) (WorkData, error) {
+	key, err := workProject(project)
+	if err != nil {
+		return WorkData{}, err
+	}
+	return s.worklogs(member, key, from, to)
+}
+
+func (s jiraSource) TracksCommits() bool  { return false }
+func (s jiraSource) TracksWorklogs() bool { return true }
+
+// worklogs finds issues member logged time to in the period and sums the time.
+// Issues embed only the first worklogs, the rest are requested separately
+func (s jiraSource) worklogs(member model.Standuper, projectKey string, from, to time.Time) (WorkData, error) {
+	author, err := account(member)
+	if err != nil {
+		return WorkData{}, err
+	}
+
+	jql := fmt.Sprintf(`worklogAuthor = %s AND worklogDate >= "%s" AND worklogDate <= "%s"`, jqlString(author), from.Format(workDateFormat), to.Format(workDateFormat))
+	if projectKey != "" {
+		jql += " AND project = " + jqlString(projectKey)
+	}
+
+	data := WorkData{}
+	for startAt := 0; ; {
+		var search jiraSearch
+		err := s.get("/rest/api/2/search", url.Values{
+			"jql":        {jql},
+			"fields":     {"worklog"},
+			"startAt":    {fmt.Sprint(startAt)},
+			"maxResults": {fmt.Sprint(jiraPageSize)},
+		}, &search)
+		if err != nil {
+			return WorkData{}, err
+		}
+
+		for _, issue := range search.Issues {
+			worklogs := issue.Fields.Worklog
+			if worklogs.Total > len(worklogs.Worklogs) {
+				worklogs, err = s.issueWorklogs(issue.Key)
+				if err != nil {
+					return WorkData{}, err
+				}
+			}
+			for _, w := range worklogs.Worklogs {
+				if w.logged(author, from, to) {
+					data.Worklogs += w.TimeSpentSeconds
+				}
+			}
+		}
+
+		startAt += len(search.Issues)
+		if len(search.Issues) == 0 || startAt >= search.Total {
+			return data, nil
+		}
+	}
+}
+
+func (s jiraSource) issueWorklogs(key string) (jiraWorklogs, error) {
+	all := jiraWorklogs{}
+	for {
+		var page jiraWorklogs
+		err := s.get("/rest/api/2/issue/"+url.PathEscape(key)+"/worklog", url.Values{
+			"startAt":    {fmt.Sprint(len(all.Worklogs))},
+			"maxResults": {fmt.Sprint(jiraPageSize)},
+		}, &page)
+		if err != nil {
+			return all, err
+		}
+		all.Worklogs = append(all.Worklogs, page.Worklogs...)
+		all.Total = page.Total
+		if len(page.Worklogs) == 0 || len(all.Worklogs) >= page.Total {
+			return all, nil
+		}
+	}
+}
+
+func (s jiraSource) get(path string, query url.Values, v interface{}) error {
+	req, err := http.NewRequest("GET", strings.TrimSuffix(s.url, "/")+path+"?"+query.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	if i := strings.Index(s.token, ":"); i != -1 {
+		req.SetBasicAuth(s.token[:i], s.token[i+1:])
+	} else {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+	_, err = s.client.fetchJSON(req, v)
+	return err
+}
+
+// jqlString quotes value for JQL query
+func jqlString(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
+
+// logged tells if worklog is of author and started in the period
+func (w jiraWorklog) logged(author string, from, to time.Time) bool {
+	if author != w.Author.AccountID && author != w.Author.Name && author != w.Author.Key && !strings.EqualFold(author, w.Author.EmailAddress) {
+		return false
+	}
+	if len(w.Started) < len(workDateFormat) {
+		return false
+	}
+	day := w.Started[:len(workDateFormat)]
+	return day >= from.Format(workDateFormat) && day <= to.Format(workDateFormat)
+}
@@ -0,0 +1,371 @@
+package botuser
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/maddevsio/comedian/config"
+	"github.com/maddevsio/comedian/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	workFrom = time.Date(2019, 5, 1, 0, 0, 0, 0, time.UTC)
+	workTo   = time.Date(2019, 5, 7, 0, 0, 0, 0, time.UTC)
+)
+
+func TestNewWorkSource(t *testing.T) {
+	conf := &config.Config{CollectorURL: "https://collector", CollectorToken: "token", WorkSourceCacheTTL: defaultWorkCacheTTL}
+
+	testCases := []struct {
+		conf   *config.Config
+		source string
+		result WorkSource
+	}{
+		{conf, "", collectorSource{url: "https://collector", token: "token", workspaceID: "T1"}},
+		{&config.Config{}, "", nil},
+		{nil, "", nil},
+		{conf, model.WorkSourceCollector, collectorSource{url: "https://other", token: "secret", workspaceID: "T1"}},
+		{conf, model.WorkSourceJira, jiraSource{url: "https://other", token: "secret"}},
+		{conf, model.WorkSourceGitLab, gitlabSource{url: "https://other", token: "secret"}},
+		{conf, model.WorkSourceGitHub, githubSource{url: "https://other", token: "secret"}},
+		{conf, model.WorkSourceToggl, togglSource{url: "https://other", token: "secret"}},
+		{&config.Config{GitReposDir: "/srv/repos", WorkSourceTimeout: time.Second, WorkSourceCacheTTL: defaultWorkCacheTTL}, model.WorkSourceGit, gitSource{dir: "/srv/repos/T1", timeout: time.Second}},
+	}
+
+	for _, tt := range testCases {
+		settings := model.Workspace{
+			WorkspaceID:     "T1",
+			WorkSource:      tt.source,
+			WorkSourceURL:   "https://other",
+			WorkSourceToken: "secret",
+		}
+		source := newWorkSource(tt.conf, settings)
+		if tt.result == nil {
+			assert.Nil(t, source, tt.source)
+			continue
+		}
+		cached, ok := source.(*cachedSource)
+		require.True(t, ok, tt.source)
+		assert.Equal(t, "T1", cached.workspaceID)
+		assert.Equal(t, defaultWorkCacheTTL, cached.ttl)
+		assert.Equal(t, tt.result, withoutClient(cached.source), tt.source)
+	}
+
+	conf.WorkSourceCacheTTL = 0
+	assert.Equal(t, collectorSource{url: "https://collector", token: "token", workspaceID: "T1"}, withoutClient(newWorkSource(conf, model.Workspace{WorkspaceID: "T1"})))
+}
+
+// withoutClient drops client of source so sources can be compared
+func withoutClient(source WorkSource) WorkSource {
+	switch s := source.(type) {
+	case collectorSource:
+		s.client = nil
+		return s
+	case jiraSource:
+		s.client = nil
+		return s
+	case gitlabSource:
+		s.client = nil
+		return s
+	case githubSource:
+		s.client = nil
+		return s
+	case togglSource:
+		s.client = nil
+		return s
+	}
+	return source
+}
+
+func TestCollectorSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Token secret", r.Header.Get("Authorization"))
+		switch r.URL.Path {
+		case "/rest/api/v1/logger/T1/users/U1/2019-05-01/2019-05-07/":
+			fmt.Fprint(w, `{"total_commits": 5, "worklogs": 3600}`)
+		case "/rest/api/v1/logger/T1/user-in-project/U1/project/2019-05-01/2019-05-07/":
+			fmt.Fprint(w, `{"total_commits": 2, "worklogs": 1800}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	source := collectorSource{url: server.URL, token: "secret", workspaceID: "T1", client: newWorkClient(nil)}
+	member := model.Standuper{UserID: "U1"}
+
+	data, err := source.Work(member, workFrom, workTo)
+	require.NoError(t, err)
+	assert.Equal(t, WorkData{Commits: 5, Worklogs: 3600}, data)
+
+	data, err = source.ProjectWork(member, model.Project{ChannelName: "project"}, workFrom, workTo)
+	require.NoError(t, err)
+	assert.Equal(t, WorkData{Commits: 2, Worklogs: 1800}, data)
+
+	_, err = source.ProjectWork(member, model.Project{ChannelName: "other"}, workFrom, workTo)
+	assert.Error(t, err)
+}
+
+func TestJiraSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "me@example.com", user)
+		assert.Equal(t, "apitoken", password)
+
+		switch r.URL.Path {
+		case "/rest/api/2/search":
+			jql := r.URL.Query().Get("jql")
+			assert.Contains(t, jql, `worklogAuthor = "acc1"`)
+			assert.Contains(t, jql, `worklogDate >= "2019-05-01" AND worklogDate <= "2019-05-07"`)
+			if r.URL.Query().Get("startAt") == "0" {
+				fmt.Fprint(w, `{"total": 2, "issues": [{"key": "PRJ-1", "fields": {"worklog": {"total": 3, "worklogs": [
+					{"author": {"accountId": "acc1"}, "started": "2019-05-02T10:00:00.000+0000", "timeSpentSeconds": 100}
+				]}}}]}`)
+				return
+			}
+			fmt.Fprint(w, `{"total": 2, "issues": [{"key": "PRJ-2", "fields": {"worklog": {"total": 2, "worklogs": [
+				{"author": {"accountId": "acc1"}, "started": "2019-05-07T23:00:00.000+0000", "timeSpentSeconds": 200},
+				{"author": {"accountId": "acc2"}, "started": "2019-05-03T10:00:00.000+0000", "timeSpentSeconds": 400}
+			]}}}]}`)
+		case "/rest/api/2/issue/PRJ-1/worklog":
+			fmt.Fprint(w, `{"total": 3, "worklogs": [
+				{"author": {"accountId": "acc1"}, "started": "2019-05-02T10:00:00.000+0000", "timeSpentSeconds": 100},
+				{"author": {"accountId": "acc1"}, "started": "2019-04-30T10:00:00.000+0000", "timeSpentSeconds": 800},
+				{"author": {"accountId": "acc1"}, "started": "2019-05-01T00:00:00.000+0000", "timeSpentSeconds": 1000}
+			]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	source := jiraSource{url: server.URL, token: "me@example.com:apitoken", client: newWorkClient(nil)}
+
+	data, err := source.Work(model.Standuper{UserID: "U1", WorkAccount: "acc1"}, workFrom, workTo)
+	require.NoError(t, err)
+	assert.Equal(t, WorkData{Worklogs: 1300}, data)
+
+	_, err = source.Work(model.Standuper{UserID: "U1"}, workFrom, workTo)
+	assert.Error(t, err)
+
+	_, err = source.ProjectWork(model.Standuper{UserID: "U1", WorkAccount: "acc1"}, model.Project{}, workFrom, workTo)
+	assert.Error(t, err)
+
+	assert.Equal(t, `"a \"b\" \\"`, jqlString(`a "b" \`))
+}
+
+func TestGitLabSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secret", r.Header.Get("PRIVATE-TOKEN"))
+		assert.Equal(t, "pushed", r.URL.Query().Get("action"))
+		assert.Equal(t, "2019-04-30", r.URL.Query().Get("after"))
+		assert.Equal(t, "2019-05-08", r.URL.Query().Get("before"))
+
+		switch r.URL.EscapedPath() {
+		case "/api/v4/users/jdoe/events":
+			if r.URL.Query().Get("page") == "1" {
+				w.Header().Set("X-Next-Page", "2")
+				fmt.Fprint(w, `[{"author_username": "jdoe", "push_data": {"commit_count": 3}}]`)
+				return
+			}
+			fmt.Fprint(w, `[{"author_username": "jdoe", "push_data": {"commit_count": 4}}]`)
+		case "/api/v4/projects/group%2Fproject/events":
+			fmt.Fprint(w, `[
+				{"author_username": "jdoe", "push_data": {"commit_count": 2}},
+				{"author_username": "other", "push_data": {"commit_count": 9}}
+			]`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	source := gitlabSource{url: server.URL, token: "secret", client: newWorkClient(nil)}
+	member := model.Standuper{UserID: "U1", WorkAccount: "jdoe"}
+
+	data, err := source.Work(member, workFrom, workTo)
+	require.NoError(t, err)
+	assert.Equal(t, WorkData{Commits: 7}, data)
+
+	data, err = source.ProjectWork(member, model.Project{WorkProject: "group/project"}, workFrom, workTo)
+	require.NoError(t, err)
+	assert.Equal(t, WorkData{Commits: 2}, data)
+}
+
+func TestGitHubSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/search/commits", r.URL.Path)
+		assert.Equal(t, "token secret", r.Header.Get("Authorization"))
+		switch r.URL.Query().Get("q") {
+		case "author:octocat author-date:2019-05-01..2019-05-07":
+			fmt.Fprint(w, `{"total_count": 12}`)
+		case "author:octocat author-date:2019-05-01..2019-05-07 repo:owner/repo":
+			fmt.Fprint(w, `{"total_count": 4}`)
+		default:
+			w.WriteHeader(http.StatusUnprocessableEntity)
+		}
+	}))
+	defer server.Close()
+
+	source := githubSource{url: server.URL, token: "secret", client: newWorkClient(nil)}
+	member := model.Standuper{UserID: "U1", WorkAccount: "octocat"}
+
+	data, err := source.Work(member, workFrom, workTo)
+	require.NoError(t, err)
+	assert.Equal(t, WorkData{Commits: 12}, data)
+
+	data, err = source.ProjectWork(member, model.Project{WorkProject: "owner/repo"}, workFrom, workTo)
+	require.NoError(t, err)
+	assert.Equal(t, WorkData{Commits: 4}, data)
+}
+
+func TestTogglSource(t *testing.T) {
+	requests := []togglSummaryRequest{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/reports/api/v3/workspace/42/summary/time_entries", r.URL.Path)
+		user, password, _ := r.BasicAuth()
+		assert.Equal(t, "secret", user)
+		assert.Equal(t, "api_token", password)
+
+		body, _ := ioutil.ReadAll(r.Body)
+		var request togglSummaryRequest
+		require.NoError(t, json.Unmarshal(body, &request))
+		requests = append(requests, request)
+
+		fmt.Fprint(w, `{"groups": [{"sub_groups": [{"seconds": 600}, {"seconds": 300}]}, {"sub_groups": [{"seconds": 100}]}]}`)
+	}))
+	defer server.Close()
+
+	source := togglSource{url: server.URL + "/reports/api/v3/workspace/42", token: "secret", client: newWorkClient(nil)}
+	member := model.Standuper{UserID: "U1", WorkAccount: "7"}
+
+	data, err := source.Work(member, workFrom, workTo)
+	require.NoError(t, err)
+	assert.Equal(t, WorkData{Worklogs: 1000}, data)
+
+	_, err = source.ProjectWork(member, model.Project{WorkProject: "15"}, workFrom, workTo)
+	require.NoError(t, err)
+
+	require.Equal(t, 2, len(requests))
+	assert.Equal(t, togglSummaryRequest{StartDate: "2019-05-01", EndDate: "2019-05-07", UserIDs: []int{7}}, requests[0])
+	assert.Equal(t, []int{15}, requests[1].ProjectIDs)
+
+	_, err = source.Work(model.Standuper{UserID: "U1", WorkAccount: "jdoe"}, workFrom, workTo)
+	assert.Error(t, err)
+}
+
+func TestMemberWork(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("q") == "author:octocat author-date:2019-05-01..2019-05-07 repo:owner/repo" {
+			fmt.Fprint(w, `{"total_count": 4}`)
+			return
+		}
+		fmt.Fprint(w, `{"total_count": 10}`)
+	}))
+	defer server.Close()
+
+	realWork := bot.work
+	defer func() { bot.work = realWork }()
+
+	bot.work = nil
+	_, _, err := bot.MemberWork(model.Standuper{UserID: "U1", ChannelID: "CHAN123"}, workFrom, workTo)
+	assert.Equal(t, errNoWorkSource, err)
+	assert.False(t, bot.tracksCommits())
+
+	project, err := bot.db.SelectProject("CHAN123")
+	require.NoError(t, err)
+	project.WorkProject = "owner/repo"
+	_, err = bot.db.UpdateProject(project)
+	require.NoError(t, err)
+	defer func() {
+		project.WorkProject = ""
+		bot.db.UpdateProject(project)
+	}()
+
+	standuper, err := bot.db.CreateStanduper(model.Standuper{
+		WorkspaceID: "testTeam",
+		UserID:      "OCTOCAT",
+		ChannelID:   "CHAN123",
+		WorkAccount: "octocat",
+	})
+	require.NoError(t, err)
+	defer bot.db.DeleteStanduper(standuper.ID)
+
+	bot.work = githubSource{url: server.URL, client: newWorkClient(nil)}
+	assert.True(t, bot.tracksCommits())
+	assert.False(t, bot.tracksWorklogs())
+
+	user, userInProject, err := bot.MemberWork(standuper, workFrom, workTo)
+	require.NoError(t, err)
+	assert.Equal(t, 10, user.Commits)
+	assert.Equal(t, 4, userInProject.Commits)
+
+	user, err = bot.UserWork("OCTOCAT", workFrom, workTo)
+	require.NoError(t, err)
+	assert.Equal(t, 10, user.Commits)
+}
+
+func TestGitSource(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir, err := ioutil.TempDir("", "comedian")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	commit := func(repo, email, date string) {
+		cmd := exec.Command("git", "-C", filepath.Join(dir, "T1", repo), "commit", "--allow-empty", "-q", "-m", "work")
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Dev", "GIT_AUTHOR_EMAIL="+email, "GIT_AUTHOR_DATE="+date+"T12:00:00",
+			"GIT_COMMITTER_NAME=Dev", "GIT_COMMITTER_EMAIL="+email, "GIT_COMMITTER_DATE="+date+"T12:00:00")
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	for _, repo := range []string{"api", "group/web"} {
+		out, err := exec.Command("git", "init", "-q", filepath.Join(dir, "T1", repo)).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	commit("api", "dev@example.com", "2019-04-30")
+	commit("api", "dev@example.com", "2019-05-02")
+	commit("api", "Dev@Personal.com", "2019-05-07")
+	commit("api", "other@example.com", "2019-05-03")
+	commit("group/web", "dev@example.com", "2019-05-05")
+	commit("group/web", "dev@example.com", "2019-05-08")
+
+	source := newGitSource(&config.Config{GitReposDir: dir}, "T1")
+	member := model.Standuper{UserID: "U1", WorkAccount: "dev@example.com, dev@personal.com"}
+
+	data, err := source.Work(member, workFrom, workTo)
+	require.NoError(t, err)
+	assert.Equal(t, WorkData{Commits: 3}, data)
+
+	data, err = source.ProjectWork(member, model.Project{WorkProject: "api"}, workFrom, workTo)
+	require.NoError(t, err)
+	assert.Equal(t, WorkData{Commits: 2}, data)
+
+	data, err = source.ProjectWork(member, model.Project{WorkProject: "group/web/"}, workFrom, workFrom)
+	require.NoError(t, err)
+	assert.Equal(t, WorkData{Commits: 0}, data)
+
+	_, err = source.ProjectWork(member, model.Project{ChannelName: "web", WorkProject: "../T2/web"}, workFrom, workTo)
+	assert.EqualError(t, err, "work project of web is outside of git repositories directory")
+	_, err = source.ProjectWork(member, model.Project{ChannelName: "web", WorkProject: "missing"}, workFrom, workTo)
+	assert.Error(t, err)
+	_, err = source.Work(model.Standuper{UserID: "U1"}, workFrom, workTo)
+	assert.EqualError(t, err, "work account of U1 is not set")
+
+	_, err = newGitSource(nil, "T1").Work(member, workFrom, workTo)
+	assert.Equal(t, errNoGitReposDir, err)
+}
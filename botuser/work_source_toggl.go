@@ -0,0 +1,101 @@
+package botuser
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/maddevsio/comedian/model"
+)
+
+// togglSource sums time entries of Toggl Track. URL is reports API of Toggl
+// workspace, https://api.track.toggl.com/reports/api/v3/workspace/<workspace id>,
+// account of member is Toggl user ID and project is Toggl project ID
+type togglSource struct {
+	url    string
+	token  string
+	client *workClient
+}
+
+type togglSummaryRequest struct {
+	StartDate  string `json:"start_date"`
+	EndDate    string `json:"end_date"`
+	UserIDs    []int  `json:"user_ids"`
+	ProjectIDs []int  `json:"project_ids,omitempty"`
+}
+
+type togglSummary struct {
+	Groups []struct {
+		SubGroups []struct {
+			Seconds int `json:"seconds"`
+		} `json:"sub_groups"`
+	} `json:"groups"`
+}
+
+func (s togglSource) Work(member model.Standuper, from, to time.Time) (WorkData, error) {
+	return s.timeEntries(member, "", from, to)
+}
+
+func (s togglSource) ProjectWork(member model.Standuper, project model.Project, from, to time.Time) (WorkData, error) {
+	projectID, err := workProject(project)
+	if err != nil {
+		return WorkData{}, err
+	}
+	return s.timeEntries(member, projectID, from, to)
+}
+
+func (s togglSource) TracksCommits() bool  { return false }
+func (s togglSource) TracksWorklogs() bool { return true }
+
+func (s togglSource) timeEntries(member model.Standuper, projectID string, from, to time.Time) (WorkData, error) {
+	user, err := account(member)
+	if err != nil {
+		return WorkData{}, err
+	}
+	userID, err := strconv.Atoi(user)
+	if err != nil {
+		return WorkData{}, fmt.Errorf("Toggl user ID must be a number: %v", user)
+	}
+
+	summary := togglSummaryRequest{
+		StartDate: from.Format(workDateFormat),
+		EndDate:   to.Format(workDateFormat),
+		UserIDs:   []int{userID},
+	}
+	if projectID != "" {
+		id, err := strconv.Atoi(projectID)
+		if err != nil {
+			return WorkData{}, fmt.Errorf("Toggl project ID must be a number: %v", projectID)
+		}
+		summary.ProjectIDs = []int{id}
+	}
+
+	body, err := json.Marshal(summary)
+	if err != nil {
+		return WorkData{}, err
+	}
+	req, err := http.NewRequest("POST", strings.TrimSuffix(s.url, "/")+"/summary/time_entries", bytes.NewReader(body))
+	if err != nil {
+		return WorkData{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.SetBasicAuth(s.token, "api_token")
+
+	var result togglSummary
+	_, err = s.client.fetchJSON(req, &result)
+	if err != nil {
+		return WorkData{}, err
+	}
+
+	data := WorkData{}
+	for _, group := range result.Groups {
+		for _, sub := range group.SubGroups {
+			data.Worklogs += sub.Seconds
+		}
+	}
+	return data, nil
+}
@@ -0,0 +1,113 @@
+// Package calendar reads holiday calendars in iCalendar (.ics) format
+package calendar
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+	"time"
+)
+
+const dateFormat = "2006-01-02"
+
+// Event is a VEVENT of iCalendar file. End is exclusive as in RFC 5545
+type Event struct {
+	Summary string
+	Start   time.Time
+	End     time.Time
+}
+
+// Dates returns every day covered by the event in YYYY-MM-DD format
+func (e Event) Dates() []string {
+	dates := []string{}
+	end := e.End
+	if !end.After(e.Start) {
+		end = e.Start.AddDate(0, 0, 1)
+	}
+	for d := e.Start; d.Before(end); d = d.AddDate(0, 0, 1) {
+		dates = append(dates, d.Format(dateFormat))
+	}
+	return dates
+}
+
+// ParseICS returns events of iCalendar file. Only DTSTART, DTEND and SUMMARY
+// properties are taken into account, recurring rules are ignored
+func ParseICS(r io.Reader) ([]Event, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	events := []Event{}
+	var event *Event
+
+	for _, line := range lines {
+		name, value := splitProperty(line)
+		switch {
+		case name == "BEGIN" && value == "VEVENT":
+			event = &Event{}
+		case name == "END" && value == "VEVENT":
+			if event == nil || event.Start.IsZero() {
+				return nil, errors.New("calendar event without start date")
+			}
+			events = append(events, *event)
+			event = nil
+		case event == nil:
+			continue
+		case name == "SUMMARY":
+			event.Summary = unescape(value)
+		case name == "DTSTART":
+			event.Start, err = parseDate(value)
+			if err != nil {
+				return nil, err
+			}
+		case name == "DTEND":
+			event.End, err = parseDate(value)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return events, nil
+}
+
+// unfold joins lines continued with leading whitespace
+func unfold(r io.Reader) ([]string, error) {
+	lines := []string{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+// splitProperty returns property name without parameters and its value
+func splitProperty(line string) (string, string) {
+	i := strings.Index(line, ":")
+	if i < 0 {
+		return strings.ToUpper(line), ""
+	}
+	name := line[:i]
+	if j := strings.Index(name, ";"); j >= 0 {
+		name = name[:j]
+	}
+	return strings.ToUpper(name), line[i+1:]
+}
+
+func parseDate(value string) (time.Time, error) {
+	if len(value) < 8 {
+		return time.Time{}, errors.New("incorrect calendar date: " + value)
+	}
+	return time.Parse("20060102", value[:8])
+}
+
+func unescape(value string) string {
+	return strings.NewReplacer(`\,`, ",", `\;`, ";", `\n`, " ", `\N`, " ", `\\`, `\`).Replace(value)
+}
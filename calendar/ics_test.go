@@ -0,0 +1,41 @@
+package calendar
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const holidaysICS = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART;VALUE=DATE:20260101\r\n" +
+	"DTEND;VALUE=DATE:20260103\r\n" +
+	"SUMMARY:New Year\\, day off\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART:20260308T000000Z\r\n" +
+	"SUMMARY:International Women's\r\n" +
+	"  Day\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestParseICS(t *testing.T) {
+	events, err := ParseICS(strings.NewReader(holidaysICS))
+	require.NoError(t, err)
+	require.Equal(t, 2, len(events))
+
+	assert.Equal(t, "New Year, day off", events[0].Summary)
+	assert.Equal(t, []string{"2026-01-01", "2026-01-02"}, events[0].Dates())
+
+	assert.Equal(t, "International Women's Day", events[1].Summary)
+	assert.Equal(t, []string{"2026-03-08"}, events[1].Dates())
+
+	_, err = ParseICS(strings.NewReader("BEGIN:VEVENT\nDTSTART:2026\nEND:VEVENT\n"))
+	assert.Error(t, err)
+
+	_, err = ParseICS(strings.NewReader("BEGIN:VEVENT\nSUMMARY:No date\nEND:VEVENT\n"))
+	assert.Error(t, err)
+}
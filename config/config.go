@@ -1,20 +1,42 @@
 package config
 
 import (
+	"time"
+
 	"github.com/kelseyhightower/envconfig"
 )
 
 // Config struct used for configuration of app with env variables
 type Config struct {
-	DatabaseURL            string `envconfig:"DATABASE" required:"false" default:"comedian:comedian@/comedian?parseTime=true"`
-	CollectorURL           string `envconfig:"COLLECTOR_URL" required:"false" default:""`
-	CollectorToken         string `envconfig:"COLLECTOR_TOKEN" required:"false" default:""`
-	HTTPBindAddr           string `envconfig:"HTTP_BIND_ADDR" required:"false" default:"0.0.0.0:8080"`
-	SlackClientID          string `envconfig:"SLACK_CLIENT_ID" required:"false"`
-	SlackClientSecret      string `envconfig:"SLACK_CLIENT_SECRET" required:"false"`
-	SlackVerificationToken string `envconfig:"SLACK_VERIFICATION_TOKEN" required:"false"`
-	UIurl                  string `envconfig:"UI_URL" required:"false"`
-	NotificationTime       int64  `envconfig:"NOTIFICATION_TIME" default:"1"`
+	DatabaseURL            string        `envconfig:"DATABASE" required:"false" default:"comedian:comedian@/comedian?parseTime=true"`
+	CollectorURL           string        `envconfig:"COLLECTOR_URL" required:"false" default:""`
+	CollectorToken         string        `envconfig:"COLLECTOR_TOKEN" required:"false" default:""`
+	HTTPBindAddr           string        `envconfig:"HTTP_BIND_ADDR" required:"false" default:"0.0.0.0:8080"`
+	SlackClientID          string        `envconfig:"SLACK_CLIENT_ID" required:"false"`
+	SlackClientSecret      string        `envconfig:"SLACK_CLIENT_SECRET" required:"false"`
+	SlackVerificationToken string        `envconfig:"SLACK_VERIFICATION_TOKEN" required:"false"`
+	SlackSigningSecret     string        `envconfig:"SLACK_SIGNING_SECRET" required:"false"`
+	SlackSignatureSkew     time.Duration `envconfig:"SLACK_SIGNATURE_SKEW" default:"5m"`
+	UIurl                  string        `envconfig:"UI_URL" required:"false"`
+	JWTSecret              string        `envconfig:"JWT_SECRET" required:"false"`
+	SessionTTL             time.Duration `envconfig:"SESSION_TTL" default:"12h"`
+	Admins                 string        `envconfig:"ADMINS" required:"false"`
+	EncryptionKeys         string        `envconfig:"ENCRYPTION_KEYS" required:"false"`
+	WebhookTimeout         time.Duration `envconfig:"WEBHOOK_TIMEOUT" default:"10s"`
+	WorkSourceTimeout      time.Duration `envconfig:"WORK_SOURCE_TIMEOUT" default:"10s"`
+	WorkSourceRetries      int           `envconfig:"WORK_SOURCE_RETRIES" default:"2"`
+	WorkSourceCacheTTL     time.Duration `envconfig:"WORK_SOURCE_CACHE_TTL" default:"10m"`
+	WorkSourceConcurrency  int           `envconfig:"WORK_SOURCE_CONCURRENCY" default:"4"`
+	GitReposDir            string        `envconfig:"GIT_REPOS_DIR" required:"false"`
+	NotificationTime       int64         `envconfig:"NOTIFICATION_TIME" default:"1"`
+	MigrateOnStart         bool          `envconfig:"MIGRATE_ON_START" default:"true"`
+	ReplicaID              string        `envconfig:"REPLICA_ID" required:"false"`
+	MattermostURL          string        `envconfig:"MATTERMOST_URL" required:"false"`
+	MattermostTeamID       string        `envconfig:"MATTERMOST_TEAM_ID" required:"false"`
+	MattermostBotToken     string        `envconfig:"MATTERMOST_BOT_TOKEN" required:"false"`
+	MattermostTokens       string        `envconfig:"MATTERMOST_TOKENS" required:"false"`
+	TelegramAPIURL         string        `envconfig:"TELEGRAM_API_URL" required:"false" default:"https://api.telegram.org"`
+	TelegramBotToken       string        `envconfig:"TELEGRAM_BOT_TOKEN" required:"false"`
 }
 
 // Get method processes env variables and fills Config struct
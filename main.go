@@ -1,9 +1,12 @@
 package main
 
 import (
+	"os"
+
 	"github.com/BurntSushi/toml"
 	"github.com/maddevsio/comedian/api"
 	"github.com/maddevsio/comedian/config"
+	"github.com/maddevsio/comedian/secret"
 	"github.com/maddevsio/comedian/storage"
 	"github.com/nicksnyder/go-i18n/v2/i18n"
 	log "github.com/sirupsen/logrus"
@@ -17,11 +20,35 @@ func main() {
 		log.Fatal("Failed to get config : ", err)
 	}
 
-	db, err := storage.New(cnf.DatabaseURL, "migrations")
+	keyring, err := secret.NewKeyring(cnf.EncryptionKeys)
+	if err != nil {
+		log.Fatal("Failed to parse encryption keys: ", err)
+	}
+	if keyring == nil {
+		log.Warning("ENCRYPTION_KEYS is not set, bot access tokens are stored as plaintext")
+	}
+
+	db, err := storage.Open(cnf.DatabaseURL, keyring)
 	if err != nil {
 		log.Fatal("Failed to connect to db: ", err)
 	}
 
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if len(os.Args) < 3 {
+			log.Fatal("Usage: comedian migrate up|down|status|to VERSION|encrypt|decrypt")
+		}
+		if err = db.Migrate(os.Args[2], os.Args[3:]...); err != nil {
+			log.Fatal("Failed to migrate: ", err)
+		}
+		return
+	}
+
+	if cnf.MigrateOnStart {
+		if err = db.Migrate("up"); err != nil {
+			log.Fatal("Failed to migrate: ", err)
+		}
+	}
+
 	bundle := i18n.NewBundle(language.English)
 	bundle.RegisterUnmarshalFunc("toml", toml.Unmarshal)
 	bundle.MustLoadMessageFile("active.en.toml")
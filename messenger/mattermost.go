@@ -0,0 +1,234 @@
+package messenger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nlopes/slack"
+)
+
+const mattermostPageSize = 200
+
+// Mattermost sends messages through Mattermost REST API v4 on behalf of a bot account
+type Mattermost struct {
+	url    string
+	token  string
+	teamID string
+	client *http.Client
+
+	mu sync.Mutex
+	me User
+}
+
+type mattermostUser struct {
+	ID        string `json:"id"`
+	Username  string `json:"username"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+}
+
+type mattermostPost struct {
+	ChannelID string                 `json:"channel_id"`
+	Message   string                 `json:"message"`
+	Props     map[string]interface{} `json:"props,omitempty"`
+}
+
+// NewMattermost creates Mattermost messenger of the team on the server at serverURL
+// authorized with bot access token
+func NewMattermost(serverURL, token, teamID string) *Mattermost {
+	return &Mattermost{
+		url:    strings.TrimRight(serverURL, "/"),
+		token:  token,
+		teamID: teamID,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// SendMessage posts a message in a specified channel visible for everyone
+func (m *Mattermost) SendMessage(channelID, text string, attachments []slack.Attachment) error {
+	_, err := m.PostMessage(channelID, text, attachments)
+	return err
+}
+
+// PostMessage posts a message in a specified channel and returns ID of the post
+func (m *Mattermost) PostMessage(channelID, text string, attachments []slack.Attachment) (string, error) {
+	post := mattermostPost{ChannelID: channelID, Message: text}
+	if len(attachments) > 0 {
+		post.Props = map[string]interface{}{"attachments": attachments}
+	}
+	var created struct {
+		ID string `json:"id"`
+	}
+	err := m.do(http.MethodPost, "/posts", post, &created)
+	return created.ID, err
+}
+
+// SendEphemeralMessage posts a message in a specified channel which is visible only for selected user
+func (m *Mattermost) SendEphemeralMessage(channelID, userID, text string) error {
+	return m.do(http.MethodPost, "/posts/ephemeral", map[string]interface{}{
+		"user_id": userID,
+		"post":    mattermostPost{ChannelID: channelID, Message: text},
+	}, nil)
+}
+
+// SendUserMessage sends direct message to user
+func (m *Mattermost) SendUserMessage(userID, text string) error {
+	me, err := m.Me()
+	if err != nil {
+		return err
+	}
+
+	var channel struct {
+		ID string `json:"id"`
+	}
+	err = m.do(http.MethodPost, "/channels/direct", []string{me.ID, userID}, &channel)
+	if err != nil {
+		return err
+	}
+	return m.SendMessage(channel.ID, text, nil)
+}
+
+// AddReaction reacts to post with the given ID
+func (m *Mattermost) AddReaction(channelID, messageID, reaction string) error {
+	me, err := m.Me()
+	if err != nil {
+		return err
+	}
+	return m.do(http.MethodPost, "/reactions", map[string]string{
+		"user_id":    me.ID,
+		"post_id":    messageID,
+		"emoji_name": reaction,
+	}, nil)
+}
+
+// GetUser returns user of the server
+func (m *Mattermost) GetUser(userID string) (User, error) {
+	var u mattermostUser
+	err := m.do(http.MethodGet, "/users/"+url.PathEscape(userID), nil, &u)
+	if err != nil {
+		return User{}, err
+	}
+	return m.user(u), nil
+}
+
+// GetChannel returns channel of the server
+func (m *Mattermost) GetChannel(channelID string) (Channel, error) {
+	var ch struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+	err := m.do(http.MethodGet, "/channels/"+url.PathEscape(channelID), nil, &ch)
+	if err != nil {
+		return Channel{}, err
+	}
+	return Channel{ID: ch.ID, Name: ch.Name}, nil
+}
+
+// ListUsers returns all members of the team
+func (m *Mattermost) ListUsers() ([]User, error) {
+	result := []User{}
+	for page := 0; ; page++ {
+		users := []mattermostUser{}
+		path := fmt.Sprintf("/users?in_team=%s&page=%d&per_page=%d", url.QueryEscape(m.teamID), page, mattermostPageSize)
+		err := m.do(http.MethodGet, path, nil, &users)
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range users {
+			result = append(result, m.user(u))
+		}
+		if len(users) < mattermostPageSize {
+			return result, nil
+		}
+	}
+}
+
+// Me returns the bot account messages are sent from
+func (m *Mattermost) Me() (User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.me.ID != "" {
+		return m.me, nil
+	}
+
+	var u mattermostUser
+	err := m.do(http.MethodGet, "/users/me", nil, &u)
+	if err != nil {
+		return User{}, err
+	}
+	m.me = m.user(u)
+	return m.me, nil
+}
+
+// TeamName returns display name of the team
+func (m *Mattermost) TeamName() (string, error) {
+	var team struct {
+		Name        string `json:"name"`
+		DisplayName string `json:"display_name"`
+	}
+	err := m.do(http.MethodGet, "/teams/"+url.PathEscape(m.teamID), nil, &team)
+	if err != nil {
+		return "", err
+	}
+	if team.DisplayName != "" {
+		return team.DisplayName, nil
+	}
+	return team.Name, nil
+}
+
+func (m *Mattermost) user(u mattermostUser) User {
+	realName := strings.TrimSpace(u.FirstName + " " + u.LastName)
+	if realName == "" {
+		realName = u.Username
+	}
+	return User{
+		ID:       u.ID,
+		TeamID:   m.teamID,
+		Name:     u.Username,
+		RealName: realName,
+	}
+}
+
+// do sends request to API v4 and decodes response into result if it is not nil
+func (m *Mattermost) do(method, path string, body, result interface{}) error {
+	var payload bytes.Buffer
+	if body != nil {
+		err := json.NewEncoder(&payload).Encode(body)
+		if err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequest(method, m.url+"/api/v4"+path, &payload)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+m.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		json.NewDecoder(resp.Body).Decode(&apiErr)
+		return fmt.Errorf("mattermost %s %s: %s %s", method, path, resp.Status, apiErr.Message)
+	}
+
+	if result == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
@@ -0,0 +1,118 @@
+package messenger
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nlopes/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type request struct {
+	method string
+	path   string
+	body   map[string]interface{}
+}
+
+func mattermostServer(t *testing.T, requests *[]request) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+
+		body := map[string]interface{}{}
+		json.NewDecoder(r.Body).Decode(&body)
+		*requests = append(*requests, request{r.Method, r.URL.Path, body})
+
+		switch r.URL.Path {
+		case "/api/v4/users/me":
+			fmt.Fprint(w, `{"id":"bot1","username":"comedian"}`)
+		case "/api/v4/users/user1":
+			fmt.Fprint(w, `{"id":"user1","username":"john","first_name":"John","last_name":"Doe"}`)
+		case "/api/v4/users/missing":
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message":"Unable to find the user."}`)
+		case "/api/v4/users":
+			if r.URL.Query().Get("page") == "0" {
+				fmt.Fprint(w, `[{"id":"user1","username":"john"},{"id":"user2","username":"jane"}]`)
+				return
+			}
+			fmt.Fprint(w, `[]`)
+		case "/api/v4/channels/chan1":
+			fmt.Fprint(w, `{"id":"chan1","name":"backend","display_name":"Backend"}`)
+		case "/api/v4/channels/direct":
+			fmt.Fprint(w, `{"id":"dm1"}`)
+		case "/api/v4/teams/team1":
+			fmt.Fprint(w, `{"id":"team1","name":"mad","display_name":"Mad Devs"}`)
+		default:
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{}`)
+		}
+	}))
+}
+
+func TestMattermostMessages(t *testing.T) {
+	requests := []request{}
+	server := mattermostServer(t, &requests)
+	defer server.Close()
+
+	m := NewMattermost(server.URL+"/", "token", "team1")
+
+	err := m.SendMessage("chan1", "hello", []slack.Attachment{{Text: "report"}})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(requests))
+	assert.Equal(t, "/api/v4/posts", requests[0].path)
+	assert.Equal(t, "chan1", requests[0].body["channel_id"])
+	assert.Equal(t, "hello", requests[0].body["message"])
+	assert.NotNil(t, requests[0].body["props"])
+
+	err = m.SendEphemeralMessage("chan1", "user1", "only you")
+	require.NoError(t, err)
+	assert.Equal(t, "/api/v4/posts/ephemeral", requests[1].path)
+	assert.Equal(t, "user1", requests[1].body["user_id"])
+
+	err = m.SendUserMessage("user1", "direct")
+	require.NoError(t, err)
+	assert.Equal(t, "/api/v4/users/me", requests[2].path)
+	assert.Equal(t, "/api/v4/channels/direct", requests[3].path)
+	assert.Equal(t, "/api/v4/posts", requests[4].path)
+	assert.Equal(t, "dm1", requests[4].body["channel_id"])
+
+	err = m.AddReaction("chan1", "post1", "heavy_check_mark")
+	require.NoError(t, err)
+	// bot account is requested once
+	assert.Equal(t, "/api/v4/reactions", requests[5].path)
+	assert.Equal(t, "bot1", requests[5].body["user_id"])
+	assert.Equal(t, "post1", requests[5].body["post_id"])
+}
+
+func TestMattermostUsersAndChannels(t *testing.T) {
+	requests := []request{}
+	server := mattermostServer(t, &requests)
+	defer server.Close()
+
+	m := NewMattermost(server.URL, "token", "team1")
+
+	user, err := m.GetUser("user1")
+	require.NoError(t, err)
+	assert.Equal(t, User{ID: "user1", TeamID: "team1", Name: "john", RealName: "John Doe"}, user)
+
+	_, err = m.GetUser("missing")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Unable to find the user.")
+
+	channel, err := m.GetChannel("chan1")
+	require.NoError(t, err)
+	assert.Equal(t, Channel{ID: "chan1", Name: "backend"}, channel)
+
+	users, err := m.ListUsers()
+	require.NoError(t, err)
+	require.Equal(t, 2, len(users))
+	assert.Equal(t, "jane", users[1].RealName)
+
+	name, err := m.TeamName()
+	require.NoError(t, err)
+	assert.Equal(t, "Mad Devs", name)
+}
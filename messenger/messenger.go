@@ -0,0 +1,38 @@
+// Package messenger hides chat platforms Comedian works with behind one interface
+package messenger
+
+import (
+	"github.com/nlopes/slack"
+)
+
+// Messenger is a chat platform bot sends messages to and reads users and channels from.
+// Attachments follow Slack format, Mattermost understands it as well
+type Messenger interface {
+	SendMessage(channelID, text string, attachments []slack.Attachment) error
+	PostMessage(channelID, text string, attachments []slack.Attachment) (string, error)
+	SendEphemeralMessage(channelID, userID, text string) error
+	SendUserMessage(userID, text string) error
+	AddReaction(channelID, messageID, reaction string) error
+	GetUser(userID string) (User, error)
+	GetChannel(channelID string) (Channel, error)
+	ListUsers() ([]User, error)
+}
+
+// ViewOpener is a messenger that can show modal views to users
+type ViewOpener interface {
+	OpenView(triggerID string, view interface{}) error
+}
+
+// User is a member of chat workspace
+type User struct {
+	ID       string
+	TeamID   string
+	Name     string
+	RealName string
+}
+
+// Channel is a chat channel
+type Channel struct {
+	ID   string
+	Name string
+}
@@ -0,0 +1,144 @@
+package messenger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/nlopes/slack"
+)
+
+const slackAPIURL = "https://slack.com/api/"
+
+// Slack sends messages through Slack Web API
+type Slack struct {
+	client *slack.Client
+	token  string
+	apiURL string
+	http   *http.Client
+}
+
+// NewSlack creates Slack messenger authorized with bot access token
+func NewSlack(token string) *Slack {
+	return &Slack{
+		client: slack.New(token),
+		token:  token,
+		apiURL: slackAPIURL,
+		http:   &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// SendMessage posts a message in a specified channel visible for everyone
+func (s *Slack) SendMessage(channelID, text string, attachments []slack.Attachment) error {
+	_, err := s.PostMessage(channelID, text, attachments)
+	return err
+}
+
+// PostMessage posts a message in a specified channel and returns its timestamp
+func (s *Slack) PostMessage(channelID, text string, attachments []slack.Attachment) (string, error) {
+	_, ts, err := s.client.PostMessage(channelID, text, slack.PostMessageParameters{Attachments: attachments})
+	return ts, err
+}
+
+// SendEphemeralMessage posts a message in a specified channel which is visible only for selected user
+func (s *Slack) SendEphemeralMessage(channelID, userID, text string) error {
+	_, err := s.client.PostEphemeral(channelID, userID, slack.MsgOptionText(text, true))
+	return err
+}
+
+// SendUserMessage sends direct message to user
+func (s *Slack) SendUserMessage(userID, text string) error {
+	_, _, channelID, err := s.client.OpenIMChannel(userID)
+	if err != nil {
+		return err
+	}
+	return s.SendMessage(channelID, text, nil)
+}
+
+// AddReaction reacts to message with the given timestamp
+func (s *Slack) AddReaction(channelID, messageID, reaction string) error {
+	return s.client.AddReaction(reaction, slack.ItemRef{
+		Channel:   channelID,
+		Timestamp: messageID,
+	})
+}
+
+// GetUser returns user of the workspace
+func (s *Slack) GetUser(userID string) (User, error) {
+	u, err := s.client.GetUserInfo(userID)
+	if err != nil {
+		return User{}, err
+	}
+	return slackUser(*u), nil
+}
+
+// GetChannel returns channel of the workspace
+func (s *Slack) GetChannel(channelID string) (Channel, error) {
+	ch, err := s.client.GetConversationInfo(channelID, true)
+	if err != nil {
+		return Channel{}, err
+	}
+	return Channel{ID: ch.ID, Name: ch.Name}, nil
+}
+
+// ListUsers returns users of all workspaces bot has access to
+func (s *Slack) ListUsers() ([]User, error) {
+	users, err := s.client.GetUsers()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]User, 0, len(users))
+	for _, u := range users {
+		result = append(result, slackUser(u))
+	}
+	return result, nil
+}
+
+// OpenView opens modal view in response to user action identified by trigger ID.
+// Slack client of the project does not support views, so Web API is called directly
+func (s *Slack) OpenView(triggerID string, view interface{}) error {
+	payload, err := json.Marshal(map[string]interface{}{
+		"trigger_id": triggerID,
+		"view":       view,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.apiURL+"views.open", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+s.token)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+	resp, err := s.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var response struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	if err != nil {
+		return fmt.Errorf("slack views.open: %s: %v", resp.Status, err)
+	}
+	if !response.OK {
+		return fmt.Errorf("slack views.open: %s", response.Error)
+	}
+	return nil
+}
+
+func slackUser(u slack.User) User {
+	return User{
+		ID:       u.ID,
+		TeamID:   u.TeamID,
+		Name:     u.Name,
+		RealName: u.RealName,
+	}
+}
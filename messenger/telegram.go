@@ -0,0 +1,275 @@
+package messenger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nlopes/slack"
+)
+
+// telegramMessageLimit is the longest text Telegram accepts in one message
+const telegramMessageLimit = 4096
+
+// telegramReactions maps reactions used by Comedian to emoji Telegram allows in reactions
+var telegramReactions = map[string]string{
+	"heavy_check_mark": "👍",
+}
+
+// Telegram sends messages through Telegram Bot API
+type Telegram struct {
+	url    string
+	client *http.Client
+
+	mu sync.Mutex
+	me TelegramUser
+}
+
+// TelegramUpdate is an incoming update of Bot API
+type TelegramUpdate struct {
+	UpdateID      int64            `json:"update_id"`
+	Message       *TelegramMessage `json:"message"`
+	EditedMessage *TelegramMessage `json:"edited_message"`
+}
+
+// TelegramMessage is a message of Telegram chat
+type TelegramMessage struct {
+	MessageID int64         `json:"message_id"`
+	From      *TelegramUser `json:"from"`
+	Chat      TelegramChat  `json:"chat"`
+	Text      string        `json:"text"`
+}
+
+// TelegramUser is a Telegram user or bot
+type TelegramUser struct {
+	ID        int64  `json:"id"`
+	IsBot     bool   `json:"is_bot"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Username  string `json:"username"`
+}
+
+// TelegramChat is a private chat, group or channel
+type TelegramChat struct {
+	ID        int64  `json:"id"`
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Username  string `json:"username"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+}
+
+// IsGroup returns true for group and supergroup chats
+func (c TelegramChat) IsGroup() bool {
+	return c.Type == "group" || c.Type == "supergroup"
+}
+
+// NewTelegram creates Telegram messenger of the bot with token using Bot API server at apiURL
+func NewTelegram(apiURL, token string) *Telegram {
+	return &Telegram{
+		url:    strings.TrimRight(apiURL, "/") + "/bot" + token,
+		client: &http.Client{Timeout: time.Minute},
+	}
+}
+
+// SendMessage posts a message in the chat. Telegram has no attachments,
+// so they are appended to the text
+func (t *Telegram) SendMessage(channelID, text string, attachments []slack.Attachment) error {
+	_, err := t.PostMessage(channelID, text, attachments)
+	return err
+}
+
+// PostMessage posts a message in the chat and returns its ID prefixed with chat ID and colon,
+// since Telegram counts messages per chat. Long text is split, ID of the first message is returned
+func (t *Telegram) PostMessage(channelID, text string, attachments []slack.Attachment) (string, error) {
+	messageID := ""
+	for _, chunk := range splitText(telegramText(text, attachments), telegramMessageLimit) {
+		var msg TelegramMessage
+		err := t.call("sendMessage", map[string]interface{}{
+			"chat_id": channelID,
+			"text":    chunk,
+		}, &msg)
+		if err != nil {
+			return messageID, err
+		}
+		if messageID == "" {
+			messageID = fmt.Sprintf("%s:%d", channelID, msg.MessageID)
+		}
+	}
+	return messageID, nil
+}
+
+// SendEphemeralMessage sends message to user privately since Telegram groups
+// have no messages visible for one member only. If user never started chat with
+// the bot, the message is posted in the group
+func (t *Telegram) SendEphemeralMessage(channelID, userID, text string) error {
+	err := t.SendUserMessage(userID, text)
+	if err == nil {
+		return nil
+	}
+	return t.SendMessage(channelID, text, nil)
+}
+
+// SendUserMessage sends message to private chat with user
+func (t *Telegram) SendUserMessage(userID, text string) error {
+	return t.SendMessage(userID, text, nil)
+}
+
+// AddReaction reacts to message. Message ID may be prefixed with chat ID and colon
+func (t *Telegram) AddReaction(channelID, messageID, reaction string) error {
+	id, err := strconv.ParseInt(messageID[strings.LastIndex(messageID, ":")+1:], 10, 64)
+	if err != nil {
+		return err
+	}
+
+	emoji, ok := telegramReactions[reaction]
+	if !ok {
+		return fmt.Errorf("reaction %v is not supported by telegram", reaction)
+	}
+
+	return t.call("setMessageReaction", map[string]interface{}{
+		"chat_id":    channelID,
+		"message_id": id,
+		"reaction":   []map[string]string{{"type": "emoji", "emoji": emoji}},
+	}, nil)
+}
+
+// GetUser returns user who started private chat with the bot
+func (t *Telegram) GetUser(userID string) (User, error) {
+	var chat TelegramChat
+	err := t.call("getChat", map[string]interface{}{"chat_id": userID}, &chat)
+	if err != nil {
+		return User{}, err
+	}
+	return User{
+		ID:       userID,
+		Name:     chat.Username,
+		RealName: strings.TrimSpace(chat.FirstName + " " + chat.LastName),
+	}, nil
+}
+
+// GetChannel returns group chat
+func (t *Telegram) GetChannel(channelID string) (Channel, error) {
+	var chat TelegramChat
+	err := t.call("getChat", map[string]interface{}{"chat_id": channelID}, &chat)
+	if err != nil {
+		return Channel{}, err
+	}
+	return Channel{ID: channelID, Name: chat.Title}, nil
+}
+
+// ListUsers returns nothing since Bot API does not list users known to bot
+func (t *Telegram) ListUsers() ([]User, error) {
+	return []User{}, nil
+}
+
+// Me returns the bot messages are sent from
+func (t *Telegram) Me() (TelegramUser, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.me.ID != 0 {
+		return t.me, nil
+	}
+
+	var me TelegramUser
+	err := t.call("getMe", nil, &me)
+	if err != nil {
+		return me, err
+	}
+	t.me = me
+	return t.me, nil
+}
+
+// Updates waits up to timeout seconds for updates starting from offset.
+// Updates before offset are confirmed and not returned again
+func (t *Telegram) Updates(offset int64, timeout int) ([]TelegramUpdate, error) {
+	updates := []TelegramUpdate{}
+	err := t.call("getUpdates", map[string]interface{}{
+		"offset":          offset,
+		"timeout":         timeout,
+		"allowed_updates": []string{"message", "edited_message"},
+	}, &updates)
+	return updates, err
+}
+
+// call invokes Bot API method and decodes its result into result if it is not nil
+func (t *Telegram) call(method string, params, result interface{}) error {
+	var payload bytes.Buffer
+	if params != nil {
+		err := json.NewEncoder(&payload).Encode(params)
+		if err != nil {
+			return err
+		}
+	}
+
+	resp, err := t.client.Post(t.url+"/"+method, "application/json", &payload)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var response struct {
+		OK          bool            `json:"ok"`
+		Description string          `json:"description"`
+		Result      json.RawMessage `json:"result"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	if err != nil {
+		return fmt.Errorf("telegram %s: %s: %v", method, resp.Status, err)
+	}
+	if !response.OK {
+		return fmt.Errorf("telegram %s: %s", method, response.Description)
+	}
+
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(response.Result, result)
+}
+
+// telegramText renders attachments as plain text below the message
+func telegramText(text string, attachments []slack.Attachment) string {
+	lines := []string{}
+	if text != "" {
+		lines = append(lines, text)
+	}
+	for _, a := range attachments {
+		if a.Title != "" {
+			lines = append(lines, a.Title)
+		}
+		if a.Text != "" {
+			lines = append(lines, a.Text)
+		}
+		for _, f := range a.Fields {
+			lines = append(lines, strings.TrimSpace(f.Title+" "+f.Value))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// splitText splits text into chunks not longer than limit bytes, preferably on line breaks
+func splitText(text string, limit int) []string {
+	chunks := []string{}
+	for len(text) > limit {
+		cut := strings.LastIndex(text[:limit], "\n")
+		if cut <= 0 {
+			cut = limit
+			for cut > 0 && !utf8RuneStart(text[cut]) {
+				cut--
+			}
+		}
+		chunks = append(chunks, text[:cut])
+		text = strings.TrimPrefix(text[cut:], "\n")
+	}
+	return append(chunks, text)
+}
+
+func utf8RuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
@@ -0,0 +1,137 @@
+package messenger
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nlopes/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func telegramServer(t *testing.T, requests *[]request) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := map[string]interface{}{}
+		json.NewDecoder(r.Body).Decode(&body)
+		*requests = append(*requests, request{r.Method, r.URL.Path, body})
+
+		switch r.URL.Path {
+		case "/bottoken/getMe":
+			fmt.Fprint(w, `{"ok":true,"result":{"id":42,"is_bot":true,"first_name":"Comedian","username":"comedian_bot"}}`)
+		case "/bottoken/getChat":
+			switch body["chat_id"] {
+			case "-100":
+				fmt.Fprint(w, `{"ok":true,"result":{"id":-100,"type":"supergroup","title":"Backend"}}`)
+			case "7":
+				fmt.Fprint(w, `{"ok":true,"result":{"id":7,"type":"private","username":"john","first_name":"John","last_name":"Doe"}}`)
+			default:
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, `{"ok":false,"error_code":400,"description":"Bad Request: chat not found"}`)
+			}
+		case "/bottoken/getUpdates":
+			fmt.Fprint(w, `{"ok":true,"result":[{"update_id":5,"message":{"message_id":3,"from":{"id":7,"first_name":"John"},"chat":{"id":-100,"type":"supergroup","title":"Backend"},"text":"@comedian_bot yesterday"}}]}`)
+		case "/bottoken/sendMessage":
+			if body["chat_id"] == "8" {
+				w.WriteHeader(http.StatusForbidden)
+				fmt.Fprint(w, `{"ok":false,"error_code":403,"description":"Forbidden: bot can't initiate conversation with a user"}`)
+				return
+			}
+			fmt.Fprint(w, `{"ok":true,"result":{}}`)
+		default:
+			fmt.Fprint(w, `{"ok":true,"result":true}`)
+		}
+	}))
+}
+
+func TestTelegramMessages(t *testing.T) {
+	requests := []request{}
+	server := telegramServer(t, &requests)
+	defer server.Close()
+
+	tg := NewTelegram(server.URL+"/", "token")
+
+	err := tg.SendMessage("-100", "hello", []slack.Attachment{{Text: "report", Fields: []slack.AttachmentField{{Title: "John", Value: "done"}}}})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(requests))
+	assert.Equal(t, "/bottoken/sendMessage", requests[0].path)
+	assert.Equal(t, "-100", requests[0].body["chat_id"])
+	assert.Equal(t, "hello\nreport\nJohn done", requests[0].body["text"])
+
+	err = tg.SendEphemeralMessage("-100", "7", "only you")
+	require.NoError(t, err)
+	require.Equal(t, 2, len(requests))
+	assert.Equal(t, "7", requests[1].body["chat_id"])
+
+	// user never started chat with the bot, so message goes to the group
+	err = tg.SendEphemeralMessage("-100", "8", "only you")
+	require.NoError(t, err)
+	require.Equal(t, 4, len(requests))
+	assert.Equal(t, "-100", requests[3].body["chat_id"])
+
+	err = tg.AddReaction("-100", "-100:3", "heavy_check_mark")
+	require.NoError(t, err)
+	assert.Equal(t, "/bottoken/setMessageReaction", requests[4].path)
+	assert.Equal(t, float64(3), requests[4].body["message_id"])
+
+	err = tg.AddReaction("-100", "3", "smile")
+	require.Error(t, err)
+}
+
+func TestTelegramChats(t *testing.T) {
+	requests := []request{}
+	server := telegramServer(t, &requests)
+	defer server.Close()
+
+	tg := NewTelegram(server.URL, "token")
+
+	me, err := tg.Me()
+	require.NoError(t, err)
+	assert.Equal(t, "comedian_bot", me.Username)
+	_, err = tg.Me()
+	require.NoError(t, err)
+	assert.Equal(t, 1, len(requests))
+
+	user, err := tg.GetUser("7")
+	require.NoError(t, err)
+	assert.Equal(t, User{ID: "7", Name: "john", RealName: "John Doe"}, user)
+
+	_, err = tg.GetUser("9")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "chat not found")
+
+	channel, err := tg.GetChannel("-100")
+	require.NoError(t, err)
+	assert.Equal(t, Channel{ID: "-100", Name: "Backend"}, channel)
+
+	updates, err := tg.Updates(5, 0)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(updates))
+	assert.Equal(t, int64(5), updates[0].UpdateID)
+	assert.True(t, updates[0].Message.Chat.IsGroup())
+	assert.Equal(t, float64(5), requests[len(requests)-1].body["offset"])
+}
+
+func TestSplitText(t *testing.T) {
+	testCases := []struct {
+		text   string
+		limit  int
+		chunks []string
+	}{
+		{"short", 10, []string{"short"}},
+		{"first line\nsecond line", 15, []string{"first line", "second line"}},
+		{"abcdefghij", 4, []string{"abcd", "efgh", "ij"}},
+		{"привет", 5, []string{"пр", "ив", "ет"}},
+	}
+	for _, tt := range testCases {
+		chunks := splitText(tt.text, tt.limit)
+		assert.Equal(t, tt.chunks, chunks)
+		for _, c := range chunks {
+			assert.True(t, len(c) <= tt.limit, c)
+		}
+	}
+	assert.Equal(t, 2, len(splitText(strings.Repeat("a", telegramMessageLimit+1), telegramMessageLimit)))
+}
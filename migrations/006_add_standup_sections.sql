@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `standups`
+    ADD `done` TEXT COLLATE utf8mb4_unicode_ci NOT NULL,
+    ADD `planned` TEXT COLLATE utf8mb4_unicode_ci NOT NULL,
+    ADD `blockers` TEXT COLLATE utf8mb4_unicode_ci NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `standups`
+    DROP COLUMN `done`,
+    DROP COLUMN `planned`,
+    DROP COLUMN `blockers`;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `blockers` (
+    `id` INTEGER NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `created_at` INTEGER NOT NULL,
+    `workspace_id` VARCHAR(255) NOT NULL,
+    `channel_id` VARCHAR(255) NOT NULL,
+    `user_id` VARCHAR(255) NOT NULL,
+    `standup_id` INTEGER NOT NULL,
+    `text` TEXT COLLATE utf8mb4_unicode_ci NOT NULL,
+    `resolved` BOOLEAN NOT NULL DEFAULT FALSE,
+    `resolved_at` INTEGER NOT NULL DEFAULT 0,
+    `resolved_by` VARCHAR(255) NOT NULL DEFAULT ''
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `blockers`;
+-- +goose StatementEnd
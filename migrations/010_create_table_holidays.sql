@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `holidays` (
+    `id` INTEGER NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `created_at` INTEGER NOT NULL,
+    `workspace_id` VARCHAR(255) NOT NULL,
+    `channel_id` VARCHAR(255) NOT NULL,
+    `date` VARCHAR(10) NOT NULL,
+    `name` VARCHAR(255) COLLATE utf8mb4_unicode_ci NOT NULL
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `holidays`;
+-- +goose StatementEnd
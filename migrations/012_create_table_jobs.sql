@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `jobs` (
+    `id` INTEGER NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `workspace_id` VARCHAR(255) NOT NULL,
+    `name` VARCHAR(255) NOT NULL,
+    `last_run` BIGINT NOT NULL,
+    UNIQUE KEY `workspace_job` (`workspace_id`, `name`)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `jobs`;
+-- +goose StatementEnd
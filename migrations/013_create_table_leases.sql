@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `leases` (
+    `id` INTEGER NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `name` VARCHAR(255) NOT NULL,
+    `owner` VARCHAR(255) NOT NULL,
+    `expires_at` BIGINT NOT NULL,
+    UNIQUE KEY `lease_name` (`name`)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `leases`;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `projects`
+    ADD `dm_mode` BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD `dm_prompt_offset` INTEGER NOT NULL DEFAULT 30;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `projects`
+    DROP COLUMN `dm_mode`,
+    DROP COLUMN `dm_prompt_offset`;
+-- +goose StatementEnd
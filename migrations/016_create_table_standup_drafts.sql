@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `standup_drafts` (
+    `id` INTEGER NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `created_at` BIGINT NOT NULL,
+    `workspace_id` VARCHAR(255) NOT NULL,
+    `channel_id` VARCHAR(255) NOT NULL,
+    `user_id` VARCHAR(255) NOT NULL,
+    `section` VARCHAR(255) NOT NULL,
+    `done` TEXT COLLATE utf8mb4_unicode_ci NOT NULL,
+    `planned` TEXT COLLATE utf8mb4_unicode_ci NOT NULL,
+    `blockers` TEXT COLLATE utf8mb4_unicode_ci NOT NULL,
+    UNIQUE KEY `user_channel` (`user_id`, `channel_id`)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `standup_drafts`;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `admins` (
+    `id` INTEGER NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `created_at` BIGINT NOT NULL,
+    `workspace_id` VARCHAR(255) NOT NULL,
+    `user_id` VARCHAR(255) NOT NULL,
+    UNIQUE KEY `workspace_user` (`workspace_id`, `user_id`)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `admins`;
+-- +goose StatementEnd
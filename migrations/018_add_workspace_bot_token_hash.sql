@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `workspaces`
+    MODIFY `bot_access_token` TEXT NOT NULL,
+    ADD `bot_token_hash` VARCHAR(64) NOT NULL DEFAULT '',
+    ADD INDEX `bot_token_hash` (`bot_token_hash`);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- tokens must be decrypted with 'comedian migrate decrypt' before rolling back
+ALTER TABLE `workspaces`
+    DROP INDEX `bot_token_hash`,
+    DROP COLUMN `bot_token_hash`,
+    MODIFY `bot_access_token` VARCHAR(255) NOT NULL;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `webhooks` (
+    `id` INTEGER NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `created_at` BIGINT NOT NULL,
+    `workspace_id` VARCHAR(255) NOT NULL,
+    `url` TEXT NOT NULL,
+    `secret` TEXT NOT NULL,
+    `events` VARCHAR(255) NOT NULL DEFAULT '',
+    `enabled` BOOLEAN NOT NULL DEFAULT TRUE,
+    INDEX `workspace_id` (`workspace_id`)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `webhooks`;
+-- +goose StatementEnd
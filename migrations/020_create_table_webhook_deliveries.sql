@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `webhook_deliveries` (
+    `id` INTEGER NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `created_at` BIGINT NOT NULL,
+    `workspace_id` VARCHAR(255) NOT NULL,
+    `webhook_id` INTEGER NOT NULL,
+    `event` VARCHAR(255) NOT NULL,
+    `payload` TEXT COLLATE utf8mb4_unicode_ci NOT NULL,
+    `status` VARCHAR(255) NOT NULL,
+    `attempts` INTEGER NOT NULL DEFAULT 0,
+    `next_attempt_at` BIGINT NOT NULL DEFAULT 0,
+    `last_attempt_at` BIGINT NOT NULL DEFAULT 0,
+    `response_code` INTEGER NOT NULL DEFAULT 0,
+    `error` TEXT COLLATE utf8mb4_unicode_ci NOT NULL,
+    INDEX `due` (`workspace_id`, `status`, `next_attempt_at`),
+    INDEX `webhook_id` (`webhook_id`)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `webhook_deliveries`;
+-- +goose StatementEnd
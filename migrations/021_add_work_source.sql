@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `workspaces`
+    ADD `work_source` VARCHAR(255) NOT NULL DEFAULT '',
+    ADD `work_source_url` VARCHAR(255) NOT NULL DEFAULT '',
+    ADD `work_source_token` TEXT NOT NULL;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE `projects`
+    ADD `work_project` VARCHAR(255) NOT NULL DEFAULT '';
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE `standupers`
+    ADD `work_account` VARCHAR(255) NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `standupers`
+    DROP COLUMN `work_account`;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE `projects`
+    DROP COLUMN `work_project`;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE `workspaces`
+    DROP COLUMN `work_source`,
+    DROP COLUMN `work_source_url`,
+    DROP COLUMN `work_source_token`;
+-- +goose StatementEnd
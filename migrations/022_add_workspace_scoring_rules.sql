@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `workspaces`
+    ADD `scoring_rules` TEXT NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `workspaces`
+    DROP COLUMN `scoring_rules`;
+-- +goose StatementEnd
@@ -0,0 +1,48 @@
+// Package migrations keeps database migrations compiled into the binary
+package migrations
+
+import (
+	"embed"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+)
+
+// FS holds MySQL migrations in the root and PostgreSQL ones in postgres directory
+//go:embed *.sql postgres/*.sql
+var FS embed.FS
+
+// Extract writes migrations for the dialect into a new temporary directory
+// so goose can read them from disk. Caller is responsible for removing the directory
+func Extract(dialect string) (string, error) {
+	src := "."
+	if dialect == "postgres" {
+		src = "postgres"
+	}
+
+	files, err := FS.ReadDir(src)
+	if err != nil {
+		return "", err
+	}
+
+	dir, err := ioutil.TempDir("", "comedian-migrations")
+	if err != nil {
+		return "", err
+	}
+
+	for _, f := range files {
+		if f.IsDir() || filepath.Ext(f.Name()) != ".sql" {
+			continue
+		}
+		data, err := FS.ReadFile(path.Join(src, f.Name()))
+		if err != nil {
+			return dir, err
+		}
+		err = ioutil.WriteFile(filepath.Join(dir, f.Name()), data, 0644)
+		if err != nil {
+			return dir, err
+		}
+	}
+
+	return dir, nil
+}
@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtract(t *testing.T) {
+	names := map[string][]string{}
+
+	for _, dialect := range []string{"mysql", "postgres"} {
+		dir, err := Extract(dialect)
+		require.NoError(t, err)
+
+		files, err := ioutil.ReadDir(dir)
+		require.NoError(t, err)
+
+		for _, f := range files {
+			assert.False(t, f.IsDir())
+			names[dialect] = append(names[dialect], f.Name())
+		}
+
+		assert.NoError(t, os.RemoveAll(dir))
+	}
+
+	assert.NotEmpty(t, names["mysql"])
+	assert.Equal(t, names["mysql"], names["postgres"], "every migration must exist for both dialects")
+}
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE standups (
+    id SERIAL PRIMARY KEY,
+    created_at BIGINT NOT NULL,
+    workspace_id VARCHAR(255) NOT NULL,
+    channel_id VARCHAR(255) NOT NULL,
+    user_id VARCHAR(255) NOT NULL,
+    comment VARCHAR(255) NOT NULL,
+    message_ts VARCHAR(255) NOT NULL
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE standups;
+-- +goose StatementEnd
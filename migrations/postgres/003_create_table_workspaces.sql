@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE workspaces (
+    id SERIAL PRIMARY KEY,
+    created_at BIGINT NOT NULL,
+    notifier_interval INTEGER NOT NULL,
+    max_reminders INTEGER NOT NULL,
+    reminder_offset INTEGER NOT NULL,
+    workspace_id VARCHAR(255) NOT NULL,
+    workspace_name VARCHAR(255) NOT NULL,
+    bot_access_token VARCHAR(255) NOT NULL,
+    bot_user_id VARCHAR(255) NOT NULL,
+    projects_reports_enabled BOOLEAN NOT NULL,
+    reporting_channel VARCHAR(255) NOT NULL,
+    reporting_time VARCHAR(255) NOT NULL,
+    language VARCHAR(255) NOT NULL
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE workspaces;
+-- +goose StatementEnd
+
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE projects (
+    id SERIAL PRIMARY KEY,
+    created_at BIGINT NOT NULL,
+    workspace_id VARCHAR(255) NOT NULL,
+    channel_id VARCHAR(255) NOT NULL,
+    channel_name VARCHAR(255) NOT NULL,
+    deadline VARCHAR(255) NOT NULL,
+    tz VARCHAR(255) NOT NULL,
+    submission_days VARCHAR(255) NOT NULL,
+    onbording_message TEXT NOT NULL
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE projects;
+-- +goose StatementEnd
\ No newline at end of file
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE standups
+    ADD COLUMN done TEXT NOT NULL DEFAULT '',
+    ADD COLUMN planned TEXT NOT NULL DEFAULT '',
+    ADD COLUMN blockers TEXT NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE standups
+    DROP COLUMN done,
+    DROP COLUMN planned,
+    DROP COLUMN blockers;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE projects
+    ADD COLUMN yesterday_keywords VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN today_keywords VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN problem_keywords VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN optional_sections VARCHAR(255) NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE projects
+    DROP COLUMN yesterday_keywords,
+    DROP COLUMN today_keywords,
+    DROP COLUMN problem_keywords,
+    DROP COLUMN optional_sections;
+-- +goose StatementEnd
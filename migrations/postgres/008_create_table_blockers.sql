@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE blockers (
+    id SERIAL PRIMARY KEY,
+    created_at BIGINT NOT NULL,
+    workspace_id VARCHAR(255) NOT NULL,
+    channel_id VARCHAR(255) NOT NULL,
+    user_id VARCHAR(255) NOT NULL,
+    standup_id INTEGER NOT NULL,
+    text TEXT NOT NULL,
+    resolved BOOLEAN NOT NULL DEFAULT FALSE,
+    resolved_at BIGINT NOT NULL DEFAULT 0,
+    resolved_by VARCHAR(255) NOT NULL DEFAULT ''
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE blockers;
+-- +goose StatementEnd
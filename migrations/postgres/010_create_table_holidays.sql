@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE holidays (
+    id SERIAL PRIMARY KEY,
+    created_at BIGINT NOT NULL,
+    workspace_id VARCHAR(255) NOT NULL,
+    channel_id VARCHAR(255) NOT NULL,
+    date VARCHAR(10) NOT NULL,
+    name VARCHAR(255) NOT NULL
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE holidays;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE standupers
+    ADD COLUMN tz VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN deadline VARCHAR(255) NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE standupers
+    DROP COLUMN tz,
+    DROP COLUMN deadline;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE workspaces
+    ADD COLUMN platform VARCHAR(255) NOT NULL DEFAULT 'slack';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE workspaces
+    DROP COLUMN platform;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE standup_drafts (
+    id SERIAL PRIMARY KEY,
+    created_at BIGINT NOT NULL,
+    workspace_id VARCHAR(255) NOT NULL,
+    channel_id VARCHAR(255) NOT NULL,
+    user_id VARCHAR(255) NOT NULL,
+    section VARCHAR(255) NOT NULL,
+    done TEXT NOT NULL,
+    planned TEXT NOT NULL,
+    blockers TEXT NOT NULL,
+    UNIQUE (user_id, channel_id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE standup_drafts;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE workspaces
+    ALTER COLUMN bot_access_token TYPE TEXT,
+    ADD COLUMN bot_token_hash VARCHAR(64) NOT NULL DEFAULT '';
+CREATE INDEX workspaces_bot_token_hash ON workspaces (bot_token_hash);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- tokens must be decrypted with 'comedian migrate decrypt' before rolling back
+DROP INDEX workspaces_bot_token_hash;
+ALTER TABLE workspaces
+    DROP COLUMN bot_token_hash,
+    ALTER COLUMN bot_access_token TYPE VARCHAR(255);
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE workspaces
+    ADD COLUMN work_source VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN work_source_url VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN work_source_token TEXT NOT NULL DEFAULT '';
+ALTER TABLE projects
+    ADD COLUMN work_project VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE standupers
+    ADD COLUMN work_account VARCHAR(255) NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE standupers
+    DROP COLUMN work_account;
+ALTER TABLE projects
+    DROP COLUMN work_project;
+ALTER TABLE workspaces
+    DROP COLUMN work_source,
+    DROP COLUMN work_source_url,
+    DROP COLUMN work_source_token;
+-- +goose StatementEnd
@@ -1,7 +1,11 @@
 package model
 
 import (
+	"database/sql/driver"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"net/url"
 	"strings"
 	"time"
 
@@ -16,22 +20,42 @@ type Standup struct {
 	ChannelID   string `db:"channel_id" json:"channel_id"`
 	UserID      string `db:"user_id" json:"user_id"`
 	Comment     string `db:"comment" json:"comment"`
+	Done        string `db:"done" json:"done"`
+	Planned     string `db:"planned" json:"planned"`
+	Blockers    string `db:"blockers" json:"blockers"`
 	MessageTS   string `db:"message_ts" json:"message_ts"`
 }
 
 // Project model used for serialization/deserialization stored Projects
 type Project struct {
-	ID               int64  `db:"id" json:"id"`
-	CreatedAt        int64  `db:"created_at" json:"created_at"`
-	WorkspaceID      string `db:"workspace_id" json:"workspace_id"`
-	ChannelName      string `db:"channel_name" json:"channel_name"`
-	ChannelID        string `db:"channel_id" json:"channel_id"`
-	Deadline         string `db:"deadline" json:"deadline"`
-	TZ               string `db:"tz" json:"tz"`
-	OnbordingMessage string `db:"onbording_message" json:"onbording_message,omitempty"`
-	SubmissionDays   string `db:"submission_days" json:"submission_days,omitempty"`
+	ID                int64  `db:"id" json:"id"`
+	CreatedAt         int64  `db:"created_at" json:"created_at"`
+	WorkspaceID       string `db:"workspace_id" json:"workspace_id"`
+	ChannelName       string `db:"channel_name" json:"channel_name"`
+	ChannelID         string `db:"channel_id" json:"channel_id"`
+	Deadline          string `db:"deadline" json:"deadline"`
+	TZ                string `db:"tz" json:"tz"`
+	OnbordingMessage  string `db:"onbording_message" json:"onbording_message,omitempty"`
+	SubmissionDays    string `db:"submission_days" json:"submission_days,omitempty"`
+	YesterdayKeywords string `db:"yesterday_keywords" json:"yesterday_keywords"`
+	TodayKeywords     string `db:"today_keywords" json:"today_keywords"`
+	ProblemKeywords   string `db:"problem_keywords" json:"problem_keywords"`
+	OptionalSections  string `db:"optional_sections" json:"optional_sections"`
+	DMMode            bool   `db:"dm_mode" json:"dm_mode"`
+	DMPromptOffset    int64  `db:"dm_prompt_offset" json:"dm_prompt_offset"`
+	WorkProject       string `db:"work_project" json:"work_project"`
 }
 
+// Standup sections that can be configured per project
+const (
+	SectionYesterday = "yesterday"
+	SectionToday     = "today"
+	SectionProblems  = "problems"
+)
+
+// StandupSections lists standup sections in the order they are checked
+var StandupSections = []string{SectionYesterday, SectionToday, SectionProblems}
+
 // Standuper model used for serialization/deserialization stored ChannelMembers
 type Standuper struct {
 	ID          int64  `db:"id" json:"id"`
@@ -42,23 +66,282 @@ type Standuper struct {
 	Role        string `db:"role" json:"role"`
 	RealName    string `db:"real_name" json:"real_name"`
 	ChannelName string `db:"channel_name" json:"channel_name"`
+	TZ          string `db:"tz" json:"tz"`
+	Deadline    string `db:"deadline" json:"deadline"`
+	WorkAccount string `db:"work_account" json:"work_account"`
+}
+
+// Roles of users. Workspace admins are stored in admins table,
+// PMs are standupers of the project with pm role, everybody else is a member
+const (
+	RoleAdmin  = "admin"
+	RolePM     = "pm"
+	RoleMember = "member"
+)
+
+// Admin is a user allowed to manage the workspace and settings of all its projects
+type Admin struct {
+	ID          int64  `db:"id" json:"id"`
+	CreatedAt   int64  `db:"created_at" json:"created_at"`
+	WorkspaceID string `db:"workspace_id" json:"workspace_id"`
+	UserID      string `db:"user_id" json:"user_id"`
+}
+
+// Standup lifecycle events sent to webhooks
+const (
+	EventStandupCreated = "standup.created"
+	EventStandupUpdated = "standup.updated"
+	EventStandupDeleted = "standup.deleted"
+	EventStandupMissed  = "standup.missed"
+)
+
+// WebhookEvents lists events webhooks can subscribe to
+var WebhookEvents = []string{EventStandupCreated, EventStandupUpdated, EventStandupDeleted, EventStandupMissed}
+
+// Webhook is a subscription of external service to events of the workspace.
+// Events are comma separated, empty list subscribes to all events
+type Webhook struct {
+	ID          int64  `db:"id" json:"id"`
+	CreatedAt   int64  `db:"created_at" json:"created_at"`
+	WorkspaceID string `db:"workspace_id" json:"workspace_id"`
+	URL         string `db:"url" json:"url"`
+	Secret      string `db:"secret" json:"secret,omitempty"`
+	Events      string `db:"events" json:"events"`
+	Enabled     bool   `db:"enabled" json:"enabled"`
+}
+
+// Statuses of webhook deliveries
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryFailed    = "failed"
+)
+
+// WebhookDelivery is an event queued for the webhook. Pending deliveries are retried
+// until NextAttemptAt passes, delivered and failed ones are kept as delivery log
+type WebhookDelivery struct {
+	ID            int64  `db:"id" json:"id"`
+	CreatedAt     int64  `db:"created_at" json:"created_at"`
+	WorkspaceID   string `db:"workspace_id" json:"workspace_id"`
+	WebhookID     int64  `db:"webhook_id" json:"webhook_id"`
+	Event         string `db:"event" json:"event"`
+	Payload       string `db:"payload" json:"payload"`
+	Status        string `db:"status" json:"status"`
+	Attempts      int    `db:"attempts" json:"attempts"`
+	NextAttemptAt int64  `db:"next_attempt_at" json:"next_attempt_at"`
+	LastAttemptAt int64  `db:"last_attempt_at" json:"last_attempt_at"`
+	ResponseCode  int    `db:"response_code" json:"response_code"`
+	Error         string `db:"error" json:"error"`
 }
 
 // Workspace is used for updating and storing different bot configuration parameters
 type Workspace struct {
-	ID                     int64  `db:"id" json:"id"`
-	CreatedAt              int64  `db:"created_at" json:"created_at"`
-	BotUserID              string `db:"bot_user_id" json:"bot_user_id"`
-	NotifierInterval       int    `db:"notifier_interval" json:"notifier_interval" `
-	Language               string `db:"language" json:"language" `
-	MaxReminders           int    `db:"max_reminders" json:"max_reminders" `
-	ReminderOffset         int64  `db:"reminder_offset" json:"reminder_offset" `
-	BotAccessToken         string `db:"bot_access_token" json:"bot_access_token" `
-	WorkspaceID            string `db:"workspace_id" json:"workspace_id" `
-	WorkspaceName          string `db:"workspace_name" json:"workspace_name" `
-	ReportingChannel       string `db:"reporting_channel" json:"reporting_channel"`
-	ReportingTime          string `db:"reporting_time" json:"reporting_time"`
-	ProjectsReportsEnabled bool   `db:"projects_reports_enabled" json:"projects_reports_enabled"`
+	ID                     int64        `db:"id" json:"id"`
+	CreatedAt              int64        `db:"created_at" json:"created_at"`
+	BotUserID              string       `db:"bot_user_id" json:"bot_user_id"`
+	NotifierInterval       int          `db:"notifier_interval" json:"notifier_interval" `
+	Language               string       `db:"language" json:"language" `
+	MaxReminders           int          `db:"max_reminders" json:"max_reminders" `
+	ReminderOffset         int64        `db:"reminder_offset" json:"reminder_offset" `
+	BotAccessToken         string       `db:"bot_access_token" json:"-"`
+	BotTokenHash           string       `db:"bot_token_hash" json:"-"`
+	WorkspaceID            string       `db:"workspace_id" json:"workspace_id" `
+	WorkspaceName          string       `db:"workspace_name" json:"workspace_name" `
+	ReportingChannel       string       `db:"reporting_channel" json:"reporting_channel"`
+	ReportingTime          string       `db:"reporting_time" json:"reporting_time"`
+	ProjectsReportsEnabled bool         `db:"projects_reports_enabled" json:"projects_reports_enabled"`
+	Platform               string       `db:"platform" json:"platform"`
+	WorkSource             string       `db:"work_source" json:"work_source"`
+	WorkSourceURL          string       `db:"work_source_url" json:"work_source_url"`
+	WorkSourceToken        string       `db:"work_source_token" json:"-"`
+	ScoringRules           ScoringRules `db:"scoring_rules" json:"scoring_rules"`
+}
+
+// Chat platforms Comedian works with
+const (
+	PlatformSlack      = "slack"
+	PlatformMattermost = "mattermost"
+	PlatformTelegram   = "telegram"
+)
+
+// Work sources providing commits and logged time of standupers for reports
+const (
+	WorkSourceCollector = "collector"
+	WorkSourceJira      = "jira"
+	WorkSourceGitLab    = "gitlab"
+	WorkSourceGitHub    = "github"
+	WorkSourceToggl     = "toggl"
+	WorkSourceGit       = "git"
+)
+
+// ScoreLevel grades a part of work in reports. Work of at least Min hours of
+// worklogs or Min commits is marked with Emoji, Good levels count as work done
+type ScoreLevel struct {
+	Min   float64 `json:"min"`
+	Emoji string  `json:"emoji"`
+	Good  bool    `json:"good"`
+}
+
+// ScoringRule tells reports how to grade work of standupers with Role, rule
+// with empty role is used for everybody else. Levels, holiday hours and colors
+// not set are taken from the default rule
+type ScoringRule struct {
+	Role           string       `json:"role"`
+	DailyWorklogs  []ScoreLevel `json:"daily_worklogs,omitempty"`
+	WeeklyWorklogs []ScoreLevel `json:"weekly_worklogs,omitempty"`
+	Commits        []ScoreLevel `json:"commits,omitempty"`
+	HolidayHours   float64      `json:"holiday_hours,omitempty"`
+	SkipWorklogs   bool         `json:"skip_worklogs"`
+	SkipCommits    bool         `json:"skip_commits"`
+	BadColor       string       `json:"bad_color,omitempty"`
+	WarningColor   string       `json:"warning_color,omitempty"`
+	GoodColor      string       `json:"good_color,omitempty"`
+}
+
+// ScoringRules of a workspace, they are stored as JSON
+type ScoringRules []ScoringRule
+
+// DefaultScoringRules are used by workspaces without scoring rules
+var DefaultScoringRules = ScoringRules{
+	{
+		DailyWorklogs: []ScoreLevel{
+			{Min: 0, Emoji: ":angry:"},
+			{Min: 3, Emoji: ":disappointed:"},
+			{Min: 7, Emoji: ":wink:", Good: true},
+			{Min: 9, Emoji: ":sunglasses:", Good: true},
+		},
+		WeeklyWorklogs: []ScoreLevel{
+			{Min: 0, Emoji: ":disappointed:"},
+			{Min: 31, Emoji: ":wink:", Good: true},
+			{Min: 35, Emoji: ":sunglasses:", Good: true},
+		},
+		Commits: []ScoreLevel{
+			{Min: 0, Emoji: ":shit:"},
+			{Min: 1, Emoji: ":wink:", Good: true},
+		},
+		HolidayHours: 7,
+		BadColor:     "danger",
+		WarningColor: "warning",
+		GoodColor:    "good",
+	},
+	{Role: "pm", SkipCommits: true},
+	{Role: "designer", SkipCommits: true},
+}
+
+// Rule returns scoring rule for standupers with role. Rule of the role is
+// looked up first, then rule without role, empty fields are filled from the default rule
+func (rules ScoringRules) Rule(role string) ScoringRule {
+	if len(rules) == 0 {
+		rules = DefaultScoringRules
+	}
+
+	var rule ScoringRule
+	found := false
+	for _, r := range rules {
+		if r.Role == role {
+			rule, found = r, true
+			break
+		}
+	}
+	if !found {
+		for _, r := range rules {
+			if r.Role == "" {
+				rule = r
+				break
+			}
+		}
+	}
+
+	defaults := DefaultScoringRules[0]
+	if len(rule.DailyWorklogs) == 0 {
+		rule.DailyWorklogs = defaults.DailyWorklogs
+	}
+	if len(rule.WeeklyWorklogs) == 0 {
+		rule.WeeklyWorklogs = defaults.WeeklyWorklogs
+	}
+	if len(rule.Commits) == 0 {
+		rule.Commits = defaults.Commits
+	}
+	if rule.HolidayHours == 0 {
+		rule.HolidayHours = defaults.HolidayHours
+	}
+	if rule.BadColor == "" {
+		rule.BadColor = defaults.BadColor
+	}
+	if rule.WarningColor == "" {
+		rule.WarningColor = defaults.WarningColor
+	}
+	if rule.GoodColor == "" {
+		rule.GoodColor = defaults.GoodColor
+	}
+	return rule
+}
+
+// Grade returns the highest level value reaches, value below all levels gets empty level
+func Grade(levels []ScoreLevel, value float64) ScoreLevel {
+	grade := ScoreLevel{}
+	for _, level := range levels {
+		if value >= level.Min {
+			grade = level
+		}
+	}
+	return grade
+}
+
+// Validate validates scoring rules
+func (rules ScoringRules) Validate() error {
+	roles := map[string]bool{}
+	for _, rule := range rules {
+		if roles[rule.Role] {
+			return fmt.Errorf("scoring rules have several rules for role '%s'", rule.Role)
+		}
+		roles[rule.Role] = true
+
+		for _, levels := range [][]ScoreLevel{rule.DailyWorklogs, rule.WeeklyWorklogs, rule.Commits} {
+			for i, level := range levels {
+				if level.Min < 0 || (i > 0 && level.Min <= levels[i-1].Min) {
+					return errors.New("score levels must start from zero or more and grow")
+				}
+			}
+		}
+
+		if rule.HolidayHours < 0 {
+			return errors.New("holiday hours cannot be negative")
+		}
+	}
+	return nil
+}
+
+// Value stores scoring rules as JSON
+func (rules ScoringRules) Value() (driver.Value, error) {
+	if len(rules) == 0 {
+		return "", nil
+	}
+	data, err := json.Marshal(rules)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan reads scoring rules stored as JSON
+func (rules *ScoringRules) Scan(src interface{}) error {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return fmt.Errorf("cannot scan %T into scoring rules", src)
+	}
+
+	if len(data) == 0 {
+		*rules = nil
+		return nil
+	}
+	return json.Unmarshal(data, rules)
 }
 
 // ServiceEvent event coming from services
@@ -97,6 +380,77 @@ type AttachmentItem struct {
 	Points          int
 }
 
+// Blocker is a problem mentioned in a standup, it stays open until someone resolves it
+type Blocker struct {
+	ID          int64  `db:"id" json:"id"`
+	CreatedAt   int64  `db:"created_at" json:"created_at"`
+	WorkspaceID string `db:"workspace_id" json:"workspace_id"`
+	ChannelID   string `db:"channel_id" json:"channel_id"`
+	UserID      string `db:"user_id" json:"user_id"`
+	StandupID   int64  `db:"standup_id" json:"standup_id"`
+	Text        string `db:"text" json:"text"`
+	Resolved    bool   `db:"resolved" json:"resolved"`
+	ResolvedAt  int64  `db:"resolved_at" json:"resolved_at"`
+	ResolvedBy  string `db:"resolved_by" json:"resolved_by"`
+}
+
+// Absence is a period when user is not expected to submit standups.
+// Dates are in YYYY-MM-DD format, empty ChannelID means all user projects
+type Absence struct {
+	ID          int64  `db:"id" json:"id"`
+	CreatedAt   int64  `db:"created_at" json:"created_at"`
+	WorkspaceID string `db:"workspace_id" json:"workspace_id"`
+	UserID      string `db:"user_id" json:"user_id"`
+	ChannelID   string `db:"channel_id" json:"channel_id"`
+	DateFrom    string `db:"date_from" json:"date_from"`
+	DateTo      string `db:"date_to" json:"date_to"`
+	Reason      string `db:"reason" json:"reason"`
+}
+
+// AbsenceDateFormat is the format of absence dates
+const AbsenceDateFormat = "2006-01-02"
+
+// Holiday is a day when standups are not expected in workspace,
+// or only in one project if ChannelID is set
+type Holiday struct {
+	ID          int64  `db:"id" json:"id"`
+	CreatedAt   int64  `db:"created_at" json:"created_at"`
+	WorkspaceID string `db:"workspace_id" json:"workspace_id"`
+	ChannelID   string `db:"channel_id" json:"channel_id"`
+	Date        string `db:"date" json:"date"`
+	Name        string `db:"name" json:"name"`
+}
+
+// Job keeps the time until which a scheduled job of the workspace has already run
+type Job struct {
+	ID          int64  `db:"id" json:"id"`
+	WorkspaceID string `db:"workspace_id" json:"workspace_id"`
+	Name        string `db:"name" json:"name"`
+	LastRun     int64  `db:"last_run" json:"last_run"`
+}
+
+// Lease marks which replica owns a named resource until ExpiresAt
+type Lease struct {
+	ID        int64  `db:"id" json:"id"`
+	Name      string `db:"name" json:"name"`
+	Owner     string `db:"owner" json:"owner"`
+	ExpiresAt int64  `db:"expires_at" json:"expires_at"`
+}
+
+// StandupDraft keeps answers of standuper collected in direct messages
+// until the standup is complete. Section is the one the next answer goes to
+type StandupDraft struct {
+	ID          int64  `db:"id" json:"id"`
+	CreatedAt   int64  `db:"created_at" json:"created_at"`
+	WorkspaceID string `db:"workspace_id" json:"workspace_id"`
+	ChannelID   string `db:"channel_id" json:"channel_id"`
+	UserID      string `db:"user_id" json:"user_id"`
+	Section     string `db:"section" json:"section"`
+	Done        string `db:"done" json:"done"`
+	Planned     string `db:"planned" json:"planned"`
+	Blockers    string `db:"blockers" json:"blockers"`
+}
+
 //NotificationThread ...
 type NotificationThread struct {
 	ID               int64  `db:"id" json:"id"`
@@ -164,7 +518,29 @@ func (bs Workspace) Validate() error {
 		return err
 	}
 
-	return nil
+	switch bs.Platform {
+	case "", PlatformSlack, PlatformMattermost, PlatformTelegram:
+	default:
+		err := errors.New("platform must be slack, mattermost or telegram")
+		return err
+	}
+
+	switch bs.WorkSource {
+	case "", WorkSourceCollector, WorkSourceJira, WorkSourceGitLab, WorkSourceGitHub, WorkSourceToggl, WorkSourceGit:
+	default:
+		err := errors.New("work source must be collector, jira, gitlab, github, toggl or git")
+		return err
+	}
+
+	if bs.WorkSourceURL != "" {
+		u, err := url.Parse(bs.WorkSourceURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			err := errors.New("work source URL must be absolute http or https URL")
+			return err
+		}
+	}
+
+	return bs.ScoringRules.Validate()
 }
 
 // Validate validates Project struct
@@ -184,6 +560,28 @@ func (ch Project) Validate() error {
 		return err
 	}
 
+	for _, section := range strings.Split(ch.OptionalSections, ",") {
+		section = strings.TrimSpace(section)
+		if section == "" {
+			continue
+		}
+		known := false
+		for _, s := range StandupSections {
+			if s == section {
+				known = true
+			}
+		}
+		if !known {
+			err := errors.New("unknown standup section: " + section)
+			return err
+		}
+	}
+
+	if ch.DMPromptOffset < 0 {
+		err := errors.New("DM prompt offset cannot be negative")
+		return err
+	}
+
 	return nil
 }
 
@@ -223,3 +621,212 @@ func (nt NotificationThread) Validate() error {
 	}
 	return nil
 }
+
+// Validate validates Blocker struct
+func (b Blocker) Validate() error {
+	if b.WorkspaceID == "" {
+		err := errors.New("workspace ID cannot be empty")
+		return err
+	}
+
+	if b.ChannelID == "" {
+		err := errors.New("channel ID cannot be empty")
+		return err
+	}
+
+	if b.UserID == "" {
+		err := errors.New("user ID cannot be empty")
+		return err
+	}
+
+	if strings.TrimSpace(b.Text) == "" {
+		err := errors.New("blocker text cannot be empty")
+		return err
+	}
+
+	return nil
+}
+
+// Validate validates Absence struct
+func (a Absence) Validate() error {
+	if a.WorkspaceID == "" {
+		err := errors.New("workspace ID cannot be empty")
+		return err
+	}
+
+	if a.UserID == "" {
+		err := errors.New("user ID cannot be empty")
+		return err
+	}
+
+	from, err := time.Parse(AbsenceDateFormat, a.DateFrom)
+	if err != nil {
+		err := errors.New("date from must be in YYYY-MM-DD format")
+		return err
+	}
+
+	to, err := time.Parse(AbsenceDateFormat, a.DateTo)
+	if err != nil {
+		err := errors.New("date to must be in YYYY-MM-DD format")
+		return err
+	}
+
+	if to.Before(from) {
+		err := errors.New("date to cannot be before date from")
+		return err
+	}
+
+	return nil
+}
+
+// Validate validates Holiday struct
+func (h Holiday) Validate() error {
+	if h.WorkspaceID == "" {
+		err := errors.New("workspace ID cannot be empty")
+		return err
+	}
+
+	_, err := time.Parse(AbsenceDateFormat, h.Date)
+	if err != nil {
+		err := errors.New("date must be in YYYY-MM-DD format")
+		return err
+	}
+
+	return nil
+}
+
+// Validate validates Job struct
+func (j Job) Validate() error {
+	if j.WorkspaceID == "" {
+		err := errors.New("workspace ID cannot be empty")
+		return err
+	}
+
+	if j.Name == "" {
+		err := errors.New("job name cannot be empty")
+		return err
+	}
+
+	return nil
+}
+
+// Validate validates StandupDraft struct
+func (d StandupDraft) Validate() error {
+	if d.WorkspaceID == "" {
+		err := errors.New("workspace ID cannot be empty")
+		return err
+	}
+
+	if d.ChannelID == "" {
+		err := errors.New("channel ID cannot be empty")
+		return err
+	}
+
+	if d.UserID == "" {
+		err := errors.New("user ID cannot be empty")
+		return err
+	}
+
+	for _, s := range StandupSections {
+		if s == d.Section {
+			return nil
+		}
+	}
+	err := errors.New("unknown standup section: " + d.Section)
+	return err
+}
+
+// Validate validates Admin struct
+func (a Admin) Validate() error {
+	if a.WorkspaceID == "" {
+		err := errors.New("workspace ID cannot be empty")
+		return err
+	}
+
+	if a.UserID == "" {
+		err := errors.New("user ID cannot be empty")
+		return err
+	}
+
+	return nil
+}
+
+// Subscribed tells if webhook wants the event
+func (w Webhook) Subscribed(event string) bool {
+	if !w.Enabled {
+		return false
+	}
+	if strings.TrimSpace(w.Events) == "" {
+		return true
+	}
+	for _, e := range strings.Split(w.Events, ",") {
+		if strings.TrimSpace(e) == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate validates Webhook struct
+func (w Webhook) Validate() error {
+	if w.WorkspaceID == "" {
+		err := errors.New("workspace ID cannot be empty")
+		return err
+	}
+
+	u, err := url.Parse(w.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		err := errors.New("webhook URL must be absolute http or https URL")
+		return err
+	}
+
+	if w.Secret == "" {
+		err := errors.New("webhook secret cannot be empty")
+		return err
+	}
+
+	if strings.TrimSpace(w.Events) == "" {
+		return nil
+	}
+	for _, e := range strings.Split(w.Events, ",") {
+		known := false
+		for _, event := range WebhookEvents {
+			if strings.TrimSpace(e) == event {
+				known = true
+			}
+		}
+		if !known {
+			err := errors.New("unknown webhook event: " + strings.TrimSpace(e))
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Validate validates WebhookDelivery struct
+func (d WebhookDelivery) Validate() error {
+	if d.WorkspaceID == "" {
+		err := errors.New("workspace ID cannot be empty")
+		return err
+	}
+
+	if d.WebhookID == 0 {
+		err := errors.New("webhook ID cannot be empty")
+		return err
+	}
+
+	if d.Event == "" {
+		err := errors.New("event cannot be empty")
+		return err
+	}
+
+	switch d.Status {
+	case DeliveryPending, DeliveryDelivered, DeliveryFailed:
+	default:
+		err := errors.New("unknown delivery status: " + d.Status)
+		return err
+	}
+
+	return nil
+}
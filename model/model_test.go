@@ -74,23 +74,65 @@ func TestWorkspace(t *testing.T) {
 	}
 }
 
-func TestChannel(t *testing.T) {
+func TestWorkspaceWorkSource(t *testing.T) {
 	testCases := []struct {
-		workspaceID  string
-		channelName  string
-		channelID    string
+		source       string
+		url          string
 		errorMessage string
 	}{
-		{"", "", "", "workspace ID cannot be empty"},
-		{"workspaceID", "", "", "channel name cannot be empty"},
-		{"workspaceID", "chanName", "", "channel ID cannot be empty"},
-		{"workspaceID", "chanName", "chanID", ""},
+		{"", "", ""},
+		{WorkSourceCollector, "https://collector.example.com", ""},
+		{WorkSourceJira, "https://example.atlassian.net", ""},
+		{WorkSourceGitHub, "", ""},
+		{WorkSourceGit, "", ""},
+		{"redmine", "", "work source must be collector, jira, gitlab, github, toggl or git"},
+		{WorkSourceGitLab, "gitlab.example.com", "work source URL must be absolute http or https URL"},
+		{WorkSourceToggl, "ftp://toggl", "work source URL must be absolute http or https URL"},
+	}
+	for _, tt := range testCases {
+		bs := Workspace{
+			WorkspaceID:    "tID",
+			WorkspaceName:  "tName",
+			BotAccessToken: "accToken",
+			ReminderOffset: 1,
+			ReportingTime:  "01:00",
+			Language:       "en_US",
+			WorkSource:     tt.source,
+			WorkSourceURL:  tt.url,
+		}
+		err := bs.Validate()
+		if tt.errorMessage == "" {
+			assert.NoError(t, err)
+			continue
+		}
+		assert.Equal(t, errors.New(tt.errorMessage), err)
+	}
+}
+
+func TestChannel(t *testing.T) {
+	testCases := []struct {
+		workspaceID      string
+		channelName      string
+		channelID        string
+		optionalSections string
+		dmPromptOffset   int64
+		errorMessage     string
+	}{
+		{"", "", "", "", 0, "workspace ID cannot be empty"},
+		{"workspaceID", "", "", "", 0, "channel name cannot be empty"},
+		{"workspaceID", "chanName", "", "", 0, "channel ID cannot be empty"},
+		{"workspaceID", "chanName", "chanID", "today, tomorrow", 0, "unknown standup section: tomorrow"},
+		{"workspaceID", "chanName", "chanID", "today, problems", 0, ""},
+		{"workspaceID", "chanName", "chanID", "", -5, "DM prompt offset cannot be negative"},
+		{"workspaceID", "chanName", "chanID", "", 30, ""},
 	}
 	for _, tt := range testCases {
 		ch := Project{
-			WorkspaceID: tt.workspaceID,
-			ChannelName: tt.channelName,
-			ChannelID:   tt.channelID,
+			WorkspaceID:      tt.workspaceID,
+			ChannelName:      tt.channelName,
+			ChannelID:        tt.channelID,
+			OptionalSections: tt.optionalSections,
+			DMPromptOffset:   tt.dmPromptOffset,
 		}
 		err := ch.Validate()
 		if err != nil {
@@ -151,3 +193,309 @@ func TestNotificationThread(t *testing.T) {
 		}
 	}
 }
+
+func TestBlocker(t *testing.T) {
+	testCases := []struct {
+		workspaceID  string
+		channelID    string
+		userID       string
+		text         string
+		errorMessage string
+	}{
+		{"", "", "", "", "workspace ID cannot be empty"},
+		{"workspaceID", "", "", "", "channel ID cannot be empty"},
+		{"workspaceID", "channelID", "", "", "user ID cannot be empty"},
+		{"workspaceID", "channelID", "userID", " ", "blocker text cannot be empty"},
+		{"workspaceID", "channelID", "userID", "CI is down", ""},
+	}
+	for _, tt := range testCases {
+		b := Blocker{
+			WorkspaceID: tt.workspaceID,
+			ChannelID:   tt.channelID,
+			UserID:      tt.userID,
+			Text:        tt.text,
+		}
+		err := b.Validate()
+		if tt.errorMessage == "" {
+			assert.NoError(t, err)
+		} else {
+			assert.Equal(t, errors.New(tt.errorMessage), err)
+		}
+	}
+}
+
+func TestAbsence(t *testing.T) {
+	testCases := []struct {
+		workspaceID  string
+		userID       string
+		dateFrom     string
+		dateTo       string
+		errorMessage string
+	}{
+		{"", "", "", "", "workspace ID cannot be empty"},
+		{"workspaceID", "", "", "", "user ID cannot be empty"},
+		{"workspaceID", "userID", "01.11.2026", "", "date from must be in YYYY-MM-DD format"},
+		{"workspaceID", "userID", "2026-11-01", "", "date to must be in YYYY-MM-DD format"},
+		{"workspaceID", "userID", "2026-11-14", "2026-11-01", "date to cannot be before date from"},
+		{"workspaceID", "userID", "2026-11-01", "2026-11-01", ""},
+	}
+	for _, tt := range testCases {
+		a := Absence{
+			WorkspaceID: tt.workspaceID,
+			UserID:      tt.userID,
+			DateFrom:    tt.dateFrom,
+			DateTo:      tt.dateTo,
+		}
+		err := a.Validate()
+		if tt.errorMessage == "" {
+			assert.NoError(t, err)
+		} else {
+			assert.Equal(t, errors.New(tt.errorMessage), err)
+		}
+	}
+}
+
+func TestHoliday(t *testing.T) {
+	testCases := []struct {
+		workspaceID  string
+		date         string
+		errorMessage string
+	}{
+		{"", "", "workspace ID cannot be empty"},
+		{"workspaceID", "01.01.2026", "date must be in YYYY-MM-DD format"},
+		{"workspaceID", "2026-01-01", ""},
+	}
+	for _, tt := range testCases {
+		h := Holiday{
+			WorkspaceID: tt.workspaceID,
+			Date:        tt.date,
+		}
+		err := h.Validate()
+		if tt.errorMessage == "" {
+			assert.NoError(t, err)
+		} else {
+			assert.Equal(t, errors.New(tt.errorMessage), err)
+		}
+	}
+}
+
+func TestJob(t *testing.T) {
+	testCases := []struct {
+		workspaceID  string
+		name         string
+		errorMessage string
+	}{
+		{"", "", "workspace ID cannot be empty"},
+		{"workspaceID", "", "job name cannot be empty"},
+		{"workspaceID", "daily_report", ""},
+	}
+	for _, tt := range testCases {
+		j := Job{
+			WorkspaceID: tt.workspaceID,
+			Name:        tt.name,
+		}
+		err := j.Validate()
+		if tt.errorMessage == "" {
+			assert.NoError(t, err)
+		} else {
+			assert.Equal(t, errors.New(tt.errorMessage), err)
+		}
+	}
+}
+
+func TestStandupDraft(t *testing.T) {
+	testCases := []struct {
+		workspaceID  string
+		channelID    string
+		userID       string
+		section      string
+		errorMessage string
+	}{
+		{"", "", "", "", "workspace ID cannot be empty"},
+		{"workspaceID", "", "", "", "channel ID cannot be empty"},
+		{"workspaceID", "channelID", "", "", "user ID cannot be empty"},
+		{"workspaceID", "channelID", "userID", "tomorrow", "unknown standup section: tomorrow"},
+		{"workspaceID", "channelID", "userID", "today", ""},
+	}
+	for _, tt := range testCases {
+		d := StandupDraft{
+			WorkspaceID: tt.workspaceID,
+			ChannelID:   tt.channelID,
+			UserID:      tt.userID,
+			Section:     tt.section,
+		}
+		err := d.Validate()
+		if tt.errorMessage == "" {
+			assert.NoError(t, err)
+		} else {
+			assert.Equal(t, errors.New(tt.errorMessage), err)
+		}
+	}
+}
+
+func TestAdmin(t *testing.T) {
+	testCases := []struct {
+		workspaceID  string
+		userID       string
+		errorMessage string
+	}{
+		{"", "", "workspace ID cannot be empty"},
+		{"workspaceID", "", "user ID cannot be empty"},
+		{"workspaceID", "userID", ""},
+	}
+	for _, tt := range testCases {
+		a := Admin{
+			WorkspaceID: tt.workspaceID,
+			UserID:      tt.userID,
+		}
+		err := a.Validate()
+		if tt.errorMessage == "" {
+			assert.NoError(t, err)
+		} else {
+			assert.Equal(t, errors.New(tt.errorMessage), err)
+		}
+	}
+}
+
+func TestWebhook(t *testing.T) {
+	testCases := []struct {
+		workspaceID  string
+		url          string
+		secret       string
+		events       string
+		errorMessage string
+	}{
+		{"", "", "", "", "workspace ID cannot be empty"},
+		{"workspaceID", "", "", "", "webhook URL must be absolute http or https URL"},
+		{"workspaceID", "ftp://example.com", "", "", "webhook URL must be absolute http or https URL"},
+		{"workspaceID", "/hooks", "", "", "webhook URL must be absolute http or https URL"},
+		{"workspaceID", "https://example.com/hooks", "", "", "webhook secret cannot be empty"},
+		{"workspaceID", "https://example.com/hooks", "secret", "standup.created, standup.eaten", "unknown webhook event: standup.eaten"},
+		{"workspaceID", "https://example.com/hooks", "secret", "standup.created, standup.missed", ""},
+		{"workspaceID", "http://localhost:8000/hooks", "secret", "", ""},
+	}
+	for _, tt := range testCases {
+		w := Webhook{
+			WorkspaceID: tt.workspaceID,
+			URL:         tt.url,
+			Secret:      tt.secret,
+			Events:      tt.events,
+		}
+		err := w.Validate()
+		if tt.errorMessage == "" {
+			assert.NoError(t, err)
+		} else {
+			assert.Equal(t, errors.New(tt.errorMessage), err)
+		}
+	}
+}
+
+func TestWebhookSubscribed(t *testing.T) {
+	testCases := []struct {
+		enabled    bool
+		events     string
+		event      string
+		subscribed bool
+	}{
+		{true, "", EventStandupMissed, true},
+		{true, "standup.created, standup.missed", EventStandupMissed, true},
+		{true, "standup.created", EventStandupMissed, false},
+		{false, "", EventStandupMissed, false},
+	}
+	for _, tt := range testCases {
+		w := Webhook{Enabled: tt.enabled, Events: tt.events}
+		assert.Equal(t, tt.subscribed, w.Subscribed(tt.event), tt)
+	}
+}
+
+func TestWebhookDelivery(t *testing.T) {
+	testCases := []struct {
+		workspaceID  string
+		webhookID    int64
+		event        string
+		status       string
+		errorMessage string
+	}{
+		{"", 0, "", "", "workspace ID cannot be empty"},
+		{"workspaceID", 0, "", "", "webhook ID cannot be empty"},
+		{"workspaceID", 1, "", "", "event cannot be empty"},
+		{"workspaceID", 1, "standup.created", "lost", "unknown delivery status: lost"},
+		{"workspaceID", 1, "standup.created", "pending", ""},
+	}
+	for _, tt := range testCases {
+		d := WebhookDelivery{
+			WorkspaceID: tt.workspaceID,
+			WebhookID:   tt.webhookID,
+			Event:       tt.event,
+			Status:      tt.status,
+		}
+		err := d.Validate()
+		if tt.errorMessage == "" {
+			assert.NoError(t, err)
+		} else {
+			assert.Equal(t, errors.New(tt.errorMessage), err)
+		}
+	}
+}
+
+func TestScoringRules(t *testing.T) {
+	rules := ScoringRules{
+		{DailyWorklogs: []ScoreLevel{{Min: 0, Emoji: ":coffee:"}, {Min: 6, Emoji: ":wink:", Good: true}}, GoodColor: "#00ff00"},
+		{Role: "designer", SkipCommits: true, DailyWorklogs: []ScoreLevel{{Min: 2, Emoji: ":art:", Good: true}}},
+	}
+
+	rule := rules.Rule("developer")
+	assert.Equal(t, "", rule.Role)
+	assert.Equal(t, ":coffee:", Grade(rule.DailyWorklogs, 5.9).Emoji)
+	assert.True(t, Grade(rule.DailyWorklogs, 6).Good)
+	assert.Equal(t, DefaultScoringRules[0].Commits, rule.Commits)
+	assert.Equal(t, 7.0, rule.HolidayHours)
+	assert.Equal(t, "#00ff00", rule.GoodColor)
+	assert.Equal(t, "danger", rule.BadColor)
+	assert.False(t, rule.SkipCommits)
+
+	rule = rules.Rule("designer")
+	assert.True(t, rule.SkipCommits)
+	assert.Equal(t, ScoreLevel{}, Grade(rule.DailyWorklogs, 1))
+	assert.Equal(t, ":art:", Grade(rule.DailyWorklogs, 2).Emoji)
+	assert.Equal(t, "good", rule.GoodColor)
+
+	rule = ScoringRules(nil).Rule("pm")
+	assert.True(t, rule.SkipCommits)
+	assert.Equal(t, ":angry:", Grade(rule.DailyWorklogs, 2.9).Emoji)
+	assert.Equal(t, ":disappointed:", Grade(rule.DailyWorklogs, 3).Emoji)
+	assert.Equal(t, ":sunglasses:", Grade(rule.DailyWorklogs, 9).Emoji)
+	assert.Equal(t, ":shit:", Grade(rule.Commits, 0).Emoji)
+	assert.False(t, ScoringRules(nil).Rule("developer").SkipCommits)
+
+	value, err := rules.Value()
+	assert.NoError(t, err)
+	var scanned ScoringRules
+	assert.NoError(t, scanned.Scan([]byte(value.(string))))
+	assert.Equal(t, rules, scanned)
+	assert.NoError(t, scanned.Scan(""))
+	assert.Nil(t, scanned)
+	value, err = scanned.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "", value)
+
+	testCases := []struct {
+		rules        ScoringRules
+		errorMessage string
+	}{
+		{nil, ""},
+		{rules, ""},
+		{ScoringRules{{Role: "pm"}, {Role: "pm"}}, "scoring rules have several rules for role 'pm'"},
+		{ScoringRules{{Commits: []ScoreLevel{{Min: -1}}}}, "score levels must start from zero or more and grow"},
+		{ScoringRules{{WeeklyWorklogs: []ScoreLevel{{Min: 30}, {Min: 30}}}}, "score levels must start from zero or more and grow"},
+		{ScoringRules{{HolidayHours: -7}}, "holiday hours cannot be negative"},
+	}
+	for _, tt := range testCases {
+		err := tt.rules.Validate()
+		if tt.errorMessage == "" {
+			assert.NoError(t, err)
+			continue
+		}
+		assert.EqualError(t, err, tt.errorMessage)
+	}
+}
@@ -0,0 +1,195 @@
+// Package secret encrypts credentials stored in database
+package secret
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// envelopePrefix marks encrypted values, values without it are legacy plaintext
+const envelopePrefix = "enc:v1:"
+
+var encoding = base64.RawStdEncoding
+
+// Keyring encrypts values with the primary key and decrypts values encrypted with any of its keys.
+// Every value gets its own random data key, which is encrypted (wrapped) with the primary key
+// and stored next to the value, so rotating the primary key only rewraps data keys
+type Keyring struct {
+	primary string
+	keys    map[string]cipher.AEAD
+}
+
+// NewKeyring parses comma separated "id:base64 key" pairs of 32 byte AES keys.
+// The first key is primary, the rest are kept to decrypt values encrypted before rotation.
+// Empty keys give nil keyring, which stores values as plaintext
+func NewKeyring(keys string) (*Keyring, error) {
+	if strings.TrimSpace(keys) == "" {
+		return nil, nil
+	}
+
+	k := &Keyring{keys: map[string]cipher.AEAD{}}
+	for _, pair := range strings.Split(keys, ",") {
+		parts := strings.SplitN(strings.TrimSpace(pair), ":", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("encryption key must be 'id:base64 key', got %q", pair)
+		}
+		id := parts[0]
+		if _, ok := k.keys[id]; ok {
+			return nil, fmt.Errorf("duplicate encryption key id %v", id)
+		}
+
+		key, err := base64.StdEncoding.DecodeString(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("encryption key %v is not base64: %v", id, err)
+		}
+		if len(key) != 32 {
+			return nil, fmt.Errorf("encryption key %v must be 32 bytes, got %d", id, len(key))
+		}
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, err
+		}
+
+		k.keys[id] = aead
+		if k.primary == "" {
+			k.primary = id
+		}
+	}
+	return k, nil
+}
+
+// Encrypt seals plaintext with a new data key wrapped with the primary key
+func (k *Keyring) Encrypt(plaintext string) (string, error) {
+	if k == nil || plaintext == "" {
+		return plaintext, nil
+	}
+
+	dataKey := make([]byte, 32)
+	_, err := rand.Read(dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	wrapped, err := seal(k.keys[k.primary], dataKey, []byte(k.primary))
+	if err != nil {
+		return "", err
+	}
+
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := seal(aead, []byte(plaintext), nil)
+	if err != nil {
+		return "", err
+	}
+
+	return envelopePrefix + k.primary + ":" + encoding.EncodeToString(wrapped) + ":" + encoding.EncodeToString(sealed), nil
+}
+
+// Decrypt opens value sealed by Encrypt. Plaintext values are returned as is,
+// so rows written before encryption was configured keep working until rewritten
+func (k *Keyring) Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+	if k == nil {
+		return "", errors.New("value is encrypted, but no encryption keys are configured")
+	}
+
+	parts := strings.Split(strings.TrimPrefix(value, envelopePrefix), ":")
+	if len(parts) != 3 {
+		return "", errors.New("malformed encrypted value")
+	}
+
+	master, ok := k.keys[parts[0]]
+	if !ok {
+		return "", fmt.Errorf("value is encrypted with unknown key %v", parts[0])
+	}
+
+	wrapped, err := encoding.DecodeString(parts[1])
+	if err != nil {
+		return "", errors.New("malformed encrypted value")
+	}
+	dataKey, err := open(master, wrapped, []byte(parts[0]))
+	if err != nil {
+		return "", err
+	}
+
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := encoding.DecodeString(parts[2])
+	if err != nil {
+		return "", errors.New("malformed encrypted value")
+	}
+	plaintext, err := open(aead, sealed, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// NeedsRewrite tells if value is plaintext or encrypted with a key other than primary
+func (k *Keyring) NeedsRewrite(value string) bool {
+	if k == nil || value == "" {
+		return false
+	}
+	if !IsEncrypted(value) {
+		return true
+	}
+	return !strings.HasPrefix(value, envelopePrefix+k.primary+":")
+}
+
+// IsEncrypted tells encrypted values from plaintext ones
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, envelopePrefix)
+}
+
+// Hash returns lookup hash of a high entropy secret such as access token.
+// It does not depend on encryption keys, so it survives key rotation
+func Hash(value string) string {
+	if value == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal returns random nonce followed by ciphertext
+func seal(aead cipher.AEAD, plaintext, additionalData []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	_, err := rand.Read(nonce)
+	if err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func open(aead cipher.AEAD, sealed, additionalData []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("malformed encrypted value")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, additionalData)
+	if err != nil {
+		return nil, errors.New("could not decrypt value, wrong key or corrupted data")
+	}
+	return plaintext, nil
+}
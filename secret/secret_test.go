@@ -0,0 +1,96 @@
+package secret
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	oldKey = base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef"))
+	newKey = base64.StdEncoding.EncodeToString([]byte("fedcba9876543210fedcba9876543210"))
+)
+
+func TestNewKeyring(t *testing.T) {
+	testCases := []struct {
+		keys         string
+		errorMessage string
+	}{
+		{"", ""},
+		{"k1:" + oldKey, ""},
+		{"k2:" + newKey + ", k1:" + oldKey, ""},
+		{oldKey, "encryption key must be 'id:base64 key'"},
+		{"k1:" + oldKey + ",k1:" + newKey, "duplicate encryption key id k1"},
+		{"k1:not base64", "encryption key k1 is not base64"},
+		{"k1:" + base64.StdEncoding.EncodeToString([]byte("short")), "encryption key k1 must be 32 bytes, got 5"},
+	}
+
+	for _, tt := range testCases {
+		_, err := NewKeyring(tt.keys)
+		if tt.errorMessage == "" {
+			assert.NoError(t, err, tt.keys)
+		} else if assert.Error(t, err, tt.keys) {
+			assert.Contains(t, err.Error(), tt.errorMessage)
+		}
+	}
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	old, err := NewKeyring("k1:" + oldKey)
+	require.NoError(t, err)
+	rotated, err := NewKeyring("k2:" + newKey + ",k1:" + oldKey)
+	require.NoError(t, err)
+	other, err := NewKeyring("k1:" + newKey)
+	require.NoError(t, err)
+
+	encrypted, err := old.Encrypt("xoxb-token")
+	require.NoError(t, err)
+	assert.True(t, IsEncrypted(encrypted))
+	assert.False(t, strings.Contains(encrypted, "xoxb-token"))
+
+	again, err := old.Encrypt("xoxb-token")
+	require.NoError(t, err)
+	assert.NotEqual(t, encrypted, again, "every value gets its own data key and nonce")
+
+	plaintext, err := old.Decrypt(encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "xoxb-token", plaintext)
+
+	// keys removed from primary position still decrypt old values
+	plaintext, err = rotated.Decrypt(encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "xoxb-token", plaintext)
+	assert.False(t, old.NeedsRewrite(encrypted))
+	assert.True(t, rotated.NeedsRewrite(encrypted))
+
+	_, err = other.Decrypt(encrypted)
+	assert.Error(t, err)
+
+	var none *Keyring
+	_, err = none.Decrypt(encrypted)
+	assert.Error(t, err)
+
+	_, err = old.Decrypt(encrypted[:len(encrypted)-4])
+	assert.Error(t, err)
+
+	// plaintext written before encryption was configured
+	plaintext, err = rotated.Decrypt("xoxb-legacy")
+	require.NoError(t, err)
+	assert.Equal(t, "xoxb-legacy", plaintext)
+	assert.True(t, rotated.NeedsRewrite("xoxb-legacy"))
+	assert.False(t, none.NeedsRewrite("xoxb-legacy"))
+
+	stored, err := none.Encrypt("xoxb-token")
+	require.NoError(t, err)
+	assert.Equal(t, "xoxb-token", stored)
+}
+
+func TestHash(t *testing.T) {
+	assert.Equal(t, "", Hash(""))
+	assert.Equal(t, Hash("xoxb-token"), Hash("xoxb-token"))
+	assert.NotEqual(t, Hash("xoxb-token"), Hash("xoxb-other"))
+	assert.Equal(t, 64, len(Hash("xoxb-token")))
+}
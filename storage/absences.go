@@ -0,0 +1,95 @@
+package storage
+
+import (
+	"github.com/maddevsio/comedian/model"
+)
+
+// CreateAbsence creates absence entry in database
+func (m *DB) CreateAbsence(a model.Absence) (model.Absence, error) {
+	err := a.Validate()
+	if err != nil {
+		return a, err
+	}
+
+	id, err := m.insert(
+		`INSERT INTO absences (
+			created_at,
+			workspace_id,
+			user_id,
+			channel_id,
+			date_from,
+			date_to,
+			reason
+		) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		a.CreatedAt,
+		a.WorkspaceID,
+		a.UserID,
+		a.ChannelID,
+		a.DateFrom,
+		a.DateTo,
+		a.Reason,
+	)
+	if err != nil {
+		return a, err
+	}
+	a.ID = id
+
+	return a, nil
+}
+
+// UpdateAbsence updates absence period and reason
+func (m *DB) UpdateAbsence(a model.Absence) (model.Absence, error) {
+	err := a.Validate()
+	if err != nil {
+		return a, err
+	}
+
+	_, err = m.exec(
+		"UPDATE absences SET channel_id=?, date_from=?, date_to=?, reason=? WHERE id=?",
+		a.ChannelID, a.DateFrom, a.DateTo, a.Reason, a.ID,
+	)
+	if err != nil {
+		return a, err
+	}
+
+	return m.GetAbsence(a.ID)
+}
+
+// GetAbsence selects absence entry from database
+func (m *DB) GetAbsence(id int64) (model.Absence, error) {
+	var a model.Absence
+	err := m.get(&a, "SELECT * FROM absences WHERE id=?", id)
+	return a, err
+}
+
+// ListTeamAbsences returns all workspace absences
+func (m *DB) ListTeamAbsences(teamID string) ([]model.Absence, error) {
+	items := []model.Absence{}
+	err := m.list(&items, "SELECT * FROM absences WHERE workspace_id=? order by date_from", teamID)
+	return items, err
+}
+
+// ListUserAbsences returns absences of the user that end on date or later
+func (m *DB) ListUserAbsences(userID, date string) ([]model.Absence, error) {
+	items := []model.Absence{}
+	err := m.list(&items, "SELECT * FROM absences WHERE user_id=? AND date_to>=? order by date_from", userID, date)
+	return items, err
+}
+
+// FindAbsence returns absence of the user in the channel that includes date
+func (m *DB) FindAbsence(userID, channelID, date string) (model.Absence, error) {
+	var a model.Absence
+	err := m.get(&a,
+		`SELECT * FROM absences 
+		WHERE user_id=? AND (channel_id='' OR channel_id=?) AND date_from<=? AND date_to>=? 
+		order by id limit 1`,
+		userID, channelID, date, date,
+	)
+	return a, err
+}
+
+// DeleteAbsence deletes absence entry from database
+func (m *DB) DeleteAbsence(id int64) error {
+	_, err := m.exec("DELETE FROM absences WHERE id=?", id)
+	return err
+}
@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/maddevsio/comedian/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAbsences(t *testing.T) {
+	_, err := db.CreateAbsence(model.Absence{WorkspaceID: "foo", UserID: "bar", DateFrom: "2026-11-14", DateTo: "2026-11-01"})
+	assert.Error(t, err)
+
+	a, err := db.CreateAbsence(model.Absence{
+		WorkspaceID: "foo",
+		UserID:      "away1",
+		DateFrom:    "2026-11-01",
+		DateTo:      "2026-11-14",
+		Reason:      "vacation",
+	})
+	require.NoError(t, err)
+
+	_, err = db.FindAbsence("away1", "chan1", "2026-11-01")
+	assert.NoError(t, err)
+
+	_, err = db.FindAbsence("away1", "chan1", "2026-11-15")
+	assert.Error(t, err)
+
+	a.ChannelID = "chan2"
+	a.DateTo = "2026-11-15"
+	_, err = db.UpdateAbsence(a)
+	require.NoError(t, err)
+
+	_, err = db.FindAbsence("away1", "chan1", "2026-11-10")
+	assert.Error(t, err)
+
+	found, err := db.FindAbsence("away1", "chan2", "2026-11-15")
+	require.NoError(t, err)
+	assert.Equal(t, "vacation", found.Reason)
+
+	absences, err := db.ListUserAbsences("away1", "2026-11-15")
+	require.NoError(t, err)
+	assert.Equal(t, 1, len(absences))
+
+	absences, err = db.ListUserAbsences("away1", "2026-11-16")
+	require.NoError(t, err)
+	assert.Equal(t, 0, len(absences))
+
+	absences, err = db.ListTeamAbsences("foo")
+	require.NoError(t, err)
+	assert.Equal(t, 1, len(absences))
+
+	assert.NoError(t, db.DeleteAbsence(a.ID))
+
+	_, err = db.GetAbsence(a.ID)
+	assert.Error(t, err)
+}
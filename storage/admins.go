@@ -0,0 +1,50 @@
+package storage
+
+import (
+	"github.com/maddevsio/comedian/model"
+)
+
+// CreateAdmin creates admin entry in database
+func (m *DB) CreateAdmin(a model.Admin) (model.Admin, error) {
+	err := a.Validate()
+	if err != nil {
+		return a, err
+	}
+
+	id, err := m.insert(
+		`INSERT INTO admins (
+			created_at,
+			workspace_id,
+			user_id
+		) VALUES (?, ?, ?)`,
+		a.CreatedAt,
+		a.WorkspaceID,
+		a.UserID,
+	)
+	if err != nil {
+		return a, err
+	}
+	a.ID = id
+
+	return a, nil
+}
+
+// FindAdmin selects admin of the workspace by user ID
+func (m *DB) FindAdmin(workspaceID, userID string) (model.Admin, error) {
+	var a model.Admin
+	err := m.get(&a, "SELECT * FROM admins WHERE workspace_id=? AND user_id=?", workspaceID, userID)
+	return a, err
+}
+
+// ListWorkspaceAdmins returns admins of the workspace
+func (m *DB) ListWorkspaceAdmins(workspaceID string) ([]model.Admin, error) {
+	items := []model.Admin{}
+	err := m.list(&items, "SELECT * FROM admins WHERE workspace_id=? order by id", workspaceID)
+	return items, err
+}
+
+// DeleteAdmin deletes admin entry from database
+func (m *DB) DeleteAdmin(id int64) error {
+	_, err := m.exec("DELETE FROM admins WHERE id=?", id)
+	return err
+}
@@ -0,0 +1,47 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/maddevsio/comedian/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdmins(t *testing.T) {
+	_, err := db.CreateAdmin(model.Admin{WorkspaceID: "admins"})
+	assert.Error(t, err)
+
+	_, err = db.FindAdmin("admins", "USER1")
+	assert.Error(t, err)
+
+	first, err := db.CreateAdmin(model.Admin{WorkspaceID: "admins", UserID: "USER1"})
+	require.NoError(t, err)
+
+	_, err = db.CreateAdmin(model.Admin{WorkspaceID: "admins", UserID: "USER1"})
+	assert.Error(t, err)
+
+	second, err := db.CreateAdmin(model.Admin{WorkspaceID: "admins", UserID: "USER2"})
+	require.NoError(t, err)
+
+	other, err := db.CreateAdmin(model.Admin{WorkspaceID: "otherAdmins", UserID: "USER1"})
+	require.NoError(t, err)
+
+	admin, err := db.FindAdmin("admins", "USER1")
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, admin.ID)
+
+	admins, err := db.ListWorkspaceAdmins("admins")
+	require.NoError(t, err)
+	require.Equal(t, 2, len(admins))
+	assert.Equal(t, first.ID, admins[0].ID)
+	assert.Equal(t, second.ID, admins[1].ID)
+
+	assert.NoError(t, db.DeleteAdmin(first.ID))
+	assert.NoError(t, db.DeleteAdmin(second.ID))
+	assert.NoError(t, db.DeleteAdmin(other.ID))
+
+	admins, err = db.ListWorkspaceAdmins("admins")
+	require.NoError(t, err)
+	assert.Equal(t, 0, len(admins))
+}
@@ -0,0 +1,87 @@
+package storage
+
+import (
+	"github.com/maddevsio/comedian/model"
+)
+
+// CreateBlocker creates blocker entry in database
+func (m *DB) CreateBlocker(b model.Blocker) (model.Blocker, error) {
+	err := b.Validate()
+	if err != nil {
+		return b, err
+	}
+
+	id, err := m.insert(
+		`INSERT INTO blockers (
+			created_at,
+			workspace_id,
+			channel_id,
+			user_id,
+			standup_id,
+			text,
+			resolved,
+			resolved_at,
+			resolved_by
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		b.CreatedAt,
+		b.WorkspaceID,
+		b.ChannelID,
+		b.UserID,
+		b.StandupID,
+		b.Text,
+		b.Resolved,
+		b.ResolvedAt,
+		b.ResolvedBy,
+	)
+	if err != nil {
+		return b, err
+	}
+	b.ID = id
+
+	return b, nil
+}
+
+// UpdateBlocker updates blocker text and resolution
+func (m *DB) UpdateBlocker(b model.Blocker) (model.Blocker, error) {
+	err := b.Validate()
+	if err != nil {
+		return b, err
+	}
+
+	_, err = m.exec(
+		"UPDATE blockers SET standup_id=?, text=?, resolved=?, resolved_at=?, resolved_by=? WHERE id=?",
+		b.StandupID, b.Text, b.Resolved, b.ResolvedAt, b.ResolvedBy, b.ID,
+	)
+	if err != nil {
+		return b, err
+	}
+
+	return m.GetBlocker(b.ID)
+}
+
+// GetBlocker selects blocker entry from database
+func (m *DB) GetBlocker(id int64) (model.Blocker, error) {
+	var b model.Blocker
+	err := m.get(&b, "SELECT * FROM blockers WHERE id=?", id)
+	return b, err
+}
+
+// ListTeamBlockers returns all workspace blockers, newest first
+func (m *DB) ListTeamBlockers(teamID string) ([]model.Blocker, error) {
+	items := []model.Blocker{}
+	err := m.list(&items, "SELECT * FROM blockers WHERE workspace_id=? order by id desc", teamID)
+	return items, err
+}
+
+// ListOpenBlockers returns blockers of the channel that are not resolved yet
+func (m *DB) ListOpenBlockers(channelID string) ([]model.Blocker, error) {
+	items := []model.Blocker{}
+	err := m.list(&items, "SELECT * FROM blockers WHERE channel_id=? AND resolved=? order by id", channelID, false)
+	return items, err
+}
+
+// DeleteBlocker deletes blocker entry from database
+func (m *DB) DeleteBlocker(id int64) error {
+	_, err := m.exec("DELETE FROM blockers WHERE id=?", id)
+	return err
+}
@@ -0,0 +1,54 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/maddevsio/comedian/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockers(t *testing.T) {
+	_, err := db.CreateBlocker(model.Blocker{})
+	assert.Error(t, err)
+
+	b, err := db.CreateBlocker(model.Blocker{
+		CreatedAt:   time.Now().Unix(),
+		WorkspaceID: "foo",
+		ChannelID:   "blockers12",
+		UserID:      "bar",
+		StandupID:   1,
+		Text:        "CI is down",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "CI is down", b.Text)
+
+	blockers, err := db.ListOpenBlockers("blockers12")
+	require.NoError(t, err)
+	assert.Equal(t, 1, len(blockers))
+
+	b.Resolved = true
+	b.ResolvedAt = time.Now().Unix()
+	b.ResolvedBy = "pm"
+	_, err = db.UpdateBlocker(b)
+	require.NoError(t, err)
+
+	b, err = db.GetBlocker(b.ID)
+	require.NoError(t, err)
+	assert.Equal(t, true, b.Resolved)
+	assert.Equal(t, "pm", b.ResolvedBy)
+
+	blockers, err = db.ListOpenBlockers("blockers12")
+	require.NoError(t, err)
+	assert.Equal(t, 0, len(blockers))
+
+	blockers, err = db.ListTeamBlockers("foo")
+	require.NoError(t, err)
+	assert.Equal(t, 1, len(blockers))
+
+	assert.NoError(t, db.DeleteBlocker(b.ID))
+
+	_, err = db.GetBlocker(b.ID)
+	assert.Error(t, err)
+}
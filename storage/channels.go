@@ -11,7 +11,7 @@ func (m *DB) CreateProject(ch model.Project) (model.Project, error) {
 		return ch, err
 	}
 
-	res, err := m.db.Exec(
+	id, err := m.insert(
 		`INSERT INTO projects (
 			created_at,
 			workspace_id, 
@@ -20,8 +20,15 @@ func (m *DB) CreateProject(ch model.Project) (model.Project, error) {
 			deadline,
 			tz,
 			onbording_message,
-			submission_days
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			submission_days,
+			yesterday_keywords,
+			today_keywords,
+			problem_keywords,
+			optional_sections,
+			dm_mode,
+			dm_prompt_offset,
+			work_project
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		ch.CreatedAt,
 		ch.WorkspaceID,
 		ch.ChannelName,
@@ -30,14 +37,17 @@ func (m *DB) CreateProject(ch model.Project) (model.Project, error) {
 		ch.TZ,
 		ch.OnbordingMessage,
 		ch.SubmissionDays,
+		ch.YesterdayKeywords,
+		ch.TodayKeywords,
+		ch.ProblemKeywords,
+		ch.OptionalSections,
+		ch.DMMode,
+		ch.DMPromptOffset,
+		ch.WorkProject,
 	)
 	if err != nil {
 		return ch, err
 	}
-	id, err := res.LastInsertId()
-	if err != nil {
-		return ch, err
-	}
 	ch.ID = id
 
 	return ch, nil
@@ -49,43 +59,57 @@ func (m *DB) UpdateProject(ch model.Project) (model.Project, error) {
 	if err != nil {
 		return ch, err
 	}
-	_, err = m.db.Exec(
+	_, err = m.exec(
 		`UPDATE projects SET 
 		deadline=?,
 		tz=?,
 		onbording_message=?,
-		submission_days=? 
+		submission_days=?,
+		yesterday_keywords=?,
+		today_keywords=?,
+		problem_keywords=?,
+		optional_sections=?,
+		dm_mode=?,
+		dm_prompt_offset=?,
+		work_project=?
 		WHERE id=?`,
 		ch.Deadline,
 		ch.TZ,
 		ch.OnbordingMessage,
 		ch.SubmissionDays,
+		ch.YesterdayKeywords,
+		ch.TodayKeywords,
+		ch.ProblemKeywords,
+		ch.OptionalSections,
+		ch.DMMode,
+		ch.DMPromptOffset,
+		ch.WorkProject,
 		ch.ID,
 	)
 	if err != nil {
 		return ch, err
 	}
-	return ch, nil
+	return m.GetProject(ch.ID)
 }
 
 //ListProjects returns list of projects
 func (m *DB) ListProjects() ([]model.Project, error) {
 	projects := []model.Project{}
-	err := m.db.Select(&projects, "SELECT * FROM `projects`")
+	err := m.list(&projects, "SELECT * FROM projects")
 	return projects, err
 }
 
 //ListWorkspaceProjects returns list of projects
 func (m *DB) ListWorkspaceProjects(ws string) ([]model.Project, error) {
 	projects := []model.Project{}
-	err := m.db.Select(&projects, "SELECT * FROM `projects` where workspace_id=?", ws)
+	err := m.list(&projects, "SELECT * FROM projects where workspace_id=?", ws)
 	return projects, err
 }
 
 // SelectProject selects Project entry from database
 func (m *DB) SelectProject(channelID string) (model.Project, error) {
 	var c model.Project
-	err := m.db.Get(&c, "SELECT * FROM `projects` WHERE channel_id=?", channelID)
+	err := m.get(&c, "SELECT * FROM projects WHERE channel_id=?", channelID)
 	if err != nil {
 		return c, err
 	}
@@ -95,7 +119,7 @@ func (m *DB) SelectProject(channelID string) (model.Project, error) {
 // GetProject selects Project entry from database with specific id
 func (m *DB) GetProject(id int64) (model.Project, error) {
 	var c model.Project
-	err := m.db.Get(&c, "SELECT * FROM `projects` where id=?", id)
+	err := m.get(&c, "SELECT * FROM projects where id=?", id)
 	if err != nil {
 		return c, err
 	}
@@ -104,6 +128,6 @@ func (m *DB) GetProject(id int64) (model.Project, error) {
 
 // DeleteProject deletes Project entry from database
 func (m *DB) DeleteProject(id int64) error {
-	_, err := m.db.Exec("DELETE FROM `projects` WHERE id=?", id)
+	_, err := m.exec("DELETE FROM projects WHERE id=?", id)
 	return err
 }
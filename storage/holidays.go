@@ -0,0 +1,56 @@
+package storage
+
+import (
+	"github.com/maddevsio/comedian/model"
+)
+
+// CreateHoliday creates holiday entry in database
+func (m *DB) CreateHoliday(h model.Holiday) (model.Holiday, error) {
+	err := h.Validate()
+	if err != nil {
+		return h, err
+	}
+
+	id, err := m.insert(
+		"INSERT INTO holidays (created_at, workspace_id, channel_id, date, name) VALUES (?, ?, ?, ?, ?)",
+		h.CreatedAt, h.WorkspaceID, h.ChannelID, h.Date, h.Name,
+	)
+	if err != nil {
+		return h, err
+	}
+	h.ID = id
+
+	return h, nil
+}
+
+// GetHoliday selects holiday entry from database
+func (m *DB) GetHoliday(id int64) (model.Holiday, error) {
+	var h model.Holiday
+	err := m.get(&h, "SELECT * FROM holidays WHERE id=?", id)
+	return h, err
+}
+
+// ListTeamHolidays returns all workspace and project holidays of the workspace
+func (m *DB) ListTeamHolidays(teamID string) ([]model.Holiday, error) {
+	items := []model.Holiday{}
+	err := m.list(&items, "SELECT * FROM holidays WHERE workspace_id=? order by date", teamID)
+	return items, err
+}
+
+// FindHoliday returns holiday of the workspace or the channel on date
+func (m *DB) FindHoliday(teamID, channelID, date string) (model.Holiday, error) {
+	var h model.Holiday
+	err := m.get(&h,
+		`SELECT * FROM holidays 
+		WHERE workspace_id=? AND (channel_id='' OR channel_id=?) AND date=? 
+		order by id limit 1`,
+		teamID, channelID, date,
+	)
+	return h, err
+}
+
+// DeleteHoliday deletes holiday entry from database
+func (m *DB) DeleteHoliday(id int64) error {
+	_, err := m.exec("DELETE FROM holidays WHERE id=?", id)
+	return err
+}
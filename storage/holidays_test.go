@@ -0,0 +1,40 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/maddevsio/comedian/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHolidays(t *testing.T) {
+	_, err := db.CreateHoliday(model.Holiday{WorkspaceID: "foo", Date: "01.01.2026"})
+	assert.Error(t, err)
+
+	workspaceHoliday, err := db.CreateHoliday(model.Holiday{WorkspaceID: "holidays", Date: "2026-01-01", Name: "New Year"})
+	require.NoError(t, err)
+
+	projectHoliday, err := db.CreateHoliday(model.Holiday{WorkspaceID: "holidays", ChannelID: "chan1", Date: "2026-01-07", Name: "Christmas"})
+	require.NoError(t, err)
+
+	h, err := db.FindHoliday("holidays", "chan2", "2026-01-01")
+	require.NoError(t, err)
+	assert.Equal(t, "New Year", h.Name)
+
+	_, err = db.FindHoliday("holidays", "chan2", "2026-01-07")
+	assert.Error(t, err)
+
+	_, err = db.FindHoliday("holidays", "chan1", "2026-01-07")
+	assert.NoError(t, err)
+
+	holidays, err := db.ListTeamHolidays("holidays")
+	require.NoError(t, err)
+	assert.Equal(t, 2, len(holidays))
+
+	assert.NoError(t, db.DeleteHoliday(workspaceHoliday.ID))
+	assert.NoError(t, db.DeleteHoliday(projectHoliday.ID))
+
+	_, err = db.GetHoliday(projectHoliday.ID)
+	assert.Error(t, err)
+}
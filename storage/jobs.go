@@ -0,0 +1,54 @@
+package storage
+
+import (
+	"github.com/maddevsio/comedian/model"
+)
+
+// CreateJob creates job entry in database
+func (m *DB) CreateJob(j model.Job) (model.Job, error) {
+	err := j.Validate()
+	if err != nil {
+		return j, err
+	}
+
+	id, err := m.insert(
+		"INSERT INTO jobs (workspace_id, name, last_run) VALUES (?, ?, ?)",
+		j.WorkspaceID, j.Name, j.LastRun,
+	)
+	if err != nil {
+		return j, err
+	}
+	j.ID = id
+
+	return j, nil
+}
+
+// FindJob selects job of the workspace by its name
+func (m *DB) FindJob(workspaceID, name string) (model.Job, error) {
+	var j model.Job
+	err := m.get(&j, "SELECT * FROM jobs WHERE workspace_id=? AND name=?", workspaceID, name)
+	return j, err
+}
+
+// ClaimJob moves last run of the job to lastRun only if nobody has moved it
+// since the job was selected. Returns false if the job was claimed by someone else
+func (m *DB) ClaimJob(j model.Job, lastRun int64) (bool, error) {
+	res, err := m.exec(
+		"UPDATE jobs SET last_run=? WHERE id=? AND last_run=?",
+		lastRun, j.ID, j.LastRun,
+	)
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+// DeleteJob deletes job entry from database
+func (m *DB) DeleteJob(id int64) error {
+	_, err := m.exec("DELETE FROM jobs WHERE id=?", id)
+	return err
+}
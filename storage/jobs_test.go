@@ -0,0 +1,37 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/maddevsio/comedian/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobs(t *testing.T) {
+	_, err := db.CreateJob(model.Job{WorkspaceID: "jobs"})
+	assert.Error(t, err)
+
+	_, err = db.FindJob("jobs", "daily_report")
+	assert.Error(t, err)
+
+	job, err := db.CreateJob(model.Job{WorkspaceID: "jobs", Name: "daily_report", LastRun: 100})
+	require.NoError(t, err)
+
+	_, err = db.CreateJob(model.Job{WorkspaceID: "jobs", Name: "daily_report", LastRun: 100})
+	assert.Error(t, err)
+
+	claimed, err := db.ClaimJob(job, 200)
+	require.NoError(t, err)
+	assert.True(t, claimed)
+
+	claimed, err = db.ClaimJob(job, 300)
+	require.NoError(t, err)
+	assert.False(t, claimed)
+
+	job, err = db.FindJob("jobs", "daily_report")
+	require.NoError(t, err)
+	assert.Equal(t, int64(200), job.LastRun)
+
+	assert.NoError(t, db.DeleteJob(job.ID))
+}
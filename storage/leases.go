@@ -0,0 +1,48 @@
+package storage
+
+import (
+	"github.com/maddevsio/comedian/model"
+)
+
+// AcquireLease takes or renews lease for owner until the given time.
+// Lease is taken if it does not exist, belongs to owner already or has expired
+// by now. Returns false if the lease is held by someone else
+func (m *DB) AcquireLease(name, owner string, now, until int64) (bool, error) {
+	res, err := m.exec(
+		"UPDATE leases SET owner=?, expires_at=? WHERE name=? AND (owner=? OR expires_at<?)",
+		owner, until, name, owner, now,
+	)
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if affected == 1 {
+		return true, nil
+	}
+
+	_, err = m.insert(
+		"INSERT INTO leases (name, owner, expires_at) VALUES (?, ?, ?)",
+		name, owner, until,
+	)
+	if err == nil {
+		return true, nil
+	}
+
+	// either the lease is held by someone else or MySQL reported no rows
+	// changed since owner renewed it with the same expiration
+	var l model.Lease
+	err = m.get(&l, "SELECT * FROM leases WHERE name=?", name)
+	if err != nil {
+		return false, err
+	}
+	return l.Owner == owner && l.ExpiresAt >= now, nil
+}
+
+// ReleaseLease gives lease up if it is held by owner
+func (m *DB) ReleaseLease(name, owner string) error {
+	_, err := m.exec("DELETE FROM leases WHERE name=? AND owner=?", name, owner)
+	return err
+}
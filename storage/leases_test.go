@@ -0,0 +1,48 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLeases(t *testing.T) {
+	held, err := db.AcquireLease("leases", "first", 100, 190)
+	require.NoError(t, err)
+	assert.True(t, held)
+
+	held, err = db.AcquireLease("leases", "second", 150, 240)
+	require.NoError(t, err)
+	assert.False(t, held)
+
+	held, err = db.AcquireLease("leases", "first", 160, 250)
+	require.NoError(t, err)
+	assert.True(t, held)
+
+	held, err = db.AcquireLease("leases", "first", 160, 250)
+	require.NoError(t, err)
+	assert.True(t, held)
+
+	held, err = db.AcquireLease("leases", "second", 260, 350)
+	require.NoError(t, err)
+	assert.True(t, held)
+
+	held, err = db.AcquireLease("leases", "first", 270, 360)
+	require.NoError(t, err)
+	assert.False(t, held)
+
+	require.NoError(t, db.ReleaseLease("leases", "first"))
+
+	held, err = db.AcquireLease("leases", "first", 280, 370)
+	require.NoError(t, err)
+	assert.False(t, held)
+
+	require.NoError(t, db.ReleaseLease("leases", "second"))
+
+	held, err = db.AcquireLease("leases", "first", 290, 380)
+	require.NoError(t, err)
+	assert.True(t, held)
+
+	assert.NoError(t, db.ReleaseLease("leases", "first"))
+}
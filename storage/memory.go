@@ -0,0 +1,1233 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/maddevsio/comedian/model"
+	"github.com/maddevsio/comedian/secret"
+)
+
+// Memory is an in-memory Store implementation. It keeps all the data
+// in process and is meant for tests and running Comedian without database server
+type Memory struct {
+	mu                  sync.Mutex
+	lastID              int64
+	standups            map[int64]model.Standup
+	standupers          map[int64]model.Standuper
+	projects            map[int64]model.Project
+	workspaces          map[int64]model.Workspace
+	notificationThreads map[int64]model.NotificationThread
+	blockers            map[int64]model.Blocker
+	absences            map[int64]model.Absence
+	holidays            map[int64]model.Holiday
+	jobs                map[int64]model.Job
+	leases              map[string]model.Lease
+	standupDrafts       map[int64]model.StandupDraft
+	admins              map[int64]model.Admin
+	webhooks            map[int64]model.Webhook
+	webhookDeliveries   map[int64]model.WebhookDelivery
+}
+
+// NewMemory creates empty in-memory store
+func NewMemory() *Memory {
+	return &Memory{
+		standups:            map[int64]model.Standup{},
+		standupers:          map[int64]model.Standuper{},
+		projects:            map[int64]model.Project{},
+		workspaces:          map[int64]model.Workspace{},
+		notificationThreads: map[int64]model.NotificationThread{},
+		blockers:            map[int64]model.Blocker{},
+		absences:            map[int64]model.Absence{},
+		holidays:            map[int64]model.Holiday{},
+		jobs:                map[int64]model.Job{},
+		leases:              map[string]model.Lease{},
+		standupDrafts:       map[int64]model.StandupDraft{},
+		admins:              map[int64]model.Admin{},
+		webhooks:            map[int64]model.Webhook{},
+		webhookDeliveries:   map[int64]model.WebhookDelivery{},
+	}
+}
+
+func (m *Memory) nextID() int64 {
+	m.lastID++
+	return m.lastID
+}
+
+// sortedIDs returns ids passed to add by collect in ascending order, so that
+// entities are listed in the order they were created, like in SQL storage
+func sortedIDs(collect func(add func(id int64))) []int64 {
+	ids := []int64{}
+	collect(func(id int64) { ids = append(ids, id) })
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+func (m *Memory) standupIDs() []int64 {
+	return sortedIDs(func(add func(int64)) {
+		for id := range m.standups {
+			add(id)
+		}
+	})
+}
+
+func (m *Memory) standuperIDs() []int64 {
+	return sortedIDs(func(add func(int64)) {
+		for id := range m.standupers {
+			add(id)
+		}
+	})
+}
+
+func (m *Memory) projectIDs() []int64 {
+	return sortedIDs(func(add func(int64)) {
+		for id := range m.projects {
+			add(id)
+		}
+	})
+}
+
+func (m *Memory) workspaceIDs() []int64 {
+	return sortedIDs(func(add func(int64)) {
+		for id := range m.workspaces {
+			add(id)
+		}
+	})
+}
+
+func (m *Memory) blockerIDs() []int64 {
+	return sortedIDs(func(add func(int64)) {
+		for id := range m.blockers {
+			add(id)
+		}
+	})
+}
+
+func (m *Memory) standupDraftIDs() []int64 {
+	return sortedIDs(func(add func(int64)) {
+		for id := range m.standupDrafts {
+			add(id)
+		}
+	})
+}
+
+func (m *Memory) adminIDs() []int64 {
+	return sortedIDs(func(add func(int64)) {
+		for id := range m.admins {
+			add(id)
+		}
+	})
+}
+
+func (m *Memory) webhookIDs() []int64 {
+	return sortedIDs(func(add func(int64)) {
+		for id := range m.webhooks {
+			add(id)
+		}
+	})
+}
+
+func (m *Memory) webhookDeliveryIDs() []int64 {
+	return sortedIDs(func(add func(int64)) {
+		for id := range m.webhookDeliveries {
+			add(id)
+		}
+	})
+}
+
+func (m *Memory) absenceIDs() []int64 {
+	return sortedIDs(func(add func(int64)) {
+		for id := range m.absences {
+			add(id)
+		}
+	})
+}
+
+func (m *Memory) holidayIDs() []int64 {
+	return sortedIDs(func(add func(int64)) {
+		for id := range m.holidays {
+			add(id)
+		}
+	})
+}
+
+func (m *Memory) jobIDs() []int64 {
+	return sortedIDs(func(add func(int64)) {
+		for id := range m.jobs {
+			add(id)
+		}
+	})
+}
+
+func (m *Memory) notificationThreadIDs() []int64 {
+	return sortedIDs(func(add func(int64)) {
+		for id := range m.notificationThreads {
+			add(id)
+		}
+	})
+}
+
+// CreateStandup creates standup entry in memory
+func (m *Memory) CreateStandup(s model.Standup) (model.Standup, error) {
+	err := s.Validate()
+	if err != nil {
+		return s, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s.ID = m.nextID()
+	m.standups[s.ID] = s
+	return s, nil
+}
+
+// UpdateStandup updates standup entry in memory
+func (m *Memory) UpdateStandup(s model.Standup) (model.Standup, error) {
+	err := s.Validate()
+	if err != nil {
+		return s, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	i, ok := m.standups[s.ID]
+	if !ok {
+		return i, sql.ErrNoRows
+	}
+	i.Comment = s.Comment
+	i.Done = s.Done
+	i.Planned = s.Planned
+	i.Blockers = s.Blockers
+	i.MessageTS = s.MessageTS
+	m.standups[s.ID] = i
+	return i, nil
+}
+
+// ListStandups returns array of standup entries, newest first
+func (m *Memory) ListStandups() ([]model.Standup, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	items := []model.Standup{}
+	ids := m.standupIDs()
+	for i := len(ids) - 1; i >= 0; i-- {
+		items = append(items, m.standups[ids[i]])
+	}
+	return items, nil
+}
+
+// ListTeamStandups returns array of workspace standup entries, newest first
+func (m *Memory) ListTeamStandups(teamID string) ([]model.Standup, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	items := []model.Standup{}
+	ids := m.standupIDs()
+	for i := len(ids) - 1; i >= 0; i-- {
+		if m.standups[ids[i]].WorkspaceID == teamID {
+			items = append(items, m.standups[ids[i]])
+		}
+	}
+	return items, nil
+}
+
+// ListTeamStandupsForPeriod returns array of workspace standups created in the given period, newest first
+func (m *Memory) ListTeamStandupsForPeriod(teamID string, timeFrom, timeTo int64) ([]model.Standup, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	items := []model.Standup{}
+	ids := m.standupIDs()
+	for i := len(ids) - 1; i >= 0; i-- {
+		s := m.standups[ids[i]]
+		if s.WorkspaceID == teamID && s.CreatedAt >= timeFrom && s.CreatedAt <= timeTo {
+			items = append(items, s)
+		}
+	}
+	return items, nil
+}
+
+//GetStandup returns standup by its ID
+func (m *Memory) GetStandup(id int64) (model.Standup, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.standups[id]
+	if !ok {
+		return s, sql.ErrNoRows
+	}
+	return s, nil
+}
+
+// SelectStandupByMessageTS selects standup entry filtered by MessageTS parameter
+func (m *Memory) SelectStandupByMessageTS(messageTS string) (model.Standup, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, id := range m.standupIDs() {
+		if m.standups[id].MessageTS == messageTS {
+			return m.standups[id], nil
+		}
+	}
+	return model.Standup{}, sql.ErrNoRows
+}
+
+// SelectLatestStandupByUser selects the latest standup of user in channel
+func (m *Memory) SelectLatestStandupByUser(userID, channelID string) (model.Standup, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ids := m.standupIDs()
+	for i := len(ids) - 1; i >= 0; i-- {
+		s := m.standups[ids[i]]
+		if s.UserID == userID && s.ChannelID == channelID {
+			return s, nil
+		}
+	}
+	return model.Standup{}, sql.ErrNoRows
+}
+
+// GetStandupForPeriod selects standup of user in channel created in the given period
+func (m *Memory) GetStandupForPeriod(userID, channelID string, timeFrom, timeTo int64) (*model.Standup, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, id := range m.standupIDs() {
+		s := m.standups[id]
+		if s.UserID == userID && s.ChannelID == channelID && s.CreatedAt >= timeFrom && s.CreatedAt <= timeTo {
+			return &s, nil
+		}
+	}
+	return &model.Standup{}, sql.ErrNoRows
+}
+
+// DeleteStandup deletes standup entry
+func (m *Memory) DeleteStandup(id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.standups, id)
+	return nil
+}
+
+// CreateStanduper creates standuper entry in memory
+func (m *Memory) CreateStanduper(s model.Standuper) (model.Standuper, error) {
+	err := s.Validate()
+	if err != nil {
+		return s, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s.ID = m.nextID()
+	m.standupers[s.ID] = s
+	return s, nil
+}
+
+// UpdateStanduper updates Standuper entry in memory
+func (m *Memory) UpdateStanduper(st model.Standuper) (model.Standuper, error) {
+	err := st.Validate()
+	if err != nil {
+		return st, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	i, ok := m.standupers[st.ID]
+	if !ok {
+		return i, sql.ErrNoRows
+	}
+	i.Role = st.Role
+	i.TZ = st.TZ
+	i.Deadline = st.Deadline
+	i.WorkAccount = st.WorkAccount
+	m.standupers[st.ID] = i
+	return i, nil
+}
+
+//FindStansuperByUserID finds user in channel
+func (m *Memory) FindStansuperByUserID(userID, channelID string) (model.Standuper, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, id := range m.standuperIDs() {
+		s := m.standupers[id]
+		if s.UserID == userID && s.ChannelID == channelID {
+			return s, nil
+		}
+	}
+	return model.Standuper{}, sql.ErrNoRows
+}
+
+//FindStansupersByUserID finds user in all the channels
+func (m *Memory) FindStansupersByUserID(userID string) ([]model.Standuper, error) {
+	return m.filterStandupers(func(s model.Standuper) bool { return s.UserID == userID }), nil
+}
+
+// ListStandupers returns array of standupers
+func (m *Memory) ListStandupers() ([]model.Standuper, error) {
+	return m.filterStandupers(func(s model.Standuper) bool { return true }), nil
+}
+
+// ListWorkspaceStandupers returns array of workspace standupers
+func (m *Memory) ListWorkspaceStandupers(workspaceID string) ([]model.Standuper, error) {
+	return m.filterStandupers(func(s model.Standuper) bool { return s.WorkspaceID == workspaceID }), nil
+}
+
+//GetStanduper returns a standuper
+func (m *Memory) GetStanduper(id int64) (model.Standuper, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.standupers[id]
+	if !ok {
+		return s, sql.ErrNoRows
+	}
+	return s, nil
+}
+
+// ListProjectStandupers returns array of channel standupers
+func (m *Memory) ListProjectStandupers(channelID string) ([]model.Standuper, error) {
+	return m.filterStandupers(func(s model.Standuper) bool { return s.ChannelID == channelID }), nil
+}
+
+// ListStandupersByWorkspaceID returns array of standupers which belongs to one team
+func (m *Memory) ListStandupersByWorkspaceID(wsID string) ([]model.Standuper, error) {
+	return m.ListWorkspaceStandupers(wsID)
+}
+
+// DeleteStanduper deletes standuper entry
+func (m *Memory) DeleteStanduper(id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.standupers, id)
+	return nil
+}
+
+func (m *Memory) filterStandupers(match func(model.Standuper) bool) []model.Standuper {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	items := []model.Standuper{}
+	for _, id := range m.standuperIDs() {
+		if match(m.standupers[id]) {
+			items = append(items, m.standupers[id])
+		}
+	}
+	return items
+}
+
+// CreateProject creates project entry in memory
+func (m *Memory) CreateProject(ch model.Project) (model.Project, error) {
+	err := ch.Validate()
+	if err != nil {
+		return ch, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ch.ID = m.nextID()
+	m.projects[ch.ID] = ch
+	return ch, nil
+}
+
+// UpdateProject updates Project entry in memory
+func (m *Memory) UpdateProject(ch model.Project) (model.Project, error) {
+	err := ch.Validate()
+	if err != nil {
+		return ch, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	i, ok := m.projects[ch.ID]
+	if !ok {
+		return ch, sql.ErrNoRows
+	}
+	i.Deadline = ch.Deadline
+	i.TZ = ch.TZ
+	i.OnbordingMessage = ch.OnbordingMessage
+	i.SubmissionDays = ch.SubmissionDays
+	i.YesterdayKeywords = ch.YesterdayKeywords
+	i.TodayKeywords = ch.TodayKeywords
+	i.ProblemKeywords = ch.ProblemKeywords
+	i.OptionalSections = ch.OptionalSections
+	i.DMMode = ch.DMMode
+	i.DMPromptOffset = ch.DMPromptOffset
+	i.WorkProject = ch.WorkProject
+	m.projects[ch.ID] = i
+	return i, nil
+}
+
+//ListProjects returns list of projects
+func (m *Memory) ListProjects() ([]model.Project, error) {
+	return m.filterProjects(func(p model.Project) bool { return true }), nil
+}
+
+//ListWorkspaceProjects returns list of workspace projects
+func (m *Memory) ListWorkspaceProjects(ws string) ([]model.Project, error) {
+	return m.filterProjects(func(p model.Project) bool { return p.WorkspaceID == ws }), nil
+}
+
+// SelectProject selects Project entry by channel ID
+func (m *Memory) SelectProject(channelID string) (model.Project, error) {
+	projects := m.filterProjects(func(p model.Project) bool { return p.ChannelID == channelID })
+	if len(projects) == 0 {
+		return model.Project{}, sql.ErrNoRows
+	}
+	return projects[0], nil
+}
+
+// GetProject selects Project entry with specific id
+func (m *Memory) GetProject(id int64) (model.Project, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	p, ok := m.projects[id]
+	if !ok {
+		return p, sql.ErrNoRows
+	}
+	return p, nil
+}
+
+// DeleteProject deletes Project entry
+func (m *Memory) DeleteProject(id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.projects, id)
+	return nil
+}
+
+func (m *Memory) filterProjects(match func(model.Project) bool) []model.Project {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	items := []model.Project{}
+	for _, id := range m.projectIDs() {
+		if match(m.projects[id]) {
+			items = append(items, m.projects[id])
+		}
+	}
+	return items
+}
+
+//CreateWorkspace creates workspace entry in memory
+func (m *Memory) CreateWorkspace(bs model.Workspace) (model.Workspace, error) {
+	err := bs.Validate()
+	if err != nil {
+		return bs, err
+	}
+	if bs.Platform == "" {
+		bs.Platform = model.PlatformSlack
+	}
+	bs.BotTokenHash = secret.Hash(bs.BotAccessToken)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	bs.ID = m.nextID()
+	m.workspaces[bs.ID] = bs
+	return bs, nil
+}
+
+//UpdateWorkspace updates workspace entry in memory
+func (m *Memory) UpdateWorkspace(settings model.Workspace) (model.Workspace, error) {
+	err := settings.Validate()
+	if err != nil {
+		return settings, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if settings.Platform == "" {
+		settings.Platform = model.PlatformSlack
+	}
+	i, ok := m.workspaces[settings.ID]
+	if !ok {
+		return settings, sql.ErrNoRows
+	}
+	i.NotifierInterval = settings.NotifierInterval
+	i.MaxReminders = settings.MaxReminders
+	i.WorkspaceID = settings.WorkspaceID
+	i.ReminderOffset = settings.ReminderOffset
+	i.WorkspaceName = settings.WorkspaceName
+	i.BotAccessToken = settings.BotAccessToken
+	i.BotUserID = settings.BotUserID
+	i.ProjectsReportsEnabled = settings.ProjectsReportsEnabled
+	i.ReportingChannel = settings.ReportingChannel
+	i.ReportingTime = settings.ReportingTime
+	i.Language = settings.Language
+	i.Platform = settings.Platform
+	i.BotTokenHash = secret.Hash(settings.BotAccessToken)
+	i.WorkSource = settings.WorkSource
+	i.WorkSourceURL = settings.WorkSourceURL
+	i.WorkSourceToken = settings.WorkSourceToken
+	i.ScoringRules = settings.ScoringRules
+	m.workspaces[settings.ID] = i
+	return i, nil
+}
+
+//GetAllWorkspaces returns all workspaces
+func (m *Memory) GetAllWorkspaces() ([]model.Workspace, error) {
+	return m.filterWorkspaces(func(w model.Workspace) bool { return true }), nil
+}
+
+//GetWorkspaceByWorkspaceID returns a particular workspace
+func (m *Memory) GetWorkspaceByWorkspaceID(workspaceID string) (model.Workspace, error) {
+	return m.findWorkspace(func(w model.Workspace) bool { return w.WorkspaceID == workspaceID })
+}
+
+//GetWorkspaceByBotAccessToken returns a particular workspace
+func (m *Memory) GetWorkspaceByBotAccessToken(botAccessToken string) (model.Workspace, error) {
+	hash := secret.Hash(botAccessToken)
+	return m.findWorkspace(func(w model.Workspace) bool { return w.BotTokenHash == hash })
+}
+
+//GetWorkspace returns a particular workspace
+func (m *Memory) GetWorkspace(id int64) (model.Workspace, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	w, ok := m.workspaces[id]
+	if !ok {
+		return w, sql.ErrNoRows
+	}
+	return w, nil
+}
+
+//DeleteWorkspaceByID deletes workspace
+func (m *Memory) DeleteWorkspaceByID(id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.workspaces, id)
+	return nil
+}
+
+//DeleteWorkspace deletes workspace
+func (m *Memory) DeleteWorkspace(teamID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, w := range m.workspaces {
+		if w.WorkspaceID == teamID {
+			delete(m.workspaces, id)
+		}
+	}
+	return nil
+}
+
+func (m *Memory) filterWorkspaces(match func(model.Workspace) bool) []model.Workspace {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	items := []model.Workspace{}
+	for _, id := range m.workspaceIDs() {
+		if match(m.workspaces[id]) {
+			items = append(items, m.workspaces[id])
+		}
+	}
+	return items
+}
+
+func (m *Memory) findWorkspace(match func(model.Workspace) bool) (model.Workspace, error) {
+	workspaces := m.filterWorkspaces(match)
+	if len(workspaces) == 0 {
+		return model.Workspace{}, sql.ErrNoRows
+	}
+	return workspaces[0], nil
+}
+
+// CreateBlocker creates blocker entry in memory
+func (m *Memory) CreateBlocker(b model.Blocker) (model.Blocker, error) {
+	err := b.Validate()
+	if err != nil {
+		return b, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	b.ID = m.nextID()
+	m.blockers[b.ID] = b
+	return b, nil
+}
+
+// UpdateBlocker updates blocker text and resolution
+func (m *Memory) UpdateBlocker(b model.Blocker) (model.Blocker, error) {
+	err := b.Validate()
+	if err != nil {
+		return b, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	i, ok := m.blockers[b.ID]
+	if !ok {
+		return b, sql.ErrNoRows
+	}
+	i.StandupID = b.StandupID
+	i.Text = b.Text
+	i.Resolved = b.Resolved
+	i.ResolvedAt = b.ResolvedAt
+	i.ResolvedBy = b.ResolvedBy
+	m.blockers[b.ID] = i
+	return i, nil
+}
+
+// GetBlocker returns blocker by its ID
+func (m *Memory) GetBlocker(id int64) (model.Blocker, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	b, ok := m.blockers[id]
+	if !ok {
+		return b, sql.ErrNoRows
+	}
+	return b, nil
+}
+
+// ListTeamBlockers returns all workspace blockers, newest first
+func (m *Memory) ListTeamBlockers(teamID string) ([]model.Blocker, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	items := []model.Blocker{}
+	ids := m.blockerIDs()
+	for i := len(ids) - 1; i >= 0; i-- {
+		if m.blockers[ids[i]].WorkspaceID == teamID {
+			items = append(items, m.blockers[ids[i]])
+		}
+	}
+	return items, nil
+}
+
+// ListOpenBlockers returns blockers of the channel that are not resolved yet
+func (m *Memory) ListOpenBlockers(channelID string) ([]model.Blocker, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	items := []model.Blocker{}
+	for _, id := range m.blockerIDs() {
+		b := m.blockers[id]
+		if b.ChannelID == channelID && !b.Resolved {
+			items = append(items, b)
+		}
+	}
+	return items, nil
+}
+
+// DeleteBlocker deletes blocker entry
+func (m *Memory) DeleteBlocker(id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.blockers, id)
+	return nil
+}
+
+// CreateAbsence creates absence entry in memory
+func (m *Memory) CreateAbsence(a model.Absence) (model.Absence, error) {
+	err := a.Validate()
+	if err != nil {
+		return a, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	a.ID = m.nextID()
+	m.absences[a.ID] = a
+	return a, nil
+}
+
+// UpdateAbsence updates absence period and reason
+func (m *Memory) UpdateAbsence(a model.Absence) (model.Absence, error) {
+	err := a.Validate()
+	if err != nil {
+		return a, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	i, ok := m.absences[a.ID]
+	if !ok {
+		return a, sql.ErrNoRows
+	}
+	i.ChannelID = a.ChannelID
+	i.DateFrom = a.DateFrom
+	i.DateTo = a.DateTo
+	i.Reason = a.Reason
+	m.absences[a.ID] = i
+	return i, nil
+}
+
+// GetAbsence returns absence by its ID
+func (m *Memory) GetAbsence(id int64) (model.Absence, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	a, ok := m.absences[id]
+	if !ok {
+		return a, sql.ErrNoRows
+	}
+	return a, nil
+}
+
+// ListTeamAbsences returns all workspace absences
+func (m *Memory) ListTeamAbsences(teamID string) ([]model.Absence, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	items := []model.Absence{}
+	for _, id := range m.absenceIDs() {
+		if m.absences[id].WorkspaceID == teamID {
+			items = append(items, m.absences[id])
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].DateFrom < items[j].DateFrom })
+	return items, nil
+}
+
+// ListUserAbsences returns absences of the user that end on date or later
+func (m *Memory) ListUserAbsences(userID, date string) ([]model.Absence, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	items := []model.Absence{}
+	for _, id := range m.absenceIDs() {
+		a := m.absences[id]
+		if a.UserID == userID && a.DateTo >= date {
+			items = append(items, a)
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].DateFrom < items[j].DateFrom })
+	return items, nil
+}
+
+// FindAbsence returns absence of the user in the channel that includes date
+func (m *Memory) FindAbsence(userID, channelID, date string) (model.Absence, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, id := range m.absenceIDs() {
+		a := m.absences[id]
+		if a.UserID == userID && (a.ChannelID == "" || a.ChannelID == channelID) && a.DateFrom <= date && a.DateTo >= date {
+			return a, nil
+		}
+	}
+	return model.Absence{}, sql.ErrNoRows
+}
+
+// DeleteAbsence deletes absence entry
+func (m *Memory) DeleteAbsence(id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.absences, id)
+	return nil
+}
+
+// CreateHoliday creates holiday entry in memory
+func (m *Memory) CreateHoliday(h model.Holiday) (model.Holiday, error) {
+	err := h.Validate()
+	if err != nil {
+		return h, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	h.ID = m.nextID()
+	m.holidays[h.ID] = h
+	return h, nil
+}
+
+// GetHoliday returns holiday by its ID
+func (m *Memory) GetHoliday(id int64) (model.Holiday, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	h, ok := m.holidays[id]
+	if !ok {
+		return h, sql.ErrNoRows
+	}
+	return h, nil
+}
+
+// ListTeamHolidays returns all workspace and project holidays of the workspace
+func (m *Memory) ListTeamHolidays(teamID string) ([]model.Holiday, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	items := []model.Holiday{}
+	for _, id := range m.holidayIDs() {
+		if m.holidays[id].WorkspaceID == teamID {
+			items = append(items, m.holidays[id])
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].Date < items[j].Date })
+	return items, nil
+}
+
+// FindHoliday returns holiday of the workspace or the channel on date
+func (m *Memory) FindHoliday(teamID, channelID, date string) (model.Holiday, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, id := range m.holidayIDs() {
+		h := m.holidays[id]
+		if h.WorkspaceID == teamID && (h.ChannelID == "" || h.ChannelID == channelID) && h.Date == date {
+			return h, nil
+		}
+	}
+	return model.Holiday{}, sql.ErrNoRows
+}
+
+// DeleteHoliday deletes holiday entry
+func (m *Memory) DeleteHoliday(id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.holidays, id)
+	return nil
+}
+
+// CreateJob creates job entry in memory
+func (m *Memory) CreateJob(j model.Job) (model.Job, error) {
+	err := j.Validate()
+	if err != nil {
+		return j, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, id := range m.jobIDs() {
+		if m.jobs[id].WorkspaceID == j.WorkspaceID && m.jobs[id].Name == j.Name {
+			return j, fmt.Errorf("job %v already exists in %v", j.Name, j.WorkspaceID)
+		}
+	}
+	j.ID = m.nextID()
+	m.jobs[j.ID] = j
+	return j, nil
+}
+
+// FindJob selects job of the workspace by its name
+func (m *Memory) FindJob(workspaceID, name string) (model.Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, id := range m.jobIDs() {
+		if m.jobs[id].WorkspaceID == workspaceID && m.jobs[id].Name == name {
+			return m.jobs[id], nil
+		}
+	}
+	return model.Job{}, sql.ErrNoRows
+}
+
+// ClaimJob moves last run of the job to lastRun only if nobody has moved it
+// since the job was selected
+func (m *Memory) ClaimJob(j model.Job, lastRun int64) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	i, ok := m.jobs[j.ID]
+	if !ok || i.LastRun != j.LastRun {
+		return false, nil
+	}
+	i.LastRun = lastRun
+	m.jobs[j.ID] = i
+	return true, nil
+}
+
+// DeleteJob deletes job entry from memory
+func (m *Memory) DeleteJob(id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.jobs, id)
+	return nil
+}
+
+// AcquireLease takes or renews lease for owner until the given time
+func (m *Memory) AcquireLease(name, owner string, now, until int64) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	l, ok := m.leases[name]
+	if ok && l.Owner != owner && l.ExpiresAt >= now {
+		return false, nil
+	}
+	if !ok {
+		l = model.Lease{ID: m.nextID(), Name: name}
+	}
+	l.Owner = owner
+	l.ExpiresAt = until
+	m.leases[name] = l
+	return true, nil
+}
+
+// ReleaseLease gives lease up if it is held by owner
+func (m *Memory) ReleaseLease(name, owner string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if l, ok := m.leases[name]; ok && l.Owner == owner {
+		delete(m.leases, name)
+	}
+	return nil
+}
+
+// CreateNotificationThread create notifications
+func (m *Memory) CreateNotificationThread(s model.NotificationThread) (model.NotificationThread, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s.ID = m.nextID()
+	m.notificationThreads[s.ID] = s
+	return s, nil
+}
+
+// DeleteNotificationThread deletes notification entry
+func (m *Memory) DeleteNotificationThread(id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.notificationThreads, id)
+	return nil
+}
+
+// SelectNotificationsThread returns notification thread of the channel
+func (m *Memory) SelectNotificationsThread(channelID string) (model.NotificationThread, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, id := range m.notificationThreadIDs() {
+		if m.notificationThreads[id].ChannelID == channelID {
+			return m.notificationThreads[id], nil
+		}
+	}
+	return model.NotificationThread{}, sql.ErrNoRows
+}
+
+// Migrate does nothing since in-memory store has no schema
+func (m *Memory) Migrate(command string, args ...string) error {
+	return nil
+}
+
+// UpdateNotificationThread update field reminder counter
+func (m *Memory) UpdateNotificationThread(id int64, notificationTime int64, nonReporters string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	nt, ok := m.notificationThreads[id]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	nt.UserIDs = nonReporters
+	nt.ReminderCounter++
+	nt.NotificationTime = notificationTime
+	m.notificationThreads[id] = nt
+	return nil
+}
+
+// CreateStandupDraft creates standup draft entry in memory
+func (m *Memory) CreateStandupDraft(d model.StandupDraft) (model.StandupDraft, error) {
+	err := d.Validate()
+	if err != nil {
+		return d, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, id := range m.standupDraftIDs() {
+		if m.standupDrafts[id].UserID == d.UserID && m.standupDrafts[id].ChannelID == d.ChannelID {
+			return d, fmt.Errorf("standup draft of %v already exists in %v", d.UserID, d.ChannelID)
+		}
+	}
+	d.ID = m.nextID()
+	m.standupDrafts[d.ID] = d
+	return d, nil
+}
+
+// UpdateStandupDraft updates answers of standup draft
+func (m *Memory) UpdateStandupDraft(d model.StandupDraft) (model.StandupDraft, error) {
+	err := d.Validate()
+	if err != nil {
+		return d, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	i, ok := m.standupDrafts[d.ID]
+	if !ok {
+		return d, sql.ErrNoRows
+	}
+	i.Section = d.Section
+	i.Done = d.Done
+	i.Planned = d.Planned
+	i.Blockers = d.Blockers
+	m.standupDrafts[d.ID] = i
+	return i, nil
+}
+
+// FindStandupDraft selects standup draft of user in the channel
+func (m *Memory) FindStandupDraft(userID, channelID string) (model.StandupDraft, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, id := range m.standupDraftIDs() {
+		if m.standupDrafts[id].UserID == userID && m.standupDrafts[id].ChannelID == channelID {
+			return m.standupDrafts[id], nil
+		}
+	}
+	return model.StandupDraft{}, sql.ErrNoRows
+}
+
+// ListUserStandupDrafts returns standup drafts of user in all channels, oldest first
+func (m *Memory) ListUserStandupDrafts(userID string) ([]model.StandupDraft, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	items := []model.StandupDraft{}
+	for _, id := range m.standupDraftIDs() {
+		if m.standupDrafts[id].UserID == userID {
+			items = append(items, m.standupDrafts[id])
+		}
+	}
+	return items, nil
+}
+
+// DeleteStandupDraft deletes standup draft entry
+func (m *Memory) DeleteStandupDraft(id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.standupDrafts, id)
+	return nil
+}
+
+// CreateAdmin creates admin entry in memory
+func (m *Memory) CreateAdmin(a model.Admin) (model.Admin, error) {
+	err := a.Validate()
+	if err != nil {
+		return a, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, id := range m.adminIDs() {
+		if m.admins[id].WorkspaceID == a.WorkspaceID && m.admins[id].UserID == a.UserID {
+			return a, fmt.Errorf("%v is already admin of %v", a.UserID, a.WorkspaceID)
+		}
+	}
+	a.ID = m.nextID()
+	m.admins[a.ID] = a
+	return a, nil
+}
+
+// FindAdmin selects admin of the workspace by user ID
+func (m *Memory) FindAdmin(workspaceID, userID string) (model.Admin, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, id := range m.adminIDs() {
+		if m.admins[id].WorkspaceID == workspaceID && m.admins[id].UserID == userID {
+			return m.admins[id], nil
+		}
+	}
+	return model.Admin{}, sql.ErrNoRows
+}
+
+// ListWorkspaceAdmins returns admins of the workspace
+func (m *Memory) ListWorkspaceAdmins(workspaceID string) ([]model.Admin, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	items := []model.Admin{}
+	for _, id := range m.adminIDs() {
+		if m.admins[id].WorkspaceID == workspaceID {
+			items = append(items, m.admins[id])
+		}
+	}
+	return items, nil
+}
+
+// DeleteAdmin deletes admin entry
+func (m *Memory) DeleteAdmin(id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.admins, id)
+	return nil
+}
+
+// CreateWebhook creates webhook entry in memory
+func (m *Memory) CreateWebhook(w model.Webhook) (model.Webhook, error) {
+	err := w.Validate()
+	if err != nil {
+		return w, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	w.ID = m.nextID()
+	m.webhooks[w.ID] = w
+	return w, nil
+}
+
+// UpdateWebhook updates URL, secret, events and state of webhook
+func (m *Memory) UpdateWebhook(w model.Webhook) (model.Webhook, error) {
+	err := w.Validate()
+	if err != nil {
+		return w, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	i, ok := m.webhooks[w.ID]
+	if !ok {
+		return w, sql.ErrNoRows
+	}
+	i.URL = w.URL
+	i.Secret = w.Secret
+	i.Events = w.Events
+	i.Enabled = w.Enabled
+	m.webhooks[w.ID] = i
+	return i, nil
+}
+
+// GetWebhook returns webhook by its ID
+func (m *Memory) GetWebhook(id int64) (model.Webhook, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	w, ok := m.webhooks[id]
+	if !ok {
+		return w, sql.ErrNoRows
+	}
+	return w, nil
+}
+
+// ListWorkspaceWebhooks returns webhooks of the workspace
+func (m *Memory) ListWorkspaceWebhooks(workspaceID string) ([]model.Webhook, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	items := []model.Webhook{}
+	for _, id := range m.webhookIDs() {
+		if m.webhooks[id].WorkspaceID == workspaceID {
+			items = append(items, m.webhooks[id])
+		}
+	}
+	return items, nil
+}
+
+// DeleteWebhook deletes webhook entry and its deliveries
+func (m *Memory) DeleteWebhook(id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, deliveryID := range m.webhookDeliveryIDs() {
+		if m.webhookDeliveries[deliveryID].WebhookID == id {
+			delete(m.webhookDeliveries, deliveryID)
+		}
+	}
+	delete(m.webhooks, id)
+	return nil
+}
+
+// CreateWebhookDelivery queues event for the webhook
+func (m *Memory) CreateWebhookDelivery(d model.WebhookDelivery) (model.WebhookDelivery, error) {
+	err := d.Validate()
+	if err != nil {
+		return d, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	d.ID = m.nextID()
+	m.webhookDeliveries[d.ID] = d
+	return d, nil
+}
+
+// UpdateWebhookDelivery records result of delivery attempt
+func (m *Memory) UpdateWebhookDelivery(d model.WebhookDelivery) (model.WebhookDelivery, error) {
+	err := d.Validate()
+	if err != nil {
+		return d, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	i, ok := m.webhookDeliveries[d.ID]
+	if !ok {
+		return d, sql.ErrNoRows
+	}
+	i.Status = d.Status
+	i.Attempts = d.Attempts
+	i.NextAttemptAt = d.NextAttemptAt
+	i.LastAttemptAt = d.LastAttemptAt
+	i.ResponseCode = d.ResponseCode
+	i.Error = d.Error
+	m.webhookDeliveries[d.ID] = i
+	return i, nil
+}
+
+// ListDueWebhookDeliveries returns up to limit pending deliveries of the workspace
+// to be attempted at now or earlier, oldest first
+func (m *Memory) ListDueWebhookDeliveries(workspaceID string, now int64, limit int) ([]model.WebhookDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	items := []model.WebhookDelivery{}
+	for _, id := range m.webhookDeliveryIDs() {
+		d := m.webhookDeliveries[id]
+		if d.WorkspaceID == workspaceID && d.Status == model.DeliveryPending && d.NextAttemptAt <= now && len(items) < limit {
+			items = append(items, d)
+		}
+	}
+	return items, nil
+}
+
+// ListWebhookDeliveries returns up to limit latest deliveries of the webhook, newest first
+func (m *Memory) ListWebhookDeliveries(webhookID int64, limit int) ([]model.WebhookDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	items := []model.WebhookDelivery{}
+	ids := m.webhookDeliveryIDs()
+	for i := len(ids) - 1; i >= 0 && len(items) < limit; i-- {
+		if m.webhookDeliveries[ids[i]].WebhookID == webhookID {
+			items = append(items, m.webhookDeliveries[ids[i]])
+		}
+	}
+	return items, nil
+}
@@ -0,0 +1,102 @@
+package storage
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/maddevsio/comedian/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStandups(t *testing.T) {
+	m := NewMemory()
+
+	_, err := m.CreateStandup(model.Standup{})
+	assert.Error(t, err)
+
+	first, err := m.CreateStandup(model.Standup{
+		CreatedAt:   time.Now().Unix(),
+		WorkspaceID: "foo",
+		UserID:      "bar",
+		ChannelID:   "bar12",
+		MessageTS:   "1",
+	})
+	require.NoError(t, err)
+
+	second, err := m.CreateStandup(model.Standup{
+		CreatedAt:   time.Now().Unix(),
+		WorkspaceID: "foo",
+		UserID:      "bar",
+		ChannelID:   "bar12",
+		MessageTS:   "2",
+	})
+	require.NoError(t, err)
+
+	standups, err := m.ListTeamStandups("foo")
+	require.NoError(t, err)
+	assert.Equal(t, 2, len(standups))
+	assert.Equal(t, second.ID, standups[0].ID)
+
+	latest, err := m.SelectLatestStandupByUser("bar", "bar12")
+	require.NoError(t, err)
+	assert.Equal(t, "2", latest.MessageTS)
+
+	_, err = m.SelectStandupByMessageTS("3")
+	assert.Equal(t, sql.ErrNoRows, err)
+
+	first.Comment = "yesterday, today, problems"
+	updated, err := m.UpdateStandup(first)
+	require.NoError(t, err)
+	assert.Equal(t, "yesterday, today, problems", updated.Comment)
+
+	assert.NoError(t, m.DeleteStandup(first.ID))
+	_, err = m.GetStandup(first.ID)
+	assert.Error(t, err)
+}
+
+func TestMemoryWorkspaces(t *testing.T) {
+	m := NewMemory()
+
+	ws, err := m.CreateWorkspace(model.Workspace{
+		Language:       "en_US",
+		MaxReminders:   3,
+		ReminderOffset: int64(10),
+		BotAccessToken: "token",
+		WorkspaceID:    "WorkspaceID",
+		WorkspaceName:  "foo",
+		ReportingTime:  "9:00",
+	})
+	require.NoError(t, err)
+
+	found, err := m.GetWorkspaceByBotAccessToken("token")
+	require.NoError(t, err)
+	assert.Equal(t, ws.ID, found.ID)
+
+	_, err = m.GetWorkspaceByWorkspaceID("wrong")
+	assert.Error(t, err)
+
+	assert.NoError(t, m.DeleteWorkspace("WorkspaceID"))
+	workspaces, err := m.GetAllWorkspaces()
+	require.NoError(t, err)
+	assert.Equal(t, 0, len(workspaces))
+}
+
+func TestMemoryNotificationThread(t *testing.T) {
+	m := NewMemory()
+
+	nt, err := m.CreateNotificationThread(model.NotificationThread{
+		ChannelID: "1",
+		UserIDs:   "User1",
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, m.UpdateNotificationThread(nt.ID, 100, "User1,User2"))
+
+	thread, err := m.SelectNotificationsThread("1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, thread.ReminderCounter)
+	assert.Equal(t, "User1,User2", thread.UserIDs)
+	assert.Equal(t, int64(100), thread.NotificationTime)
+}
@@ -1,55 +1,162 @@
 package storage
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
-	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
 
+	"github.com/maddevsio/comedian/migrations"
+	"github.com/maddevsio/comedian/secret"
 	"github.com/pressly/goose"
 
 	// This line is must for working MySQL database
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/jmoiron/sqlx"
+	// This line is must for working PostgreSQL database
+	_ "github.com/lib/pq"
+)
+
+const (
+	mysqlDriver    = "mysql"
+	postgresDriver = "postgres"
 )
 
 // DB provides api for work with DB database
 type DB struct {
-	db *sqlx.DB
+	db      *sqlx.DB
+	keyring *secret.Keyring
 }
 
-// New creates a new instance of database API
-func New(dbConn, migrationsPathh string) (*DB, error) {
-	conn, err := sqlx.Connect("mysql", dbConn)
+// New creates a new instance of database API. Bot access tokens are encrypted
+// with keyring, nil keyring stores them as plaintext
+func New(dbConn string, keyring *secret.Keyring) (*DB, error) {
+	driver, dsn := parseDatabaseURL(dbConn)
+
+	conn, err := sqlx.Connect(driver, dsn)
 	if err != nil {
-		conn, err = sqlx.Connect("mysql", "comedian:comedian@tcp(localhost:3306)/comedian?parseTime=true")
+		if driver != mysqlDriver {
+			return nil, err
+		}
+		conn, err = sqlx.Connect(mysqlDriver, "comedian:comedian@tcp(localhost:3306)/comedian?parseTime=true")
 		if err != nil {
 			return nil, err
 		}
 	}
-	db := &DB{conn}
 
-	goose.SetDialect("mysql")
+	return &DB{conn, keyring}, nil
+}
 
-	current, err := goose.EnsureDBVersion(conn.DB)
+// rewriteSecrets encrypts or decrypts every secret stored in database
+func (m *DB) rewriteSecrets(decrypt bool) error {
+	_, err := m.rewriteTokens(decrypt)
+	if err != nil {
+		return err
+	}
+	_, err = m.rewriteWorkSourceTokens(decrypt)
 	if err != nil {
-		return nil, fmt.Errorf("failed to EnsureDBVersion: %v", err)
+		return err
 	}
+	_, err = m.rewriteWebhookSecrets(decrypt)
+	return err
+}
 
-	files, err := ioutil.ReadDir(migrationsPathh)
+// Migrate runs goose migrations embedded into the binary.
+// Supported commands are up, down, status and to (with target version argument).
+// Bot access tokens, work source tokens and webhook secrets are encrypted with the primary key after up, encrypt
+// and decrypt commands only rewrite them, e.g. after key rotation or before rolling back encryption
+func (m *DB) Migrate(command string, args ...string) error {
+	switch command {
+	case "encrypt":
+		return m.rewriteSecrets(false)
+	case "decrypt":
+		return m.rewriteSecrets(true)
+	}
+
+	driver := m.db.DriverName()
+
+	err := goose.SetDialect(driver)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	migrations, err := goose.CollectMigrations(migrationsPathh, current, int64(len(files)))
+	dir, err := migrations.Extract(driver)
+	defer os.RemoveAll(dir)
 	if err != nil {
-		return nil, err
+		return fmt.Errorf("failed to extract migrations: %v", err)
 	}
 
-	for _, m := range migrations {
-		err := m.Up(conn.DB)
+	switch command {
+	case "up":
+		err = goose.Up(m.db.DB, dir)
 		if err != nil {
-			return nil, err
+			return err
 		}
+		return m.rewriteSecrets(false)
+	case "down":
+		return goose.Down(m.db.DB, dir)
+	case "status":
+		return goose.Status(m.db.DB, dir)
+	case "to":
+		if len(args) != 1 {
+			return errors.New("target version is required")
+		}
+		version, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("incorrect target version %v: %v", args[0], err)
+		}
+		current, err := goose.EnsureDBVersion(m.db.DB)
+		if err != nil {
+			return fmt.Errorf("failed to EnsureDBVersion: %v", err)
+		}
+		if version >= current {
+			return goose.UpTo(m.db.DB, dir, version)
+		}
+		return goose.DownTo(m.db.DB, dir, version)
+	default:
+		return fmt.Errorf("unknown migrate command %v", command)
+	}
+}
+
+// parseDatabaseURL returns driver name and data source name for the DATABASE value.
+// postgres:// and postgresql:// URLs go to PostgreSQL, everything else is a MySQL DSN,
+// optionally prefixed with mysql://
+func parseDatabaseURL(dbConn string) (string, string) {
+	if strings.HasPrefix(dbConn, "postgres://") || strings.HasPrefix(dbConn, "postgresql://") {
+		return postgresDriver, dbConn
+	}
+	return mysqlDriver, strings.TrimPrefix(dbConn, "mysql://")
+}
+
+// exec runs query rebinding ? placeholders to the driver format
+func (m *DB) exec(query string, args ...interface{}) (sql.Result, error) {
+	return m.db.Exec(m.db.Rebind(query), args...)
+}
+
+// get selects single row rebinding ? placeholders to the driver format
+func (m *DB) get(dest interface{}, query string, args ...interface{}) error {
+	return m.db.Get(dest, m.db.Rebind(query), args...)
+}
+
+// list selects rows rebinding ? placeholders to the driver format
+func (m *DB) list(dest interface{}, query string, args ...interface{}) error {
+	return m.db.Select(dest, m.db.Rebind(query), args...)
+}
+
+// insert runs INSERT query and returns id of the created row.
+// PostgreSQL driver does not support LastInsertId, so RETURNING is used there
+func (m *DB) insert(query string, args ...interface{}) (int64, error) {
+	if m.db.DriverName() == postgresDriver {
+		var id int64
+		err := m.db.QueryRowx(m.db.Rebind(query+" RETURNING id"), args...).Scan(&id)
+		return id, err
 	}
 
-	return db, nil
+	res, err := m.db.Exec(query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
 }
@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"github.com/maddevsio/comedian/model"
+)
+
+// CreateStandupDraft creates standup draft entry in database
+func (m *DB) CreateStandupDraft(d model.StandupDraft) (model.StandupDraft, error) {
+	err := d.Validate()
+	if err != nil {
+		return d, err
+	}
+
+	id, err := m.insert(
+		`INSERT INTO standup_drafts (
+			created_at,
+			workspace_id,
+			channel_id,
+			user_id,
+			section,
+			done,
+			planned,
+			blockers
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		d.CreatedAt,
+		d.WorkspaceID,
+		d.ChannelID,
+		d.UserID,
+		d.Section,
+		d.Done,
+		d.Planned,
+		d.Blockers,
+	)
+	if err != nil {
+		return d, err
+	}
+	d.ID = id
+
+	return d, nil
+}
+
+// UpdateStandupDraft updates answers of standup draft
+func (m *DB) UpdateStandupDraft(d model.StandupDraft) (model.StandupDraft, error) {
+	err := d.Validate()
+	if err != nil {
+		return d, err
+	}
+
+	_, err = m.exec(
+		"UPDATE standup_drafts SET section=?, done=?, planned=?, blockers=? WHERE id=?",
+		d.Section, d.Done, d.Planned, d.Blockers, d.ID,
+	)
+	if err != nil {
+		return d, err
+	}
+
+	var i model.StandupDraft
+	err = m.get(&i, "SELECT * FROM standup_drafts WHERE id=?", d.ID)
+	return i, err
+}
+
+// FindStandupDraft selects standup draft of user in the channel
+func (m *DB) FindStandupDraft(userID, channelID string) (model.StandupDraft, error) {
+	var d model.StandupDraft
+	err := m.get(&d, "SELECT * FROM standup_drafts WHERE user_id=? AND channel_id=?", userID, channelID)
+	return d, err
+}
+
+// ListUserStandupDrafts returns standup drafts of user in all channels, oldest first
+func (m *DB) ListUserStandupDrafts(userID string) ([]model.StandupDraft, error) {
+	items := []model.StandupDraft{}
+	err := m.list(&items, "SELECT * FROM standup_drafts WHERE user_id=? order by id", userID)
+	return items, err
+}
+
+// DeleteStandupDraft deletes standup draft entry from database
+func (m *DB) DeleteStandupDraft(id int64) error {
+	_, err := m.exec("DELETE FROM standup_drafts WHERE id=?", id)
+	return err
+}
@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/maddevsio/comedian/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStandupDrafts(t *testing.T) {
+	_, err := db.CreateStandupDraft(model.StandupDraft{WorkspaceID: "drafts"})
+	assert.Error(t, err)
+
+	_, err = db.FindStandupDraft("USER1", "CHAN1")
+	assert.Error(t, err)
+
+	first, err := db.CreateStandupDraft(model.StandupDraft{
+		WorkspaceID: "drafts",
+		ChannelID:   "CHAN1",
+		UserID:      "USER1",
+		Section:     model.SectionYesterday,
+	})
+	require.NoError(t, err)
+
+	_, err = db.CreateStandupDraft(model.StandupDraft{
+		WorkspaceID: "drafts",
+		ChannelID:   "CHAN1",
+		UserID:      "USER1",
+		Section:     model.SectionYesterday,
+	})
+	assert.Error(t, err)
+
+	second, err := db.CreateStandupDraft(model.StandupDraft{
+		WorkspaceID: "drafts",
+		ChannelID:   "CHAN2",
+		UserID:      "USER1",
+		Section:     model.SectionYesterday,
+	})
+	require.NoError(t, err)
+
+	first.Done = "fixed login"
+	first.Section = model.SectionToday
+	_, err = db.UpdateStandupDraft(first)
+	require.NoError(t, err)
+
+	draft, err := db.FindStandupDraft("USER1", "CHAN1")
+	require.NoError(t, err)
+	assert.Equal(t, "fixed login", draft.Done)
+	assert.Equal(t, model.SectionToday, draft.Section)
+
+	drafts, err := db.ListUserStandupDrafts("USER1")
+	require.NoError(t, err)
+	require.Equal(t, 2, len(drafts))
+	assert.Equal(t, first.ID, drafts[0].ID)
+	assert.Equal(t, second.ID, drafts[1].ID)
+
+	assert.NoError(t, db.DeleteStandupDraft(first.ID))
+	assert.NoError(t, db.DeleteStandupDraft(second.ID))
+
+	drafts, err = db.ListUserStandupDrafts("USER1")
+	require.NoError(t, err)
+	assert.Equal(t, 0, len(drafts))
+}
@@ -88,10 +88,14 @@ func TestUpdateStanduper(t *testing.T) {
 	assert.Equal(t, "", s.Role)
 
 	s.Role = "developer"
+	s.TZ = "Europe/Moscow"
+	s.Deadline = "10am"
 
 	s, err = db.UpdateStanduper(s)
 	assert.NoError(t, err)
 	assert.Equal(t, "developer", s.Role)
+	assert.Equal(t, "Europe/Moscow", s.TZ)
+	assert.Equal(t, "10am", s.Deadline)
 
 	assert.NoError(t, db.DeleteStanduper(s.ID))
 }
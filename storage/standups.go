@@ -11,29 +11,31 @@ func (m *DB) CreateStandup(s model.Standup) (model.Standup, error) {
 		return s, err
 	}
 
-	res, err := m.db.Exec(
+	id, err := m.insert(
 		`INSERT INTO standups (
 			created_at,
 			workspace_id, 
 			channel_id, 
 			user_id, 
 			comment, 
+			done,
+			planned,
+			blockers,
 			message_ts
-		) VALUES (?, ?, ?, ?, ?, ?)`,
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		s.CreatedAt,
 		s.WorkspaceID,
 		s.ChannelID,
 		s.UserID,
 		s.Comment,
+		s.Done,
+		s.Planned,
+		s.Blockers,
 		s.MessageTS,
 	)
 	if err != nil {
 		return s, err
 	}
-	id, err := res.LastInsertId()
-	if err != nil {
-		return s, err
-	}
 	s.ID = id
 
 	return s, nil
@@ -46,36 +48,50 @@ func (m *DB) UpdateStandup(s model.Standup) (model.Standup, error) {
 		return s, err
 	}
 
-	_, err = m.db.Exec(
-		"UPDATE `standups` SET comment=?, message_ts=? WHERE id=?",
-		s.Comment, s.MessageTS, s.ID,
+	_, err = m.exec(
+		"UPDATE standups SET comment=?, done=?, planned=?, blockers=?, message_ts=? WHERE id=?",
+		s.Comment, s.Done, s.Planned, s.Blockers, s.MessageTS, s.ID,
 	)
 	if err != nil {
 		return s, err
 	}
 	var i model.Standup
-	err = m.db.Get(&i, "SELECT * FROM `standups` WHERE id=?", s.ID)
+	err = m.get(&i, "SELECT * FROM standups WHERE id=?", s.ID)
 	return i, err
 }
 
 // ListStandups returns array of standup entries from database
 func (m *DB) ListStandups() ([]model.Standup, error) {
 	items := []model.Standup{}
-	err := m.db.Select(&items, "SELECT * FROM `standups` order by id desc")
+	err := m.list(&items, "SELECT * FROM standups order by id desc")
 	return items, err
 }
 
 // ListTeamStandups returns array of standup entries from database
 func (m *DB) ListTeamStandups(teamID string) ([]model.Standup, error) {
 	items := []model.Standup{}
-	err := m.db.Select(&items, "SELECT * FROM `standups` where workspace_id=? order by id desc", teamID)
+	err := m.list(&items, "SELECT * FROM standups where workspace_id=? order by id desc", teamID)
+	return items, err
+}
+
+// ListTeamStandupsForPeriod returns array of workspace standups created in the given period
+func (m *DB) ListTeamStandupsForPeriod(teamID string, timeFrom, timeTo int64) ([]model.Standup, error) {
+	items := []model.Standup{}
+	err := m.list(&items,
+		`SELECT * FROM standups 
+		where workspace_id=? and created_at BETWEEN ? AND ? 
+		order by id desc`,
+		teamID,
+		timeFrom,
+		timeTo,
+	)
 	return items, err
 }
 
 //GetStandup returns standup by its ID
 func (m *DB) GetStandup(id int64) (model.Standup, error) {
 	var s model.Standup
-	err := m.db.Get(&s, "SELECT * FROM `standups` WHERE id=?", id)
+	err := m.get(&s, "SELECT * FROM standups WHERE id=?", id)
 	if err != nil {
 		return s, err
 	}
@@ -85,7 +101,7 @@ func (m *DB) GetStandup(id int64) (model.Standup, error) {
 // SelectStandupByMessageTS selects standup entry from database filtered by MessageTS parameter
 func (m *DB) SelectStandupByMessageTS(messageTS string) (model.Standup, error) {
 	var s model.Standup
-	err := m.db.Get(&s, "SELECT * FROM `standups` WHERE message_ts=?", messageTS)
+	err := m.get(&s, "SELECT * FROM standups WHERE message_ts=?", messageTS)
 	if err != nil {
 		return s, err
 	}
@@ -95,7 +111,7 @@ func (m *DB) SelectStandupByMessageTS(messageTS string) (model.Standup, error) {
 // SelectLatestStandupByUser selects standup entry from database filtered by user
 func (m *DB) SelectLatestStandupByUser(userID, channelID string) (model.Standup, error) {
 	var s model.Standup
-	err := m.db.Get(&s,
+	err := m.get(&s,
 		`select * from standups 
 		where user_id=? and channel_id=? 
 		order by id desc limit 1`,
@@ -110,7 +126,7 @@ func (m *DB) SelectLatestStandupByUser(userID, channelID string) (model.Standup,
 // GetStandupForPeriod selects standup entry from database filtered by user
 func (m *DB) GetStandupForPeriod(userID, channelID string, timeFrom, timeTo int64) (*model.Standup, error) {
 	s := &model.Standup{}
-	err := m.db.Get(s,
+	err := m.get(s,
 		`select * from standups 
 		where user_id=? and channel_id=? 
 		and created_at BETWEEN ? AND ? 
@@ -128,6 +144,6 @@ func (m *DB) GetStandupForPeriod(userID, channelID string, timeFrom, timeTo int6
 
 // DeleteStandup deletes standup entry from database
 func (m *DB) DeleteStandup(id int64) error {
-	_, err := m.db.Exec("DELETE FROM `standups` WHERE id=?", id)
+	_, err := m.exec("DELETE FROM standups WHERE id=?", id)
 	return err
 }
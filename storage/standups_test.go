@@ -86,12 +86,22 @@ func TestUpdateStandup(t *testing.T) {
 	assert.Equal(t, "12345", st.MessageTS)
 
 	st.Comment = "yesterday, today, problems"
+	st.Blockers = "problems"
 	st.MessageTS = "123456"
 
 	st, err = db.UpdateStandup(st)
 	assert.NoError(t, err)
 	assert.Equal(t, "yesterday, today, problems", st.Comment)
+	assert.Equal(t, "problems", st.Blockers)
 	assert.Equal(t, "123456", st.MessageTS)
 
+	standups, err := db.ListTeamStandupsForPeriod("foo", time.Now().Add(-time.Hour).Unix(), time.Now().Add(time.Hour).Unix())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(standups))
+
+	standups, err = db.ListTeamStandupsForPeriod("foo", time.Now().Add(-2*time.Hour).Unix(), time.Now().Add(-time.Hour).Unix())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(standups))
+
 	assert.NoError(t, db.DeleteStandup(st.ID))
 }
@@ -0,0 +1,128 @@
+package storage
+
+import (
+	"strings"
+
+	"github.com/maddevsio/comedian/model"
+	"github.com/maddevsio/comedian/secret"
+)
+
+// Store describes everything Comedian needs from a persistence layer
+type Store interface {
+	CreateStandup(model.Standup) (model.Standup, error)
+	UpdateStandup(model.Standup) (model.Standup, error)
+	ListStandups() ([]model.Standup, error)
+	ListTeamStandups(teamID string) ([]model.Standup, error)
+	ListTeamStandupsForPeriod(teamID string, timeFrom, timeTo int64) ([]model.Standup, error)
+	GetStandup(id int64) (model.Standup, error)
+	SelectStandupByMessageTS(messageTS string) (model.Standup, error)
+	SelectLatestStandupByUser(userID, channelID string) (model.Standup, error)
+	GetStandupForPeriod(userID, channelID string, timeFrom, timeTo int64) (*model.Standup, error)
+	DeleteStandup(id int64) error
+
+	CreateStanduper(model.Standuper) (model.Standuper, error)
+	UpdateStanduper(model.Standuper) (model.Standuper, error)
+	FindStansuperByUserID(userID, channelID string) (model.Standuper, error)
+	FindStansupersByUserID(userID string) ([]model.Standuper, error)
+	ListStandupers() ([]model.Standuper, error)
+	ListWorkspaceStandupers(workspaceID string) ([]model.Standuper, error)
+	GetStanduper(id int64) (model.Standuper, error)
+	ListProjectStandupers(channelID string) ([]model.Standuper, error)
+	ListStandupersByWorkspaceID(wsID string) ([]model.Standuper, error)
+	DeleteStanduper(id int64) error
+
+	CreateProject(model.Project) (model.Project, error)
+	UpdateProject(model.Project) (model.Project, error)
+	ListProjects() ([]model.Project, error)
+	ListWorkspaceProjects(ws string) ([]model.Project, error)
+	SelectProject(channelID string) (model.Project, error)
+	GetProject(id int64) (model.Project, error)
+	DeleteProject(id int64) error
+
+	CreateWorkspace(model.Workspace) (model.Workspace, error)
+	UpdateWorkspace(model.Workspace) (model.Workspace, error)
+	GetAllWorkspaces() ([]model.Workspace, error)
+	GetWorkspaceByWorkspaceID(workspaceID string) (model.Workspace, error)
+	GetWorkspaceByBotAccessToken(botAccessToken string) (model.Workspace, error)
+	GetWorkspace(id int64) (model.Workspace, error)
+	DeleteWorkspaceByID(id int64) error
+	DeleteWorkspace(teamID string) error
+
+	CreateBlocker(model.Blocker) (model.Blocker, error)
+	UpdateBlocker(model.Blocker) (model.Blocker, error)
+	GetBlocker(id int64) (model.Blocker, error)
+	ListTeamBlockers(teamID string) ([]model.Blocker, error)
+	ListOpenBlockers(channelID string) ([]model.Blocker, error)
+	DeleteBlocker(id int64) error
+
+	CreateAbsence(model.Absence) (model.Absence, error)
+	UpdateAbsence(model.Absence) (model.Absence, error)
+	GetAbsence(id int64) (model.Absence, error)
+	ListTeamAbsences(teamID string) ([]model.Absence, error)
+	ListUserAbsences(userID, date string) ([]model.Absence, error)
+	FindAbsence(userID, channelID, date string) (model.Absence, error)
+	DeleteAbsence(id int64) error
+
+	CreateHoliday(model.Holiday) (model.Holiday, error)
+	GetHoliday(id int64) (model.Holiday, error)
+	ListTeamHolidays(teamID string) ([]model.Holiday, error)
+	FindHoliday(teamID, channelID, date string) (model.Holiday, error)
+	DeleteHoliday(id int64) error
+
+	CreateJob(model.Job) (model.Job, error)
+	FindJob(workspaceID, name string) (model.Job, error)
+	ClaimJob(j model.Job, lastRun int64) (bool, error)
+	DeleteJob(id int64) error
+
+	AcquireLease(name, owner string, now, until int64) (bool, error)
+	ReleaseLease(name, owner string) error
+
+	CreateStandupDraft(model.StandupDraft) (model.StandupDraft, error)
+	UpdateStandupDraft(model.StandupDraft) (model.StandupDraft, error)
+	FindStandupDraft(userID, channelID string) (model.StandupDraft, error)
+	ListUserStandupDrafts(userID string) ([]model.StandupDraft, error)
+	DeleteStandupDraft(id int64) error
+
+	CreateAdmin(model.Admin) (model.Admin, error)
+	FindAdmin(workspaceID, userID string) (model.Admin, error)
+	ListWorkspaceAdmins(workspaceID string) ([]model.Admin, error)
+	DeleteAdmin(id int64) error
+
+	CreateWebhook(model.Webhook) (model.Webhook, error)
+	UpdateWebhook(model.Webhook) (model.Webhook, error)
+	GetWebhook(id int64) (model.Webhook, error)
+	ListWorkspaceWebhooks(workspaceID string) ([]model.Webhook, error)
+	DeleteWebhook(id int64) error
+
+	CreateWebhookDelivery(model.WebhookDelivery) (model.WebhookDelivery, error)
+	UpdateWebhookDelivery(model.WebhookDelivery) (model.WebhookDelivery, error)
+	ListDueWebhookDeliveries(workspaceID string, now int64, limit int) ([]model.WebhookDelivery, error)
+	ListWebhookDeliveries(webhookID int64, limit int) ([]model.WebhookDelivery, error)
+
+	CreateNotificationThread(model.NotificationThread) (model.NotificationThread, error)
+	DeleteNotificationThread(id int64) error
+	SelectNotificationsThread(channelID string) (model.NotificationThread, error)
+	UpdateNotificationThread(id int64, notificationTime int64, nonReporters string) error
+
+	Migrate(command string, args ...string) error
+}
+
+var (
+	_ Store = &DB{}
+	_ Store = &Memory{}
+)
+
+const memoryScheme = "memory://"
+
+// Open picks Store implementation based on the scheme of dbConn.
+// "memory://" gives an in-memory store, anything else is treated as MySQL DSN
+func Open(dbConn string, keyring *secret.Keyring) (Store, error) {
+	if strings.HasPrefix(dbConn, memoryScheme) {
+		return NewMemory(), nil
+	}
+	db, err := New(dbConn, keyring)
+	if err != nil {
+		return nil, err
+	}
+	return db, nil
+}
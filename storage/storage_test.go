@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/maddevsio/comedian/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateUnknownEntities(t *testing.T) {
+	const unknown = int64(1 << 40)
+
+	_, err := db.UpdateProject(model.Project{ID: unknown, WorkspaceID: "foo", ChannelID: "bar", ChannelName: "bar"})
+	assert.Equal(t, sql.ErrNoRows, err)
+
+	_, err = db.UpdateWorkspace(model.Workspace{ID: unknown, Language: "en_US", MaxReminders: 3, ReminderOffset: 10, BotAccessToken: "token", WorkspaceID: "foo", WorkspaceName: "foo", ReportingTime: "9:00"})
+	assert.Equal(t, sql.ErrNoRows, err)
+
+	_, err = db.UpdateBlocker(model.Blocker{ID: unknown, WorkspaceID: "foo", ChannelID: "bar", UserID: "baz", Text: "blocked"})
+	assert.Equal(t, sql.ErrNoRows, err)
+
+	_, err = db.UpdateAbsence(model.Absence{ID: unknown, WorkspaceID: "foo", UserID: "baz", DateFrom: "2019-05-01", DateTo: "2019-05-02"})
+	assert.Equal(t, sql.ErrNoRows, err)
+
+	_, err = db.UpdateWebhook(model.Webhook{ID: unknown, WorkspaceID: "foo", URL: "https://example.com", Secret: "secret"})
+	assert.Equal(t, sql.ErrNoRows, err)
+
+	err = db.UpdateNotificationThread(unknown, 100, "User1")
+	assert.Equal(t, sql.ErrNoRows, err)
+}
+
+func TestUpdateReturnsStoredEntity(t *testing.T) {
+	absence, err := db.CreateAbsence(model.Absence{WorkspaceID: "foo", UserID: "baz", DateFrom: "2019-05-01", DateTo: "2019-05-02"})
+	require.NoError(t, err)
+	defer db.DeleteAbsence(absence.ID)
+
+	// only period, reason and channel are updated
+	updated, err := db.UpdateAbsence(model.Absence{ID: absence.ID, WorkspaceID: "other", UserID: "other", DateFrom: "2019-05-01", DateTo: "2019-05-03", Reason: "vacation"})
+	require.NoError(t, err)
+	assert.Equal(t, "foo", updated.WorkspaceID)
+	assert.Equal(t, "baz", updated.UserID)
+	assert.Equal(t, "2019-05-03", updated.DateTo)
+	assert.Equal(t, "vacation", updated.Reason)
+
+	ws, err := db.CreateWorkspace(model.Workspace{CreatedAt: 100, Language: "en_US", MaxReminders: 3, ReminderOffset: 10, BotAccessToken: "token", WorkspaceID: "storageTest", WorkspaceName: "storageTest", ReportingTime: "9:00"})
+	require.NoError(t, err)
+	defer db.DeleteWorkspaceByID(ws.ID)
+
+	ws.CreatedAt = 200
+	ws.Language = "ru_RU"
+	updatedWs, err := db.UpdateWorkspace(ws)
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), updatedWs.CreatedAt)
+	assert.Equal(t, "ru_RU", updatedWs.Language)
+	assert.Equal(t, "token", updatedWs.BotAccessToken)
+}
@@ -0,0 +1,250 @@
+package storage
+
+import (
+	"fmt"
+
+	"github.com/maddevsio/comedian/model"
+	"github.com/maddevsio/comedian/secret"
+	log "github.com/sirupsen/logrus"
+)
+
+// CreateWebhook creates webhook entry in database, secret is stored encrypted
+func (m *DB) CreateWebhook(w model.Webhook) (model.Webhook, error) {
+	err := w.Validate()
+	if err != nil {
+		return w, err
+	}
+
+	secret, err := m.keyring.Encrypt(w.Secret)
+	if err != nil {
+		return w, err
+	}
+
+	id, err := m.insert(
+		`INSERT INTO webhooks (
+			created_at,
+			workspace_id,
+			url,
+			secret,
+			events,
+			enabled
+		) VALUES (?, ?, ?, ?, ?, ?)`,
+		w.CreatedAt,
+		w.WorkspaceID,
+		w.URL,
+		secret,
+		w.Events,
+		w.Enabled,
+	)
+	if err != nil {
+		return w, err
+	}
+	w.ID = id
+
+	return w, nil
+}
+
+// UpdateWebhook updates URL, secret, events and state of webhook
+func (m *DB) UpdateWebhook(w model.Webhook) (model.Webhook, error) {
+	err := w.Validate()
+	if err != nil {
+		return w, err
+	}
+
+	secret, err := m.keyring.Encrypt(w.Secret)
+	if err != nil {
+		return w, err
+	}
+
+	_, err = m.exec(
+		"UPDATE webhooks SET url=?, secret=?, events=?, enabled=? WHERE id=?",
+		w.URL, secret, w.Events, w.Enabled, w.ID,
+	)
+	if err != nil {
+		return w, err
+	}
+
+	return m.GetWebhook(w.ID)
+}
+
+// GetWebhook selects webhook entry from database
+func (m *DB) GetWebhook(id int64) (model.Webhook, error) {
+	var w model.Webhook
+	err := m.get(&w, "SELECT * FROM webhooks WHERE id=?", id)
+	if err != nil {
+		return w, err
+	}
+	return w, m.decryptSecret(&w)
+}
+
+// ListWorkspaceWebhooks returns webhooks of the workspace
+func (m *DB) ListWorkspaceWebhooks(workspaceID string) ([]model.Webhook, error) {
+	items := []model.Webhook{}
+	err := m.list(&items, "SELECT * FROM webhooks WHERE workspace_id=? order by id", workspaceID)
+	if err != nil {
+		return items, err
+	}
+	for i := range items {
+		err = m.decryptSecret(&items[i])
+		if err != nil {
+			return items, err
+		}
+	}
+	return items, nil
+}
+
+// DeleteWebhook deletes webhook entry and its deliveries from database
+func (m *DB) DeleteWebhook(id int64) error {
+	_, err := m.exec("DELETE FROM webhook_deliveries WHERE webhook_id=?", id)
+	if err != nil {
+		return err
+	}
+	_, err = m.exec("DELETE FROM webhooks WHERE id=?", id)
+	return err
+}
+
+func (m *DB) decryptSecret(w *model.Webhook) error {
+	secret, err := m.keyring.Decrypt(w.Secret)
+	if err != nil {
+		return err
+	}
+	w.Secret = secret
+	return nil
+}
+
+// rewriteWebhookSecrets stores webhook secrets encrypted with the primary key, or as
+// plaintext if decrypt is set. Returns number of updated webhooks
+func (m *DB) rewriteWebhookSecrets(decrypt bool) (int, error) {
+	rows := []model.Webhook{}
+	err := m.list(&rows, "SELECT id, secret FROM webhooks")
+	if err != nil {
+		return 0, err
+	}
+
+	updated := 0
+	for _, row := range rows {
+		plain, err := m.keyring.Decrypt(row.Secret)
+		if err != nil {
+			return updated, fmt.Errorf("webhook %v: %v", row.ID, err)
+		}
+
+		stored := plain
+		if decrypt {
+			if !secret.IsEncrypted(row.Secret) {
+				continue
+			}
+		} else {
+			if !m.keyring.NeedsRewrite(row.Secret) {
+				continue
+			}
+			stored, err = m.keyring.Encrypt(plain)
+			if err != nil {
+				return updated, err
+			}
+		}
+
+		_, err = m.exec("UPDATE webhooks SET secret=? WHERE id=?", stored, row.ID)
+		if err != nil {
+			return updated, err
+		}
+		updated++
+	}
+
+	if updated > 0 {
+		log.Infof("rewrote secrets of %d webhooks", updated)
+	}
+	return updated, nil
+}
+
+// CreateWebhookDelivery queues event for the webhook
+func (m *DB) CreateWebhookDelivery(d model.WebhookDelivery) (model.WebhookDelivery, error) {
+	err := d.Validate()
+	if err != nil {
+		return d, err
+	}
+
+	id, err := m.insert(
+		`INSERT INTO webhook_deliveries (
+			created_at,
+			workspace_id,
+			webhook_id,
+			event,
+			payload,
+			status,
+			attempts,
+			next_attempt_at,
+			last_attempt_at,
+			response_code,
+			error
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		d.CreatedAt,
+		d.WorkspaceID,
+		d.WebhookID,
+		d.Event,
+		d.Payload,
+		d.Status,
+		d.Attempts,
+		d.NextAttemptAt,
+		d.LastAttemptAt,
+		d.ResponseCode,
+		d.Error,
+	)
+	if err != nil {
+		return d, err
+	}
+	d.ID = id
+
+	return d, nil
+}
+
+// UpdateWebhookDelivery records result of delivery attempt
+func (m *DB) UpdateWebhookDelivery(d model.WebhookDelivery) (model.WebhookDelivery, error) {
+	err := d.Validate()
+	if err != nil {
+		return d, err
+	}
+
+	_, err = m.exec(
+		`UPDATE webhook_deliveries SET
+			status=?,
+			attempts=?,
+			next_attempt_at=?,
+			last_attempt_at=?,
+			response_code=?,
+			error=?
+			WHERE id=?`,
+		d.Status,
+		d.Attempts,
+		d.NextAttemptAt,
+		d.LastAttemptAt,
+		d.ResponseCode,
+		d.Error,
+		d.ID,
+	)
+	if err != nil {
+		return d, err
+	}
+
+	var i model.WebhookDelivery
+	err = m.get(&i, "SELECT * FROM webhook_deliveries WHERE id=?", d.ID)
+	return i, err
+}
+
+// ListDueWebhookDeliveries returns up to limit pending deliveries of the workspace
+// to be attempted at now or earlier, oldest first
+func (m *DB) ListDueWebhookDeliveries(workspaceID string, now int64, limit int) ([]model.WebhookDelivery, error) {
+	items := []model.WebhookDelivery{}
+	err := m.list(
+		&items,
+		"SELECT * FROM webhook_deliveries WHERE workspace_id=? AND status=? AND next_attempt_at<=? order by id LIMIT ?",
+		workspaceID, model.DeliveryPending, now, limit,
+	)
+	return items, err
+}
+
+// ListWebhookDeliveries returns up to limit latest deliveries of the webhook, newest first
+func (m *DB) ListWebhookDeliveries(webhookID int64, limit int) ([]model.WebhookDelivery, error) {
+	items := []model.WebhookDelivery{}
+	err := m.list(&items, "SELECT * FROM webhook_deliveries WHERE webhook_id=? order by id desc LIMIT ?", webhookID, limit)
+	return items, err
+}
@@ -0,0 +1,103 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/maddevsio/comedian/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhooks(t *testing.T) {
+	_, err := db.CreateWebhook(model.Webhook{WorkspaceID: "webhooks", URL: "not a url", Secret: "secret"})
+	assert.Error(t, err)
+
+	w, err := db.CreateWebhook(model.Webhook{
+		WorkspaceID: "webhooks",
+		URL:         "https://example.com/hooks",
+		Secret:      "secret",
+		Events:      model.EventStandupCreated,
+		Enabled:     true,
+	})
+	require.NoError(t, err)
+
+	w.Events = ""
+	w.Enabled = false
+	_, err = db.UpdateWebhook(w)
+	require.NoError(t, err)
+
+	found, err := db.GetWebhook(w.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", found.Secret)
+	assert.Equal(t, "", found.Events)
+	assert.False(t, found.Enabled)
+
+	webhooks, err := db.ListWorkspaceWebhooks("webhooks")
+	require.NoError(t, err)
+	require.Equal(t, 1, len(webhooks))
+	assert.Equal(t, "secret", webhooks[0].Secret)
+
+	assert.NoError(t, db.DeleteWebhook(w.ID))
+	_, err = db.GetWebhook(w.ID)
+	assert.Error(t, err)
+}
+
+func TestWebhookDeliveries(t *testing.T) {
+	w, err := db.CreateWebhook(model.Webhook{
+		WorkspaceID: "deliveries",
+		URL:         "https://example.com/hooks",
+		Secret:      "secret",
+		Enabled:     true,
+	})
+	require.NoError(t, err)
+
+	_, err = db.CreateWebhookDelivery(model.WebhookDelivery{WorkspaceID: "deliveries", WebhookID: w.ID, Event: model.EventStandupCreated})
+	assert.Error(t, err)
+
+	ids := []int64{}
+	for _, next := range []int64{100, 200, 300} {
+		d, err := db.CreateWebhookDelivery(model.WebhookDelivery{
+			WorkspaceID:   "deliveries",
+			WebhookID:     w.ID,
+			Event:         model.EventStandupCreated,
+			Payload:       "{}",
+			Status:        model.DeliveryPending,
+			NextAttemptAt: next,
+		})
+		require.NoError(t, err)
+		ids = append(ids, d.ID)
+	}
+
+	due, err := db.ListDueWebhookDeliveries("deliveries", 250, 10)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(due))
+	assert.Equal(t, ids[0], due[0].ID)
+
+	due, err = db.ListDueWebhookDeliveries("deliveries", 250, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, len(due))
+
+	delivered := due[0]
+	delivered.Status = model.DeliveryDelivered
+	delivered.Attempts = 1
+	delivered.ResponseCode = 200
+	_, err = db.UpdateWebhookDelivery(delivered)
+	require.NoError(t, err)
+
+	due, err = db.ListDueWebhookDeliveries("deliveries", 250, 10)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(due))
+	assert.Equal(t, ids[1], due[0].ID)
+
+	log, err := db.ListWebhookDeliveries(w.ID, 10)
+	require.NoError(t, err)
+	require.Equal(t, 3, len(log))
+	assert.Equal(t, ids[2], log[0].ID)
+	assert.Equal(t, model.DeliveryDelivered, log[2].Status)
+	assert.Equal(t, 200, log[2].ResponseCode)
+
+	assert.NoError(t, db.DeleteWebhook(w.ID))
+	log, err = db.ListWebhookDeliveries(w.ID, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 0, len(log))
+}
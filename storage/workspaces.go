@@ -1,7 +1,11 @@
 package storage
 
 import (
+	"fmt"
+
 	"github.com/maddevsio/comedian/model"
+	"github.com/maddevsio/comedian/secret"
+	log "github.com/sirupsen/logrus"
 )
 
 //CreateWorkspace creates bot properties for the newly created bot
@@ -11,7 +15,22 @@ func (m *DB) CreateWorkspace(bs model.Workspace) (model.Workspace, error) {
 		return bs, err
 	}
 
-	res, err := m.db.Exec(
+	if bs.Platform == "" {
+		bs.Platform = model.PlatformSlack
+	}
+
+	token, err := m.keyring.Encrypt(bs.BotAccessToken)
+	if err != nil {
+		return bs, err
+	}
+	bs.BotTokenHash = secret.Hash(bs.BotAccessToken)
+
+	workSourceToken, err := m.keyring.Encrypt(bs.WorkSourceToken)
+	if err != nil {
+		return bs, err
+	}
+
+	id, err := m.insert(
 		`INSERT INTO workspaces (
 			created_at,
 			notifier_interval, 
@@ -24,30 +43,37 @@ func (m *DB) CreateWorkspace(bs model.Workspace) (model.Workspace, error) {
 			projects_reports_enabled, 
 			reporting_channel, 
 			reporting_time, 
-			language
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			language,
+			platform,
+			bot_token_hash,
+			work_source,
+			work_source_url,
+			work_source_token,
+			scoring_rules
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		bs.CreatedAt,
 		bs.NotifierInterval,
 		bs.MaxReminders,
 		bs.ReminderOffset,
 		bs.WorkspaceID,
 		bs.WorkspaceName,
-		bs.BotAccessToken,
+		token,
 		bs.BotUserID,
 		bs.ProjectsReportsEnabled,
 		bs.ReportingChannel,
 		bs.ReportingTime,
 		bs.Language,
+		bs.Platform,
+		bs.BotTokenHash,
+		bs.WorkSource,
+		bs.WorkSourceURL,
+		workSourceToken,
+		bs.ScoringRules,
 	)
 	if err != nil {
 		return bs, err
 	}
 
-	id, err := res.LastInsertId()
-	if err != nil {
-		return bs, err
-	}
-
 	bs.ID = id
 
 	return bs, nil
@@ -60,7 +86,22 @@ func (m *DB) UpdateWorkspace(settings model.Workspace) (model.Workspace, error)
 		return settings, err
 	}
 
-	_, err = m.db.Exec(
+	if settings.Platform == "" {
+		settings.Platform = model.PlatformSlack
+	}
+
+	token, err := m.keyring.Encrypt(settings.BotAccessToken)
+	if err != nil {
+		return settings, err
+	}
+	settings.BotTokenHash = secret.Hash(settings.BotAccessToken)
+
+	workSourceToken, err := m.keyring.Encrypt(settings.WorkSourceToken)
+	if err != nil {
+		return settings, err
+	}
+
+	_, err = m.exec(
 		`UPDATE workspaces set 
 			notifier_interval=?, 
 			max_reminders=?, 
@@ -72,75 +113,196 @@ func (m *DB) UpdateWorkspace(settings model.Workspace) (model.Workspace, error)
 			projects_reports_enabled=?, 
 			reporting_channel=?, 
 			reporting_time=?, 
-			language=?
+			language=?,
+			platform=?,
+			bot_token_hash=?,
+			work_source=?,
+			work_source_url=?,
+			work_source_token=?,
+			scoring_rules=?
 			where id=?`,
 		settings.NotifierInterval,
 		settings.MaxReminders,
 		settings.WorkspaceID,
 		settings.ReminderOffset,
 		settings.WorkspaceName,
-		settings.BotAccessToken,
+		token,
 		settings.BotUserID,
 		settings.ProjectsReportsEnabled,
 		settings.ReportingChannel,
 		settings.ReportingTime,
 		settings.Language,
+		settings.Platform,
+		settings.BotTokenHash,
+		settings.WorkSource,
+		settings.WorkSourceURL,
+		workSourceToken,
+		settings.ScoringRules,
 		settings.ID,
 	)
 	if err != nil {
 		return settings, err
 	}
-	return settings, nil
+	return m.GetWorkspace(settings.ID)
 }
 
 //GetAllWorkspaces returns all workspaces stored in DB
 func (m *DB) GetAllWorkspaces() ([]model.Workspace, error) {
 	bs := []model.Workspace{}
-	err := m.db.Select(&bs, "SELECT * FROM `workspaces`")
+	err := m.list(&bs, "SELECT * FROM workspaces")
 	if err != nil {
 		return bs, err
 	}
+	for i := range bs {
+		err = m.decryptToken(&bs[i])
+		if err != nil {
+			return bs, err
+		}
+	}
 	return bs, nil
 }
 
 //GetWorkspaceByWorkspaceID returns a particular bot
 func (m *DB) GetWorkspaceByWorkspaceID(workspaceID string) (model.Workspace, error) {
 	bs := model.Workspace{}
-	err := m.db.Get(&bs, "SELECT * FROM `workspaces` where workspace_id=?", workspaceID)
+	err := m.get(&bs, "SELECT * FROM workspaces where workspace_id=?", workspaceID)
 	if err != nil {
 		return bs, err
 	}
-	return bs, nil
+	return bs, m.decryptToken(&bs)
 }
 
-//GetWorkspaceByBotAccessToken returns a particular bot
+//GetWorkspaceByBotAccessToken returns a particular bot, token is looked up by its hash
 func (m *DB) GetWorkspaceByBotAccessToken(botAccessToken string) (model.Workspace, error) {
 	bs := model.Workspace{}
-	err := m.db.Get(&bs, "SELECT * FROM `workspaces` where bot_access_token=?", botAccessToken)
+	err := m.get(&bs, "SELECT * FROM workspaces where bot_token_hash=?", secret.Hash(botAccessToken))
 	if err != nil {
 		return bs, err
 	}
-	return bs, nil
+	return bs, m.decryptToken(&bs)
 }
 
 //GetWorkspace returns a particular bot
 func (m *DB) GetWorkspace(id int64) (model.Workspace, error) {
 	bs := model.Workspace{}
-	err := m.db.Get(&bs, "SELECT * FROM `workspaces` where id=?", id)
+	err := m.get(&bs, "SELECT * FROM workspaces where id=?", id)
 	if err != nil {
 		return bs, err
 	}
-	return bs, nil
+	return bs, m.decryptToken(&bs)
 }
 
 //DeleteWorkspaceByID deletes bot
 func (m *DB) DeleteWorkspaceByID(id int64) error {
-	_, err := m.db.Exec("DELETE FROM `workspaces` WHERE id=?", id)
+	_, err := m.exec("DELETE FROM workspaces WHERE id=?", id)
 	return err
 }
 
 //DeleteWorkspace deletes bot
 func (m *DB) DeleteWorkspace(teamID string) error {
-	_, err := m.db.Exec("DELETE FROM `workspaces` WHERE workspace_id=?", teamID)
+	_, err := m.exec("DELETE FROM workspaces WHERE workspace_id=?", teamID)
 	return err
 }
+
+func (m *DB) decryptToken(bs *model.Workspace) error {
+	token, err := m.keyring.Decrypt(bs.BotAccessToken)
+	if err != nil {
+		return err
+	}
+	bs.BotAccessToken = token
+
+	workSourceToken, err := m.keyring.Decrypt(bs.WorkSourceToken)
+	if err != nil {
+		return err
+	}
+	bs.WorkSourceToken = workSourceToken
+	return nil
+}
+
+// rewriteTokens stores bot access tokens encrypted with the primary key, or as plaintext
+// if decrypt is set, and fills missing lookup hashes. Returns number of updated workspaces
+func (m *DB) rewriteTokens(decrypt bool) (int, error) {
+	rows := []model.Workspace{}
+	err := m.list(&rows, "SELECT id, bot_access_token, bot_token_hash FROM workspaces")
+	if err != nil {
+		return 0, err
+	}
+
+	updated := 0
+	for _, row := range rows {
+		token, err := m.keyring.Decrypt(row.BotAccessToken)
+		if err != nil {
+			return updated, fmt.Errorf("workspace %v: %v", row.ID, err)
+		}
+
+		stored := token
+		if decrypt {
+			if !secret.IsEncrypted(row.BotAccessToken) && row.BotTokenHash == secret.Hash(token) {
+				continue
+			}
+		} else {
+			if !m.keyring.NeedsRewrite(row.BotAccessToken) && row.BotTokenHash == secret.Hash(token) {
+				continue
+			}
+			stored, err = m.keyring.Encrypt(token)
+			if err != nil {
+				return updated, err
+			}
+		}
+
+		_, err = m.exec("UPDATE workspaces SET bot_access_token=?, bot_token_hash=? WHERE id=?", stored, secret.Hash(token), row.ID)
+		if err != nil {
+			return updated, err
+		}
+		updated++
+	}
+
+	if updated > 0 {
+		log.Infof("rewrote bot access tokens of %d workspaces", updated)
+	}
+	return updated, nil
+}
+
+// rewriteWorkSourceTokens stores work source tokens encrypted with the primary key,
+// or as plaintext if decrypt is set. Returns number of updated workspaces
+func (m *DB) rewriteWorkSourceTokens(decrypt bool) (int, error) {
+	rows := []model.Workspace{}
+	err := m.list(&rows, "SELECT id, work_source_token FROM workspaces")
+	if err != nil {
+		return 0, err
+	}
+
+	updated := 0
+	for _, row := range rows {
+		token, err := m.keyring.Decrypt(row.WorkSourceToken)
+		if err != nil {
+			return updated, fmt.Errorf("workspace %v: %v", row.ID, err)
+		}
+
+		stored := token
+		if decrypt {
+			if !secret.IsEncrypted(row.WorkSourceToken) {
+				continue
+			}
+		} else {
+			if !m.keyring.NeedsRewrite(row.WorkSourceToken) {
+				continue
+			}
+			stored, err = m.keyring.Encrypt(token)
+			if err != nil {
+				return updated, err
+			}
+		}
+
+		_, err = m.exec("UPDATE workspaces SET work_source_token=? WHERE id=?", stored, row.ID)
+		if err != nil {
+			return updated, err
+		}
+		updated++
+	}
+
+	if updated > 0 {
+		log.Infof("rewrote work source tokens of %d workspaces", updated)
+	}
+	return updated, nil
+}
@@ -67,6 +67,14 @@ func TestWorkspace(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "WorkspaceID", bot.WorkspaceID)
 
+	bot, err = db.GetWorkspaceByBotAccessToken("token")
+	assert.NoError(t, err)
+	assert.Equal(t, "WorkspaceID", bot.WorkspaceID)
+	assert.Equal(t, "token", bot.BotAccessToken)
+
+	_, err = db.GetWorkspaceByBotAccessToken("wrongToken")
+	assert.Error(t, err)
+
 	bot, err = db.GetWorkspaceByWorkspaceID("teamWrongID")
 	assert.Error(t, err)
 
@@ -99,5 +107,29 @@ func TestUpdateAndDeleteWorkspace(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "ru_RU", bot.Language)
 
+	bot.WorkSource = model.WorkSourceJira
+	bot.WorkSourceURL = "https://example.atlassian.net"
+	bot.WorkSourceToken = "me@example.com:apitoken"
+	_, err = db.UpdateWorkspace(bot)
+	assert.NoError(t, err)
+
+	bot, err = db.GetWorkspace(bot.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, model.WorkSourceJira, bot.WorkSource)
+	assert.Equal(t, "https://example.atlassian.net", bot.WorkSourceURL)
+	assert.Equal(t, "me@example.com:apitoken", bot.WorkSourceToken)
+	assert.Nil(t, bot.ScoringRules)
+
+	rules := model.ScoringRules{
+		{Role: "designer", SkipCommits: true, DailyWorklogs: []model.ScoreLevel{{Min: 0, Emoji: ":coffee:"}, {Min: 4, Emoji: ":wink:", Good: true}}},
+	}
+	bot.ScoringRules = rules
+	_, err = db.UpdateWorkspace(bot)
+	assert.NoError(t, err)
+
+	bot, err = db.GetWorkspace(bot.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, rules, bot.ScoringRules)
+
 	assert.NoError(t, db.DeleteWorkspace(bot.WorkspaceID))
 }
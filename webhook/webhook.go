@@ -0,0 +1,122 @@
+// Package webhook signs and sends events of workspaces to subscribed HTTP endpoints
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/maddevsio/comedian/model"
+)
+
+// Headers sent with every delivery. Receivers verify signature by computing
+// HMAC-SHA256 of "<timestamp>.<body>" with the webhook secret
+const (
+	EventHeader     = "X-Comedian-Event"
+	DeliveryHeader  = "X-Comedian-Delivery"
+	TimestampHeader = "X-Comedian-Timestamp"
+	SignatureHeader = "X-Comedian-Signature"
+)
+
+// MaxAttempts is how many times delivery is attempted before it is marked failed
+const MaxAttempts = 8
+
+const (
+	firstRetry = time.Minute
+	maxRetry   = time.Hour
+	// maxError limits length of error stored in delivery log
+	maxError = 255
+)
+
+// Sign returns signature of the body sent at timestamp
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return "v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Backoff returns delay before next attempt of delivery that failed attempts times
+func Backoff(attempts int) time.Duration {
+	delay := firstRetry
+	for i := 1; i < attempts && delay < maxRetry; i++ {
+		delay *= 2
+	}
+	if delay > maxRetry {
+		delay = maxRetry
+	}
+	return delay
+}
+
+// Sender posts deliveries to webhooks
+type Sender struct {
+	client *http.Client
+}
+
+// NewSender creates Sender giving up on requests lasting longer than timeout
+func NewSender(timeout time.Duration) *Sender {
+	return &Sender{client: &http.Client{Timeout: timeout}}
+}
+
+// Deliver attempts delivery and returns it with result recorded: delivered on 2xx
+// response, otherwise pending with next attempt scheduled or failed after MaxAttempts
+func (s *Sender) Deliver(w model.Webhook, d model.WebhookDelivery, now time.Time) model.WebhookDelivery {
+	d.Attempts++
+	d.LastAttemptAt = now.Unix()
+
+	code, err := s.send(w, d, now)
+	d.ResponseCode = code
+	d.Error = ""
+	if err == nil {
+		d.Status = model.DeliveryDelivered
+		return d
+	}
+
+	d.Error = err.Error()
+	if len(d.Error) > maxError {
+		d.Error = d.Error[:maxError]
+	}
+	if d.Attempts >= MaxAttempts {
+		d.Status = model.DeliveryFailed
+		return d
+	}
+	d.Status = model.DeliveryPending
+	d.NextAttemptAt = now.Add(Backoff(d.Attempts)).Unix()
+	return d
+}
+
+func (s *Sender) send(w model.Webhook, d model.WebhookDelivery, now time.Time) (int, error) {
+	body := []byte(d.Payload)
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+
+	req, err := http.NewRequest(http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Comedian-Webhook")
+	req.Header.Set(EventHeader, d.Event)
+	req.Header.Set(DeliveryHeader, strconv.FormatInt(d.ID, 10))
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, Sign(w.Secret, timestamp, body))
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	// drain body so connection is reused
+	io.Copy(ioutil.Discard, io.LimitReader(res.Body, 64<<10))
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res.StatusCode, fmt.Errorf("unexpected response status: %v", res.Status)
+	}
+	return res.StatusCode, nil
+}
@@ -0,0 +1,76 @@
+package webhook
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/maddevsio/comedian/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, time.Minute, Backoff(1))
+	assert.Equal(t, 2*time.Minute, Backoff(2))
+	assert.Equal(t, 32*time.Minute, Backoff(6))
+	assert.Equal(t, time.Hour, Backoff(7))
+	assert.Equal(t, time.Hour, Backoff(100))
+}
+
+func TestDeliver(t *testing.T) {
+	status := http.StatusOK
+	var received *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = ioutil.ReadAll(r.Body)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	hook := model.Webhook{ID: 1, WorkspaceID: "T1", URL: server.URL, Secret: "secret", Enabled: true}
+	delivery := model.WebhookDelivery{
+		ID:          7,
+		WorkspaceID: "T1",
+		WebhookID:   1,
+		Event:       model.EventStandupCreated,
+		Payload:     `{"event":"standup.created"}`,
+		Status:      model.DeliveryPending,
+	}
+	now := time.Unix(1000, 0)
+	sender := NewSender(time.Second)
+
+	d := sender.Deliver(hook, delivery, now)
+	assert.Equal(t, model.DeliveryDelivered, d.Status)
+	assert.Equal(t, 1, d.Attempts)
+	assert.Equal(t, 200, d.ResponseCode)
+	assert.Equal(t, int64(1000), d.LastAttemptAt)
+	require.NotNil(t, received)
+	assert.Equal(t, delivery.Payload, string(body))
+	assert.Equal(t, "standup.created", received.Header.Get(EventHeader))
+	assert.Equal(t, "7", received.Header.Get(DeliveryHeader))
+	assert.Equal(t, "1000", received.Header.Get(TimestampHeader))
+	assert.Equal(t, Sign("secret", "1000", body), received.Header.Get(SignatureHeader))
+	assert.NotEqual(t, Sign("other", "1000", body), received.Header.Get(SignatureHeader))
+
+	status = http.StatusInternalServerError
+	d = sender.Deliver(hook, delivery, now)
+	assert.Equal(t, model.DeliveryPending, d.Status)
+	assert.Equal(t, 500, d.ResponseCode)
+	assert.Equal(t, int64(1060), d.NextAttemptAt)
+	assert.Contains(t, d.Error, "500")
+
+	delivery.Attempts = MaxAttempts - 1
+	d = sender.Deliver(hook, delivery, now)
+	assert.Equal(t, model.DeliveryFailed, d.Status)
+
+	hook.URL = "http://127.0.0.1:1"
+	delivery.Attempts = 0
+	d = sender.Deliver(hook, delivery, now)
+	assert.Equal(t, model.DeliveryPending, d.Status)
+	assert.Equal(t, 0, d.ResponseCode)
+	assert.NotEmpty(t, d.Error)
+}